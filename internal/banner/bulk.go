@@ -0,0 +1,344 @@
+package banner
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// csvColumns are the columns of the CSV export in order.
+// Nested fields are written as JSON.
+var csvColumns = []string{
+	"banner_id",
+	"feature_id",
+	"feature_name",
+	"tag_ids",
+	"tag_names",
+	"content",
+	"localized_content",
+	"is_active",
+	"is_deleted",
+	"priority",
+	"frequency_cap",
+	"active_from",
+	"active_until",
+	"created_at",
+	"updated_at",
+}
+
+// Kinds of the CSV columns on import.
+const (
+	columnJSON = iota
+	columnTime
+	columnIgnored
+)
+
+var csvImportColumns = map[string]int{
+	"banner_id":         columnIgnored,
+	"feature_id":        columnJSON,
+	"feature_name":      columnIgnored,
+	"tag_ids":           columnJSON,
+	"tag_names":         columnIgnored,
+	"content":           columnJSON,
+	"localized_content": columnJSON,
+	"is_active":         columnJSON,
+	"is_deleted":        columnIgnored,
+	"priority":          columnJSON,
+	"frequency_cap":     columnJSON,
+	"active_from":       columnTime,
+	"active_until":      columnTime,
+	"created_at":        columnIgnored,
+	"updated_at":        columnIgnored,
+}
+
+// exportWriter streams exported banners to the response.
+// Nothing is written until the first page, so that a failure
+// to read it can still be reported with a proper status.
+type exportWriter struct {
+	w       http.ResponseWriter
+	csv     *csv.Writer
+	json    *json.Encoder
+	started bool
+}
+
+func newExportWriter(w http.ResponseWriter, format GetBannerExportParamsFormat) (*exportWriter, error) {
+	e := &exportWriter{w: w}
+
+	switch format {
+	case GetBannerExportParamsFormatCsv:
+		e.csv = csv.NewWriter(w)
+	case GetBannerExportParamsFormatNdjson:
+		e.json = json.NewEncoder(w)
+	default:
+		return nil, &InvalidParamFormatError{
+			ParamName: "format",
+			Err:       fmt.Errorf("unknown format %q", format),
+		}
+	}
+
+	return e, nil
+}
+
+func (e *exportWriter) start() error {
+	if e.started {
+		return nil
+	}
+	e.started = true
+
+	if e.csv != nil {
+		e.w.Header().Set("Content-Type", "text/csv")
+		e.w.Header().Set("Content-Disposition", `attachment; filename="banners.csv"`)
+		return e.csv.Write(csvColumns)
+	}
+
+	e.w.Header().Set("Content-Type", "application/x-ndjson")
+	e.w.Header().Set("Content-Disposition", `attachment; filename="banners.ndjson"`)
+	return nil
+}
+
+// write writes the page of banners and flushes it to the client.
+func (e *exportWriter) write(page []GetBannerResponse) error {
+	if err := e.start(); err != nil {
+		return err
+	}
+
+	for _, b := range page {
+		if e.csv == nil {
+			if err := e.json.Encode(b); err != nil {
+				return err
+			}
+			continue
+		}
+
+		record, err := csvRecord(b)
+		if err != nil {
+			return err
+		}
+		if err = e.csv.Write(record); err != nil {
+			return err
+		}
+	}
+
+	return e.flush()
+}
+
+// finish completes the export, an empty one included.
+func (e *exportWriter) finish() error {
+	if err := e.start(); err != nil {
+		return err
+	}
+
+	return e.flush()
+}
+
+func (e *exportWriter) flush() error {
+	if e.csv != nil {
+		e.csv.Flush()
+		if err := e.csv.Error(); err != nil {
+			return err
+		}
+	}
+
+	err := http.NewResponseController(e.w).Flush()
+	if err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+
+	return nil
+}
+
+// csvRecord formats the banner as a row of the CSV export.
+func csvRecord(b GetBannerResponse) ([]string, error) {
+	tags, err := json.Marshal(b.TagIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	tagNames, err := json.Marshal(b.TagNames)
+	if err != nil {
+		return nil, err
+	}
+
+	frequencyCap := ""
+	if b.FrequencyCap != nil {
+		frequencyCap = strconv.Itoa(*b.FrequencyCap)
+	}
+
+	return []string{
+		strconv.Itoa(b.BannerID),
+		strconv.Itoa(b.FeatureID),
+		b.FeatureName,
+		string(tags),
+		string(tagNames),
+		string(b.Content),
+		string(b.LocalizedContent),
+		strconv.FormatBool(b.IsActive),
+		strconv.FormatBool(b.IsDeleted),
+		strconv.Itoa(b.Priority),
+		frequencyCap,
+		formatTime(b.ActiveFrom),
+		formatTime(b.ActiveUntil),
+		formatTime(&b.CreatedAt),
+		formatTime(&b.UpdatedAt),
+	}, nil
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+// rowReader reads rows of the imported file as bodies of banner creation.
+// It returns io.EOF after the last row.
+type rowReader interface {
+	next() (json.RawMessage, error)
+}
+
+func newRowReader(format PostBannerImportParamsFormat, r io.Reader) (rowReader, error) {
+	switch format {
+	case PostBannerImportParamsFormatCsv:
+		return newCSVReader(r)
+	case PostBannerImportParamsFormatNdjson:
+		return &ndjsonReader{r: bufio.NewReader(r)}, nil
+	default:
+		return nil, &InvalidParamFormatError{
+			ParamName: "format",
+			Err:       fmt.Errorf("unknown format %q", format),
+		}
+	}
+}
+
+type ndjsonReader struct {
+	r *bufio.Reader
+}
+
+func (n *ndjsonReader) next() (json.RawMessage, error) {
+	for {
+		line, err := n.r.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, readError(err)
+		}
+
+		// blank lines are not rows
+		line = bytes.TrimSpace(line)
+		if len(line) > 0 {
+			return line, nil
+		}
+
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+	}
+}
+
+type csvReader struct {
+	r      *csv.Reader
+	header []string
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	c := &csvReader{r: csv.NewReader(r)}
+
+	header, err := c.r.Read()
+	if err == io.EOF {
+		return nil, &InvalidFileError{Reason: "no header"}
+	}
+	if err != nil {
+		return nil, readError(err)
+	}
+
+	seen := make(map[string]struct{}, len(header))
+	for _, column := range header {
+		if _, ok := csvImportColumns[column]; !ok {
+			return nil, &InvalidFileError{Reason: fmt.Sprintf("unknown column %q", column)}
+		}
+		if _, ok := seen[column]; ok {
+			return nil, &InvalidFileError{Reason: fmt.Sprintf("repeated column %q", column)}
+		}
+		seen[column] = struct{}{}
+	}
+	c.header = header
+
+	return c, nil
+}
+
+func (c *csvReader) next() (json.RawMessage, error) {
+	record, err := c.r.Read()
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+	if err != nil {
+		return nil, readError(err)
+	}
+
+	// empty cells stand for omitted fields
+	fields := make(map[string]json.RawMessage, len(record))
+	for i, cell := range record {
+		column := c.header[i]
+		if cell == "" {
+			continue
+		}
+
+		switch csvImportColumns[column] {
+		case columnJSON:
+			if !json.Valid([]byte(cell)) {
+				return nil, &InvalidColumnError{Column: column}
+			}
+			fields[column] = json.RawMessage(cell)
+		case columnTime:
+			fields[column], _ = json.Marshal(cell)
+		}
+	}
+
+	return json.Marshal(fields)
+}
+
+// isRowError reports whether the error is caused by the content of a single row
+// and does not prevent checking the rest of the file.
+// readError describes the failure to read the imported file.
+func readError(err error) error {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return &ImportTooLargeError{Reason: fmt.Sprintf("file larger than %d bytes", tooLarge.Limit)}
+	}
+
+	return &InvalidFileError{Reason: err.Error()}
+}
+
+func isRowError(err error) bool {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var timeErr *time.ParseError
+
+	switch err.(type) {
+	case *MissingFieldError, *InvalidColumnError, *InvalidContentError,
+		*InvalidScheduleError, *InvalidLocaleError, *InvalidFrequencyCapError,
+		*RepeatedExternalIDError, *InvalidFeatureError, *InvalidTagError:
+		return true
+	}
+
+	return errors.As(err, &syntaxErr) || errors.As(err, &typeErr) || errors.As(err, &timeErr)
+}
+
+// newRowError describes the failure of the row numbered from 1.
+func newRowError(row int, err error) RowError {
+	rowErr := RowError{Row: row, Error: err.Error()}
+
+	switch e := err.(type) {
+	case *InvalidContentError:
+		rowErr.Fields = &e.Fields
+	case *ConflictingBannersError:
+		rowErr.BannerIds = &e.BannerIDs
+	}
+
+	return rowErr
+}
@@ -0,0 +1,358 @@
+package banner
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// invalidationChannel is the Redis channel cache invalidations are broadcast on,
+// so that every replica drops the entries from its in-process caches as well.
+const invalidationChannel = "banner:cache-invalidation"
+
+// invalidationBufferLength is how many invalidations may wait for the worker,
+// past that the mutations drop the entries themselves.
+const invalidationBufferLength = 100
+
+// minResubscribeDelay and maxResubscribeDelay bound the backoff
+// between the attempts to subscribe to the invalidations.
+const (
+	minResubscribeDelay = time.Second
+	maxResubscribeDelay = time.Minute
+)
+
+// cacheInvalidation is the broadcast message listing the keys of the dropped entries.
+type cacheInvalidation struct {
+	Keys []string `json:"keys"`
+}
+
+// Every cached entry is registered in index sets as it is written, so that
+// a mutation drops exactly the entries it affects without scanning the keys.
+
+// slotIndex is the index set of the slot entries of the feature.
+func slotIndex(featureID int) string {
+	return fmt.Sprintf("cache_index:feature_id:%d", featureID)
+}
+
+// tagSlotIndex is the index set of the slot entries of the feature
+// for every set of user tags containing the tag.
+func tagSlotIndex(featureID, tag int) string {
+	return fmt.Sprintf("cache_index:feature_id:%d-tag_id:%d", featureID, tag)
+}
+
+// experimentIndex is the index set of the experiment entries of the feature.
+func experimentIndex(featureID int) string {
+	return fmt.Sprintf("cache_index:experiment:feature_id:%d", featureID)
+}
+
+// bannerIndex is the index set of the entries of the banner as a variant of an experiment.
+func bannerIndex(id int) string {
+	return fmt.Sprintf("cache_index:banner_id:%d", id)
+}
+
+// slotIndexes lists the index sets the slot entry of the feature
+// and the set of user tags is registered in.
+func slotIndexes(featureID int, tagIDs []int) []string {
+	indexes := []string{slotIndex(featureID)}
+
+	for _, tag := range uniqueTags(tagIDs) {
+		indexes = append(indexes, tagSlotIndex(featureID, tag))
+	}
+
+	return indexes
+}
+
+// bannerIndexes lists the index sets of the entries the banner is served
+// under with the feature and the tags: slots of the feature for every set
+// of user tags containing any of the tags, every slot of the feature if the
+// banner is the fallback of the feature, and the banner as a variant of an experiment.
+func bannerIndexes(id, featureID int, tags []int, fallback bool) []string {
+	indexes := []string{bannerIndex(id)}
+
+	if fallback {
+		return append(indexes, slotIndex(featureID))
+	}
+
+	for _, tag := range uniqueTags(tags) {
+		indexes = append(indexes, tagSlotIndex(featureID, tag))
+	}
+
+	return indexes
+}
+
+// cacheIndexes lists the index sets of the entries of the banner in its current placement.
+func cacheIndexes(ctx context.Context, q *Queries, id int) ([]string, error) {
+	banner, err := q.GetBannerByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := liveTags(ctx, q, id)
+	if err != nil {
+		return nil, err
+	}
+
+	fallback, err := q.GetFeatureFallback(ctx, banner.FeatureID)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	isFallback := err == nil && fallback.BannerID == id
+
+	return bannerIndexes(id, banner.FeatureID, tags, isFallback), nil
+}
+
+// bannersIndexes lists the index sets of the entries of the banners in their current placement.
+func bannersIndexes(ctx context.Context, q *Queries, ids []int) ([]string, error) {
+	indexes := make([]string, 0)
+
+	for _, id := range ids {
+		i, err := cacheIndexes(ctx, q, id)
+		if err != nil {
+			return nil, err
+		}
+		indexes = append(indexes, i...)
+	}
+
+	return indexes, nil
+}
+
+// deletedIndexes lists the index sets of the entries of the deleted banner
+// given its tags as returned by the deletion.
+func deletedIndexes(id, featureID int, tagIDs json.RawMessage, fallback bool) ([]string, error) {
+	tags := make([]int, 0)
+	if err := json.Unmarshal(tagIDs, &tags); err != nil {
+		return nil, err
+	}
+
+	return bannerIndexes(id, featureID, tags, fallback), nil
+}
+
+// cacheEntry writes the entry and registers it in the index sets at once.
+func (r *repository) cacheEntry(ctx context.Context, key string, value []byte, expiration time.Duration, indexes ...string) error {
+	pipe := r.rdb.TxPipeline()
+	pipe.Set(ctx, key, value, expiration)
+	for _, index := range indexes {
+		pipe.SAdd(ctx, index, key)
+		// no entry outlives cache_expiration, so neither does the index
+		// once nothing is registered in it anymore
+		pipe.Expire(ctx, index, r.config.CacheExpiration)
+	}
+
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// invalidate queues the entries registered in the index sets to be dropped
+// by RunInvalidations once the change is committed, off the path of the request.
+// Should the queue be full, the entries are dropped right away.
+func (r *repository) invalidate(ctx context.Context, indexes ...string) {
+	if len(indexes) == 0 {
+		return
+	}
+
+	// banners sharing a tag share the index sets
+	indexes = slices.Clone(indexes)
+	slices.Sort(indexes)
+	indexes = slices.Compact(indexes)
+
+	select {
+	case r.invalidations <- indexes:
+	default:
+		r.dropIndexed(ctx, indexes)
+	}
+}
+
+// RunInvalidations drops the queued entries until the context is done,
+// and the ones still queued by then.
+func (r *repository) RunInvalidations(ctx context.Context) {
+	// the entries are dropped even if the context is done meanwhile
+	bg := context.Background()
+
+	for {
+		select {
+		case indexes := <-r.invalidations:
+			r.dropIndexed(bg, indexes)
+
+		case <-ctx.Done():
+			for {
+				select {
+				case indexes := <-r.invalidations:
+					r.dropIndexed(bg, indexes)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// dropIndexed drops the entries registered in the index sets and tells every
+// replica about them. A failure is only logged: the entries expire on their own.
+func (r *repository) dropIndexed(ctx context.Context, indexes []string) {
+	pipe := r.rdb.Pipeline()
+	members := make([]*redis.StringSliceCmd, 0, len(indexes))
+	for _, index := range indexes {
+		members = append(members, pipe.SMembers(ctx, index))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		r.logger.Error(err)
+		return
+	}
+
+	keys := make([]string, 0)
+	pipe = r.rdb.TxPipeline()
+	for i, index := range indexes {
+		registered := members[i].Val()
+		if len(registered) == 0 {
+			continue
+		}
+		keys = append(keys, registered...)
+
+		// only the entries read are unregistered,
+		// the ones written meanwhile stay in the index
+		unregistered := make([]interface{}, 0, len(registered))
+		for _, key := range registered {
+			unregistered = append(unregistered, key)
+		}
+		pipe.SRem(ctx, index, unregistered...)
+	}
+
+	if len(keys) == 0 {
+		return
+	}
+
+	slices.Sort(keys)
+	keys = slices.Compact(keys)
+
+	pipe.Del(ctx, keys...)
+	if _, err := pipe.Exec(ctx); err != nil {
+		r.logger.Error(err)
+		return
+	}
+
+	r.broadcastInvalidation(ctx, keys)
+}
+
+// broadcastInvalidation tells every replica the entries are dropped.
+// A failure is only logged: the entries expire on their own.
+func (r *repository) broadcastInvalidation(ctx context.Context, keys []string) {
+	msg, err := json.Marshal(cacheInvalidation{Keys: keys})
+	if err != nil {
+		r.logger.Error(err)
+		return
+	}
+
+	if err = r.rdb.Publish(ctx, invalidationChannel, msg).Err(); err != nil {
+		r.logger.Error(err)
+	}
+}
+
+// SubscribeInvalidations calls purge with the keys of every cache
+// invalidation broadcast by any replica, this one included, until
+// the context is done.
+func (r *repository) SubscribeInvalidations(ctx context.Context, purge func(keys []string)) error {
+	sub := r.rdb.Subscribe(ctx, invalidationChannel)
+	defer func() {
+		if err := sub.Close(); err != nil {
+			r.logger.Error(err)
+		}
+	}()
+
+	// the subscription is confirmed before any message is received
+	if _, err := sub.Receive(ctx); err != nil {
+		return err
+	}
+
+	messages := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case msg, ok := <-messages:
+			if !ok {
+				return nil
+			}
+
+			var invalidation cacheInvalidation
+			if err := json.Unmarshal([]byte(msg.Payload), &invalidation); err != nil {
+				r.logger.Errorf("cache invalidation: %v", err)
+				continue
+			}
+
+			purge(invalidation.Keys)
+		}
+	}
+}
+
+// flushInvalidations drops the cached entries of the committed changes
+// until the service is stopped.
+func (s *BannerService) flushInvalidations() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-s.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	s.repo.RunInvalidations(ctx)
+}
+
+// listenInvalidations purges the in-process caches of the service on every
+// cache invalidation broadcast until the service is stopped. A lost
+// subscription is retried with backoff.
+func (s *BannerService) listenInvalidations() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-s.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	delay := minResubscribeDelay
+	for {
+		started := time.Now()
+
+		err := s.repo.SubscribeInvalidations(ctx, s.purgeLocal)
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			err = errors.New("subscription closed")
+		}
+
+		// a subscription that held for a while starts the backoff over
+		if time.Since(started) > maxResubscribeDelay {
+			delay = minResubscribeDelay
+		}
+
+		s.logger.Errorf("subscribe to cache invalidations: %v, retrying in %s", err, delay)
+
+		select {
+		case <-s.done:
+			return
+		case <-time.After(delay):
+		}
+
+		delay = min(2*delay, maxResubscribeDelay)
+	}
+}
+
+// purgeLocal drops the entries with the keys from the in-process
+// caches. Banners are only cached in Redis so far, which the replica
+// that changed them has already dropped the entries from.
+func (s *BannerService) purgeLocal(keys []string) {
+	s.logger.Debugf("cache invalidation: %d key(s)", len(keys))
+}
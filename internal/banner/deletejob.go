@@ -0,0 +1,129 @@
+package banner
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// deleteJobLease is how long a claimed delete job stays with the worker
+// before a worker of any replica may take it over, e.g. after a crash.
+const deleteJobLease = time.Minute
+
+// DeleteJobClaim is the delete job claimed by the worker for the lease.
+type DeleteJobClaim struct {
+	JobID   uuid.UUID
+	ClaimID uuid.UUID
+	Lease   time.Duration
+	// BannerIDs are the banners to delete unless
+	// the job deletes by the feature and/or the tag.
+	BannerIDs []int
+	FeatureID *int
+	TagID     *int
+}
+
+// deleteJobResults returns the status of the finished job and the outcome
+// for each of its banners given the deleted ones. A failure to delete
+// fails all the banners, as they are deleted in a single transaction.
+func deleteJobResults(ids, deleted []int, err error) (DeleteJobStatus, []DeleteJobResult) {
+	found := make(map[int]struct{}, len(deleted))
+	for _, id := range deleted {
+		found[id] = struct{}{}
+	}
+
+	status := DeleteJobStatusDone
+	if err != nil {
+		status = DeleteJobStatusFailed
+	}
+
+	results := make([]DeleteJobResult, 0, len(ids))
+	for _, id := range ids {
+		result := DeleteJobResult{BannerId: id}
+
+		switch _, ok := found[id]; {
+		case err != nil:
+			reason := err.Error()
+			result.Outcome = DeleteJobResultOutcomeError
+			result.Error = &reason
+		case ok:
+			result.Outcome = DeleteJobResultOutcomeDeleted
+		default:
+			result.Outcome = DeleteJobResultOutcomeNotFound
+		}
+
+		results = append(results, result)
+	}
+
+	return status, results
+}
+
+// runDeleteJobs claims the stored delete jobs one by one and runs them
+// until none is left. Stopping the service lets the claimed job of banner
+// ids finish, while the job by feature or tag stops after its current chunk.
+func (s *BannerService) runDeleteJobs() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-s.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	for {
+		claim, err := s.repo.ClaimDeleteJob(ctx, deleteJobLease)
+		if err != nil {
+			if err != sql.ErrNoRows && ctx.Err() == nil {
+				s.logger.Errorf("claim delete job: %v", err)
+			}
+			return
+		}
+
+		err = s.repo.RunDeleteJob(ctx, *claim)
+		switch {
+		case err == sql.ErrNoRows:
+			s.logger.Infof("delete job %s: claim lost", claim.JobID)
+		case errors.Is(err, context.Canceled):
+			s.logger.Infof("delete job %s: stopped, resumed once its lease expires", claim.JobID)
+		case err != nil:
+			s.logger.Errorf("delete job %s: %v", claim.JobID, err)
+		}
+
+		select {
+		case <-s.done:
+			return
+		default:
+		}
+	}
+}
+
+// flushDelete runs the stored delete jobs when a new one is accepted
+// and periodically, which also resumes the jobs left unfinished
+// by a previous run of this or another replica.
+func (s *BannerService) flushDelete() {
+	ticker := time.NewTicker(s.config.DeleteFlushInterval)
+	defer ticker.Stop()
+
+	s.runDeleteJobs()
+
+	for {
+		select {
+		case <-s.done:
+			return
+
+		case <-s.deleteChan:
+			s.runDeleteJobs()
+
+		case <-ticker.C:
+			s.runDeleteJobs()
+
+			if _, err := s.repo.PruneDeleteJobs(context.Background(), s.config.DeleteJobRetention); err != nil {
+				s.logger.Errorf("prune delete jobs: %v", err)
+			}
+		}
+	}
+}
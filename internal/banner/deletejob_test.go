@@ -0,0 +1,80 @@
+package banner
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDeleteJobResults(t *testing.T) {
+	tests := []struct {
+		name       string
+		ids        []int
+		deleted    []int
+		err        error
+		wantStatus DeleteJobStatus
+		want       map[int]DeleteJobResultOutcome
+	}{
+		{
+			name:       "all deleted",
+			ids:        []int{1, 2},
+			deleted:    []int{2, 1},
+			wantStatus: DeleteJobStatusDone,
+			want:       map[int]DeleteJobResultOutcome{1: DeleteJobResultOutcomeDeleted, 2: DeleteJobResultOutcomeDeleted},
+		},
+		{
+			name:       "some not found",
+			ids:        []int{1, 2, 3},
+			deleted:    []int{2},
+			wantStatus: DeleteJobStatusDone,
+			want: map[int]DeleteJobResultOutcome{
+				1: DeleteJobResultOutcomeNotFound,
+				2: DeleteJobResultOutcomeDeleted,
+				3: DeleteJobResultOutcomeNotFound,
+			},
+		},
+		{
+			name:       "nothing to delete",
+			wantStatus: DeleteJobStatusDone,
+			want:       map[int]DeleteJobResultOutcome{},
+		},
+		{
+			name:       "failure fails every banner",
+			ids:        []int{1, 2},
+			deleted:    []int{1},
+			err:        errors.New("connection reset"),
+			wantStatus: DeleteJobStatusFailed,
+			want:       map[int]DeleteJobResultOutcome{1: DeleteJobResultOutcomeError, 2: DeleteJobResultOutcomeError},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			status, results := deleteJobResults(tt.ids, tt.deleted, tt.err)
+			if status != tt.wantStatus {
+				t.Errorf("got status %s, want %s", status, tt.wantStatus)
+			}
+
+			if len(results) != len(tt.ids) {
+				t.Fatalf("got %d results, want %d", len(results), len(tt.ids))
+			}
+			for i, result := range results {
+				// results follow the order of the job
+				if result.BannerId != tt.ids[i] {
+					t.Errorf("result %d is of banner %d, want %d", i, result.BannerId, tt.ids[i])
+				}
+				if result.Outcome != tt.want[result.BannerId] {
+					t.Errorf("banner %d: got %s, want %s", result.BannerId, result.Outcome, tt.want[result.BannerId])
+				}
+
+				switch {
+				case tt.err != nil && (result.Error == nil || *result.Error != tt.err.Error()):
+					t.Errorf("banner %d: got error %v, want %q", result.BannerId, result.Error, tt.err)
+				case tt.err == nil && result.Error != nil:
+					t.Errorf("banner %d: got error %q, want none", result.BannerId, *result.Error)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package banner
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// DraftChange is the validated change of a draft:
+// either a patch of its banner or a new banner.
+type DraftChange struct {
+	Patch  *BannerPatch
+	Banner *PostBannerJSONBody
+}
+
+// prepareDraft validates the change of the draft. The banner may have changed
+// since the draft was created, so the draft is validated against its current
+// state once again.
+func (s *BannerService) prepareDraft(ctx context.Context, draft *Draft) (DraftChange, error) {
+	payload, err := json.Marshal(draft.Payload)
+	if err != nil {
+		return DraftChange{}, err
+	}
+
+	if draft.BannerId != nil {
+		p, err := s.preparePatch(ctx, *draft.BannerId, payload)
+		if err != nil {
+			return DraftChange{}, err
+		}
+
+		return DraftChange{Patch: p}, nil
+	}
+
+	data, err := s.prepareBanner(ctx, payload)
+	if err != nil {
+		return DraftChange{}, err
+	}
+
+	return DraftChange{Banner: data}, nil
+}
@@ -1,6 +1,9 @@
 package banner
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 type InvalidTypeError struct {
 	ParamName string
@@ -9,3 +12,218 @@ type InvalidTypeError struct {
 func (e *InvalidTypeError) Error() string {
 	return fmt.Sprintf("invalid type for parameter: %s", e.ParamName)
 }
+
+type InvalidSchemaError struct {
+	Err error
+}
+
+func (e *InvalidSchemaError) Error() string {
+	return fmt.Sprintf("invalid content schema: %v", e.Err)
+}
+
+func (e *InvalidSchemaError) Unwrap() error {
+	return e.Err
+}
+
+type InvalidContentError struct {
+	Fields []FieldError
+}
+
+func (e *InvalidContentError) Error() string {
+	return fmt.Sprintf("content does not match feature schema: %d invalid field(s)", len(e.Fields))
+}
+
+type ConflictingBannersError struct {
+	BannerIDs []int
+}
+
+func (e *ConflictingBannersError) Error() string {
+	return "another active banner is already shown for the feature and tag"
+}
+
+type InvalidScheduleError struct{}
+
+func (e *InvalidScheduleError) Error() string {
+	return "active_from must be before active_until"
+}
+
+type InvalidExperimentError struct {
+	Reason string
+}
+
+func (e *InvalidExperimentError) Error() string {
+	return fmt.Sprintf("invalid experiment: %s", e.Reason)
+}
+
+type NotDeletedError struct {
+	BannerID int
+}
+
+func (e *NotDeletedError) Error() string {
+	return fmt.Sprintf("banner %d is not deleted", e.BannerID)
+}
+
+type RunningExperimentError struct{}
+
+func (e *RunningExperimentError) Error() string {
+	return "an experiment is already running for the feature and tag"
+}
+
+type InvalidFallbackError struct {
+	Reason string
+}
+
+func (e *InvalidFallbackError) Error() string {
+	return fmt.Sprintf("invalid fallback banner: %s", e.Reason)
+}
+
+type InvalidLocaleError struct {
+	Locale string
+}
+
+func (e *InvalidLocaleError) Error() string {
+	return fmt.Sprintf("invalid locale: %q", e.Locale)
+}
+
+type InvalidFrequencyCapError struct {
+	FrequencyCap int
+}
+
+func (e *InvalidFrequencyCapError) Error() string {
+	return fmt.Sprintf("invalid frequency cap: %d, must be positive", e.FrequencyCap)
+}
+
+type InvalidFileError struct {
+	Reason string
+}
+
+func (e *InvalidFileError) Error() string {
+	return fmt.Sprintf("invalid file: %s", e.Reason)
+}
+
+type ImportTooLargeError struct {
+	Reason string
+}
+
+func (e *ImportTooLargeError) Error() string {
+	return fmt.Sprintf("import too large: %s", e.Reason)
+}
+
+type InvalidColumnError struct {
+	Column string
+}
+
+func (e *InvalidColumnError) Error() string {
+	return fmt.Sprintf("invalid value of column %q", e.Column)
+}
+
+type InvalidRowsError struct {
+	Rows []RowError
+}
+
+func (e *InvalidRowsError) Error() string {
+	return fmt.Sprintf("file has %d invalid row(s), nothing imported", len(e.Rows))
+}
+
+type ConflictingRowsError struct {
+	Rows []RowError
+}
+
+func (e *ConflictingRowsError) Error() string {
+	return fmt.Sprintf("%d row(s) conflict with active banners, nothing imported", len(e.Rows))
+}
+
+type RepeatedExternalIDError struct {
+	ExternalID string
+}
+
+func (e *RepeatedExternalIDError) Error() string {
+	return fmt.Sprintf("external id %q is repeated", e.ExternalID)
+}
+
+type MissingFieldError struct {
+	Field string
+}
+
+func (e *MissingFieldError) Error() string {
+	return fmt.Sprintf("missing required field: %s", e.Field)
+}
+
+type DraftStateError struct {
+	Status DraftStatus
+}
+
+func (e *DraftStateError) Error() string {
+	return fmt.Sprintf("draft is %s", e.Status)
+}
+
+type SelfReviewError struct{}
+
+func (e *SelfReviewError) Error() string {
+	return "draft has to be reviewed by another admin"
+}
+
+type ManifestApplyDisabledError struct{}
+
+func (e *ManifestApplyDisabledError) Error() string {
+	return "applying manifests is disabled, changes have to go through drafts"
+}
+
+type StaleManifestPlanError struct{}
+
+func (e *StaleManifestPlanError) Error() string {
+	return "managed banner has been changed since the plan, plan again"
+}
+
+type InvalidFeatureError struct {
+	FeatureID int
+	Reason    string
+}
+
+func (e *InvalidFeatureError) Error() string {
+	return fmt.Sprintf("invalid feature %d: %s", e.FeatureID, e.Reason)
+}
+
+type InvalidTagError struct {
+	TagID  int
+	Reason string
+}
+
+func (e *InvalidTagError) Error() string {
+	return fmt.Sprintf("invalid tag %d: %s", e.TagID, e.Reason)
+}
+
+type AlreadyExistsError struct {
+	Entity string
+	Field  string
+}
+
+func (e *AlreadyExistsError) Error() string {
+	return fmt.Sprintf("%s with this %s already exists", e.Entity, e.Field)
+}
+
+type InUseError struct {
+	Entity string
+	ID     int
+}
+
+func (e *InUseError) Error() string {
+	return fmt.Sprintf("%s %d is used by banners, archive it instead", e.Entity, e.ID)
+}
+
+type DeleteQueueFullError struct {
+	RetryAfter time.Duration
+}
+
+func (e *DeleteQueueFullError) Error() string {
+	return "too many banner deletions are pending, retry later"
+}
+
+type TooManyBannersError struct {
+	Limit      int
+	RetryAfter time.Duration
+}
+
+func (e *TooManyBannersError) Error() string {
+	return fmt.Sprintf("at most %d banners can be deleted at once, split the batch", e.Limit)
+}
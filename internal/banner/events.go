@@ -0,0 +1,97 @@
+package banner
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// Kinds of banner events.
+const (
+	eventImpression = "impression"
+	eventClick      = "click"
+)
+
+// eventRetryBatches is how many full batches of events are kept
+// while the writes fail.
+const eventRetryBatches = 10
+
+// eventWriteTimeout bounds a single write of a batch of events.
+const eventWriteTimeout = 5 * time.Second
+
+// track records the event of the banner shown to or clicked by the user.
+// Events are written to the database in batches in the background.
+// Should the writer fall behind, the event is dropped and counted
+// rather than holding up the request.
+func (s *BannerService) track(kind string, banner Banner, userID int) {
+	select {
+	case s.eventChan <- BannerEvent{
+		BannerID:  banner.ID,
+		FeatureID: banner.FeatureID,
+		UserID:    userID,
+		Kind:      kind,
+		CreatedAt: time.Now(),
+	}:
+	default:
+		s.droppedEvents.Add(1)
+	}
+}
+
+// flushEvents writes the tracked events in batches: once a batch is full
+// and on every tick. Events that failed to be written are kept for the next
+// tick, up to eventRetryBatches full batches, the oldest are dropped past that.
+func (s *BannerService) flushEvents() {
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+
+	events := make([]BannerEvent, 0, s.config.EventBufferLength)
+	// after a failed write only the ticker tries again
+	failed := false
+
+	for {
+		select {
+		case e := <-s.eventChan:
+			if len(events) == eventRetryBatches*s.config.EventBufferLength {
+				events = slices.Delete(events, 0, 1)
+				s.droppedEvents.Add(1)
+			}
+			events = append(events, e)
+			if failed || len(events) < s.config.EventBufferLength {
+				continue
+			}
+			// do not wait for the ticker once the batch is full
+			events, failed = s.writeEvents(events)
+
+		case <-s.done:
+			if len(events) == 0 {
+				return
+			}
+			s.writeEvents(events)
+			return
+
+		case <-ticker.C:
+			if dropped := s.droppedEvents.Swap(0); dropped > 0 {
+				s.logger.Errorf("events: %d dropped", dropped)
+			}
+			if len(events) == 0 {
+				continue
+			}
+			events, failed = s.writeEvents(events)
+		}
+	}
+}
+
+// writeEvents writes the events within eventWriteTimeout. It returns
+// the events left to write, which are all of them if the write failed.
+func (s *BannerService) writeEvents(events []BannerEvent) ([]BannerEvent, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), eventWriteTimeout)
+	defer cancel()
+
+	if err := s.repo.CreateBannerEvents(ctx, events...); err != nil {
+		s.logger.Errorf("events: %v", err)
+		return events, true
+	}
+
+	// reset buffer only when flush succeeded
+	return events[:0:s.config.EventBufferLength], false
+}
@@ -0,0 +1,56 @@
+package banner
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+)
+
+// checkVariants validates variants of an experiment before they reach the database.
+func checkVariants(variants []Variant) error {
+	if len(variants) == 0 {
+		return &InvalidExperimentError{Reason: "no variants"}
+	}
+
+	seen := make(map[int]struct{}, len(variants))
+	for _, v := range variants {
+		if v.Weight < 1 {
+			return &InvalidExperimentError{
+				Reason: fmt.Sprintf("weight of banner %d must be positive", v.BannerId),
+			}
+		}
+		if _, ok := seen[v.BannerId]; ok {
+			return &InvalidExperimentError{
+				Reason: fmt.Sprintf("banner %d is listed twice", v.BannerId),
+			}
+		}
+		seen[v.BannerId] = struct{}{}
+	}
+
+	return nil
+}
+
+// pickVariant assigns the user to one of the experiment variants
+// with probability proportional to its weight. The same user always
+// gets the same variant as long as the weights stay the same.
+func pickVariant(variants []ExperimentVariant, userID int) ExperimentVariant {
+	total := 0
+	for _, v := range variants {
+		total += v.Weight
+	}
+
+	// experiment id is mixed in, so users are split independently
+	// in different experiments; the low bits of fast non-cryptographic
+	// hashes such as FNV follow the input too closely for that
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d:%d", variants[0].ExperimentID, userID)))
+	point := int(binary.BigEndian.Uint64(sum[:8]) % uint64(total))
+
+	for _, v := range variants {
+		if point < v.Weight {
+			return v
+		}
+		point -= v.Weight
+	}
+
+	return variants[len(variants)-1]
+}
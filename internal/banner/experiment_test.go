@@ -0,0 +1,98 @@
+package banner
+
+import (
+	"math"
+	"testing"
+)
+
+// splitUsers is the number of users the experiment split is checked on.
+const splitUsers = 10000
+
+func TestPickVariant(t *testing.T) {
+	tests := []struct {
+		name     string
+		variants []ExperimentVariant
+		// share of the users each banner is expected to be shown to
+		share map[int]float64
+	}{
+		{
+			name:     "single variant",
+			variants: []ExperimentVariant{{ExperimentID: 1, BannerID: 10, Weight: 1}},
+			share:    map[int]float64{10: 1},
+		},
+		{
+			name: "equal weights",
+			variants: []ExperimentVariant{
+				{ExperimentID: 1, BannerID: 10, Weight: 1},
+				{ExperimentID: 1, BannerID: 20, Weight: 1},
+			},
+			share: map[int]float64{10: 0.5, 20: 0.5},
+		},
+		{
+			name: "uneven weights",
+			variants: []ExperimentVariant{
+				{ExperimentID: 2, BannerID: 10, Weight: 9},
+				{ExperimentID: 2, BannerID: 20, Weight: 1},
+			},
+			share: map[int]float64{10: 0.9, 20: 0.1},
+		},
+		{
+			name: "three variants",
+			variants: []ExperimentVariant{
+				{ExperimentID: 3, BannerID: 10, Weight: 20},
+				{ExperimentID: 3, BannerID: 20, Weight: 30},
+				{ExperimentID: 3, BannerID: 30, Weight: 50},
+			},
+			share: map[int]float64{10: 0.2, 20: 0.3, 30: 0.5},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			shown := make(map[int]int, len(tt.variants))
+			for userID := 1; userID <= splitUsers; userID++ {
+				v := pickVariant(tt.variants, userID)
+				if again := pickVariant(tt.variants, userID); again != v {
+					t.Fatalf("user %d got banner %d, then %d", userID, v.BannerID, again.BannerID)
+				}
+				shown[v.BannerID]++
+			}
+
+			if len(shown) != len(tt.share) {
+				t.Errorf("shown banners %v, want %v", shown, tt.share)
+			}
+			for id, want := range tt.share {
+				got := float64(shown[id]) / splitUsers
+				if math.Abs(got-want) > 0.02 {
+					t.Errorf("banner %d shown to %.3f of users, want %.3f", id, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPickVariantSplitsExperimentsIndependently(t *testing.T) {
+	first := []ExperimentVariant{
+		{ExperimentID: 1, BannerID: 10, Weight: 1},
+		{ExperimentID: 1, BannerID: 20, Weight: 1},
+	}
+	second := []ExperimentVariant{
+		{ExperimentID: 2, BannerID: 30, Weight: 1},
+		{ExperimentID: 2, BannerID: 40, Weight: 1},
+	}
+
+	both := 0
+	for userID := 1; userID <= splitUsers; userID++ {
+		if pickVariant(first, userID).BannerID == 10 && pickVariant(second, userID).BannerID == 30 {
+			both++
+		}
+	}
+
+	// the users of the first variant of one experiment
+	// are spread over both variants of the other
+	if got := float64(both) / splitUsers; math.Abs(got-0.25) > 0.02 {
+		t.Errorf("%.3f of users got the first variant of both experiments, want 0.25", got)
+	}
+}
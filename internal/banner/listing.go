@@ -0,0 +1,236 @@
+package banner
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// pageCursor is the position in the listing the next page starts after.
+// The sort order is kept in it, so that a cursor is not resumed in another one.
+type pageCursor struct {
+	Sort  GetBannerParamsSort  `json:"sort"`
+	Order GetBannerParamsOrder `json:"order"`
+	ID    int                  `json:"id"`
+	Time  *time.Time           `json:"time,omitempty"`
+}
+
+// newPageCursor returns the cursor pointing at the banner in the sort order.
+func newPageCursor(sort GetBannerParamsSort, order GetBannerParamsOrder, b GetBannerResponse) pageCursor {
+	c := pageCursor{Sort: sort, Order: order, ID: b.BannerID}
+
+	switch sort {
+	case GetBannerParamsSortCreatedAt:
+		c.Time = &b.CreatedAt
+	case GetBannerParamsSortUpdatedAt:
+		c.Time = &b.UpdatedAt
+	}
+
+	return c
+}
+
+// encode returns the opaque form of the cursor handed out to clients.
+func (c pageCursor) encode() (string, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodePageCursor(s string) (*pageCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, &InvalidParamFormatError{ParamName: "after", Err: errors.New("malformed cursor")}
+	}
+
+	var c pageCursor
+	if err = json.Unmarshal(data, &c); err != nil {
+		return nil, &InvalidParamFormatError{ParamName: "after", Err: errors.New("malformed cursor")}
+	}
+
+	return &c, nil
+}
+
+// BannerFilter selects the banners of the admin listing.
+// Unset fields do not narrow the selection.
+type BannerFilter struct {
+	FeatureID *int
+	TagID     *int
+	// Query is the full-text search query over the content.
+	Query *string
+	// Deleted lists the values of is_deleted to include.
+	Deleted []bool
+}
+
+// filters reports whether anything but the deleted state narrows the selection.
+func (f BannerFilter) filters() bool {
+	return f.FeatureID != nil || f.TagID != nil || f.Query != nil
+}
+
+func newBannerFilter(params GetBannerParams) BannerFilter {
+	filter := BannerFilter{
+		FeatureID: params.FeatureId,
+		TagID:     params.TagId,
+		Deleted:   deletedFilter(params.IncludeDeleted, params.OnlyDeleted),
+	}
+
+	// empty search query searches nothing
+	if params.Q != nil && *params.Q != "" {
+		filter.Query = params.Q
+	}
+
+	return filter
+}
+
+// BannerPage is the part of the filtered banners to return, either starting
+// after the cursor or skipping the offset. Without a limit it runs to the end.
+type BannerPage struct {
+	Sort   GetBannerParamsSort
+	Order  GetBannerParamsOrder
+	After  *pageCursor
+	Limit  *int
+	Offset *int
+}
+
+// isPaged reports whether the listing is paginated with cursors
+// rather than limit and offset.
+func isPaged(params GetBannerParams) bool {
+	return params.After != nil || params.Sort != nil || params.Order != nil
+}
+
+func newBannerPage(params GetBannerParams) (BannerPage, error) {
+	page := BannerPage{
+		Sort:   GetBannerParamsSortId,
+		Order:  GetBannerParamsOrderAsc,
+		Limit:  params.Limit,
+		Offset: params.Offset,
+	}
+
+	if !isPaged(params) {
+		return page, nil
+	}
+
+	if params.Q != nil && *params.Q != "" {
+		return page, &InvalidParamFormatError{
+			ParamName: "q",
+			Err:       errors.New("search results are ranked and paginated with limit and offset only"),
+		}
+	}
+	if params.Offset != nil {
+		return page, &InvalidParamFormatError{
+			ParamName: "offset",
+			Err:       errors.New("offset cannot be combined with cursor pagination"),
+		}
+	}
+	if params.Limit != nil && *params.Limit < 0 {
+		return page, &InvalidParamFormatError{
+			ParamName: "limit",
+			Err:       errors.New("limit cannot be negative"),
+		}
+	}
+
+	if params.Sort != nil {
+		page.Sort = *params.Sort
+	}
+	switch page.Sort {
+	case GetBannerParamsSortId, GetBannerParamsSortCreatedAt, GetBannerParamsSortUpdatedAt:
+	default:
+		return page, &InvalidParamFormatError{
+			ParamName: "sort",
+			Err:       fmt.Errorf("unknown sort %q", page.Sort),
+		}
+	}
+
+	if params.Order != nil {
+		page.Order = *params.Order
+	}
+	switch page.Order {
+	case GetBannerParamsOrderAsc, GetBannerParamsOrderDesc:
+	default:
+		return page, &InvalidParamFormatError{
+			ParamName: "order",
+			Err:       fmt.Errorf("unknown order %q", page.Order),
+		}
+	}
+
+	if params.After != nil {
+		c, err := decodePageCursor(*params.After)
+		if err != nil {
+			return page, err
+		}
+		if c.Sort != page.Sort || c.Order != page.Order {
+			return page, &InvalidParamFormatError{
+				ParamName: "after",
+				Err:       fmt.Errorf("cursor of sort %q %q cannot be used with sort %q %q", c.Sort, c.Order, page.Sort, page.Order),
+			}
+		}
+		if c.Sort != GetBannerParamsSortId && c.Time == nil {
+			return page, &InvalidParamFormatError{ParamName: "after", Err: errors.New("malformed cursor")}
+		}
+		page.After = c
+	}
+
+	return page, nil
+}
+
+// listBanners returns the page of banners selected by the params and,
+// with cursor pagination, the cursor of the next page, empty on the last one.
+func (s *BannerService) listBanners(ctx context.Context, params GetBannerParams) ([]GetBannerResponse, string, error) {
+	filter := newBannerFilter(params)
+
+	page, err := newBannerPage(params)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if !isPaged(params) {
+		// nothing is listed without filters unless paginated with cursors
+		if !filter.filters() {
+			return make([]GetBannerResponse, 0), "", nil
+		}
+		banners, err := s.repo.GetBanners(ctx, filter, page)
+		return banners, "", err
+	}
+
+	// one more banner is read to tell whether the page is the last one
+	limit := page.Limit
+	if limit != nil {
+		extra := *limit + 1
+		page.Limit = &extra
+	}
+
+	banners, err := s.repo.GetBanners(ctx, filter, page)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if limit == nil || len(banners) <= *limit {
+		return banners, "", nil
+	}
+
+	banners = banners[:*limit]
+	if len(banners) == 0 {
+		return banners, "", nil
+	}
+
+	next, err := newPageCursor(page.Sort, page.Order, banners[len(banners)-1]).encode()
+	if err != nil {
+		return nil, "", err
+	}
+
+	return banners, next, nil
+}
+
+// countBanners returns the number of banners the listing selects regardless of the pagination.
+func (s *BannerService) countBanners(ctx context.Context, params GetBannerParams) (int, error) {
+	filter := newBannerFilter(params)
+	if !isPaged(params) && !filter.filters() {
+		return 0, nil
+	}
+
+	return s.repo.CountBanners(ctx, filter)
+}
@@ -0,0 +1,380 @@
+package banner
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/KretovDmitry/avito-tech/internal/config"
+	"github.com/KretovDmitry/avito-tech/pkg/log"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/lib/pq"
+)
+
+func TestPageCursor(t *testing.T) {
+	created := time.Date(2024, 4, 1, 12, 0, 0, 0, time.UTC)
+	updated := created.Add(time.Hour)
+	banner := GetBannerResponse{BannerID: 42, CreatedAt: created, UpdatedAt: updated}
+
+	tests := []struct {
+		name     string
+		sort     GetBannerParamsSort
+		order    GetBannerParamsOrder
+		wantTime *time.Time
+	}{
+		{name: "by id", sort: GetBannerParamsSortId, order: GetBannerParamsOrderAsc},
+		{name: "by creation", sort: GetBannerParamsSortCreatedAt, order: GetBannerParamsOrderDesc, wantTime: &created},
+		{name: "by update", sort: GetBannerParamsSortUpdatedAt, order: GetBannerParamsOrderAsc, wantTime: &updated},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := newPageCursor(tt.sort, tt.order, banner).encode()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			c, err := decodePageCursor(encoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.Sort != tt.sort || c.Order != tt.order || c.ID != banner.BannerID {
+				t.Errorf("got cursor %+v, want %s %s after %d", c, tt.sort, tt.order, banner.BannerID)
+			}
+			switch {
+			case tt.wantTime == nil && c.Time != nil:
+				t.Errorf("got time %v, want none", c.Time)
+			case tt.wantTime != nil && (c.Time == nil || !c.Time.Equal(*tt.wantTime)):
+				t.Errorf("got time %v, want %v", c.Time, tt.wantTime)
+			}
+		})
+	}
+}
+
+func TestDecodePageCursorMalformed(t *testing.T) {
+	for _, s := range []string{"", "not base64!", "bm90IGpzb24"} {
+		if c, err := decodePageCursor(s); !isParamError(err, "after") {
+			t.Errorf("decode %q: got %+v, %v, want an after parameter error", s, c, err)
+		}
+	}
+}
+
+func TestNewBannerPage(t *testing.T) {
+	created := time.Date(2024, 4, 1, 12, 0, 0, 0, time.UTC)
+	encode := func(c pageCursor) *string {
+		encoded, err := c.encode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &encoded
+	}
+	cursor := func(sort GetBannerParamsSort, order GetBannerParamsOrder) *string {
+		return encode(newPageCursor(sort, order, GetBannerResponse{BannerID: 7, CreatedAt: created}))
+	}
+
+	tests := []struct {
+		name      string
+		params    GetBannerParams
+		want      BannerPage
+		wantAfter *pageCursor
+		// wantErr is the parameter reported invalid, if any
+		wantErr string
+	}{
+		{
+			name:   "limit and offset",
+			params: GetBannerParams{Limit: ref(10), Offset: ref(20)},
+			want:   BannerPage{Sort: GetBannerParamsSortId, Order: GetBannerParamsOrderAsc, Limit: ref(10), Offset: ref(20)},
+		},
+		{
+			name:   "search with limit and offset",
+			params: GetBannerParams{Q: ref("sale"), Limit: ref(10), Offset: ref(20)},
+			want:   BannerPage{Sort: GetBannerParamsSortId, Order: GetBannerParamsOrderAsc, Limit: ref(10), Offset: ref(20)},
+		},
+		{
+			name:   "first page by default order",
+			params: GetBannerParams{Sort: ref(GetBannerParamsSortUpdatedAt), Limit: ref(5)},
+			want:   BannerPage{Sort: GetBannerParamsSortUpdatedAt, Order: GetBannerParamsOrderAsc, Limit: ref(5)},
+		},
+		{
+			name: "next page",
+			params: GetBannerParams{
+				Sort:  ref(GetBannerParamsSortCreatedAt),
+				Order: ref(GetBannerParamsOrderDesc),
+				After: cursor(GetBannerParamsSortCreatedAt, GetBannerParamsOrderDesc),
+			},
+			want:      BannerPage{Sort: GetBannerParamsSortCreatedAt, Order: GetBannerParamsOrderDesc},
+			wantAfter: &pageCursor{Sort: GetBannerParamsSortCreatedAt, Order: GetBannerParamsOrderDesc, ID: 7, Time: &created},
+		},
+		{
+			name:    "search with cursor",
+			params:  GetBannerParams{Q: ref("sale"), Sort: ref(GetBannerParamsSortId)},
+			wantErr: "q",
+		},
+		{
+			name:    "offset with cursor",
+			params:  GetBannerParams{Offset: ref(1), Order: ref(GetBannerParamsOrderDesc)},
+			wantErr: "offset",
+		},
+		{
+			name:    "negative limit",
+			params:  GetBannerParams{Limit: ref(-1), Sort: ref(GetBannerParamsSortId)},
+			wantErr: "limit",
+		},
+		{
+			name:    "unknown sort",
+			params:  GetBannerParams{Sort: ref(GetBannerParamsSort("priority"))},
+			wantErr: "sort",
+		},
+		{
+			name:    "unknown order",
+			params:  GetBannerParams{Order: ref(GetBannerParamsOrder("up"))},
+			wantErr: "order",
+		},
+		{
+			name: "cursor of another order",
+			params: GetBannerParams{
+				Sort:  ref(GetBannerParamsSortCreatedAt),
+				After: cursor(GetBannerParamsSortCreatedAt, GetBannerParamsOrderDesc),
+			},
+			wantErr: "after",
+		},
+		{
+			name:    "cursor without order",
+			params:  GetBannerParams{After: encode(pageCursor{Sort: GetBannerParamsSortId, ID: 7})},
+			wantErr: "after",
+		},
+		{
+			name: "time cursor without time",
+			params: GetBannerParams{
+				Sort:  ref(GetBannerParamsSortCreatedAt),
+				After: encode(pageCursor{Sort: GetBannerParamsSortCreatedAt, Order: GetBannerParamsOrderAsc, ID: 7}),
+			},
+			wantErr: "after",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := newBannerPage(tt.params)
+			if tt.wantErr != "" {
+				if !isParamError(err, tt.wantErr) {
+					t.Fatalf("got error %v, want %s parameter error", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if page.Sort != tt.want.Sort || page.Order != tt.want.Order ||
+				!equalPtr(page.Limit, tt.want.Limit, func(a, b int) bool { return a == b }) ||
+				!equalPtr(page.Offset, tt.want.Offset, func(a, b int) bool { return a == b }) {
+				t.Errorf("got page %+v, want %+v", page, tt.want)
+			}
+
+			switch {
+			case tt.wantAfter == nil && page.After != nil:
+				t.Errorf("got cursor %+v, want none", page.After)
+			case tt.wantAfter != nil && (page.After == nil || page.After.ID != tt.wantAfter.ID ||
+				!equalPtr(page.After.Time, tt.wantAfter.Time, time.Time.Equal)):
+				t.Errorf("got cursor %+v, want %+v", page.After, tt.wantAfter)
+			}
+		})
+	}
+}
+
+// isParamError reports whether err is the format error of the parameter.
+func isParamError(err error, name string) bool {
+	var paramErr *InvalidParamFormatError
+	return errors.As(err, &paramErr) && paramErr.ParamName == name
+}
+
+// benchBanners is the number of banners the listing benchmark is seeded with.
+const benchBanners = 10000
+
+// BenchmarkGetBanners compares the listing as GET /banner served it before
+// the tags were aggregated, a query for the page of banners and one more for
+// the tags of every banner, with the single query aggregating them. It needs
+// a migrated database given by APP_DSN; the banners it is seeded with are
+// never committed.
+func BenchmarkGetBanners(b *testing.B) {
+	dsn := os.Getenv("APP_DSN")
+	if dsn == "" {
+		b.Skip("APP_DSN is not set")
+	}
+
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() {
+		if err := db.Close(); err != nil {
+			b.Error(err)
+		}
+	})
+
+	ctx := context.Background()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() {
+		if err := tx.Rollback(); err != nil {
+			b.Error(err)
+		}
+	})
+
+	featureID := seedBanners(ctx, b, tx)
+
+	// both listings read the seeded banners within the transaction
+	r := &repository{db: db, queries: New(tx), logger: log.New(), config: &config.Config{}}
+
+	for _, limit := range []int{100, benchBanners} {
+		limit := limit
+
+		b.Run(fmt.Sprintf("per-row tags/limit=%d", limit), func(b *testing.B) {
+			params := GetBannerParams{FeatureId: &featureID, Limit: &limit}
+
+			for i := 0; i < b.N; i++ {
+				banners, err := getBannersByFeatureWithLimit(ctx, r.queries, params)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if len(banners) != limit {
+					b.Fatalf("got %d banners, want %d", len(banners), limit)
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("single query/limit=%d", limit), func(b *testing.B) {
+			filter := BannerFilter{FeatureID: &featureID, Deleted: []bool{false}}
+			page := BannerPage{Sort: GetBannerParamsSortId, Order: GetBannerParamsOrderAsc, Limit: &limit}
+
+			for i := 0; i < b.N; i++ {
+				banners, err := r.GetBanners(ctx, filter, page)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if len(banners) != limit {
+					b.Fatalf("got %d banners, want %d", len(banners), limit)
+				}
+			}
+		})
+	}
+}
+
+// seedBanners creates benchBanners inactive banners with two tags each
+// under a feature no other banner uses.
+func seedBanners(ctx context.Context, b *testing.B, tx *sql.Tx) int {
+	b.Helper()
+
+	var featureID int
+	err := tx.QueryRowContext(ctx, `SELECT COALESCE(MAX(feature_id), 0) + 1 FROM banners`).Scan(&featureID)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO banners (feature_id, content, is_active)
+		SELECT $1, jsonb_build_object('title', 'bench ' || n, 'url', 'https://example.com/' || n), FALSE
+		FROM generate_series(1, $2::INTEGER) AS n`, featureID, benchBanners)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO tags (banner_id, tag_id)
+		SELECT b.id, t.tag_id
+		FROM banners b, LATERAL (VALUES (b.id % 5 + 1), (b.id % 5 + 6)) AS t (tag_id)
+		WHERE b.feature_id = $1`, featureID)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	if _, err = tx.ExecContext(ctx, `ANALYZE banners, tags`); err != nil {
+		b.Fatal(err)
+	}
+
+	return featureID
+}
+
+// getBannersByFeatureWithLimitQuery is the query GET /banner ran for
+// a feature and a limit before the tags were aggregated.
+const getBannersByFeatureWithLimitQuery = `-- name: GetBannersByFeatureWithLimit :many
+SELECT
+    id, feature_id, is_active, is_deleted, created_at, updated_at, content, active_from, active_until, priority, localized_content, frequency_cap, deleted_at
+FROM
+    banners
+WHERE
+    feature_id = $1
+    AND is_deleted = ANY ($2::BOOLEAN[])
+ORDER BY
+    id
+LIMIT $3
+`
+
+// getBannersByFeatureWithLimit is the repository method that served it,
+// kept along with the query as they were as the baseline of the benchmark.
+func getBannersByFeatureWithLimit(ctx context.Context, q *Queries, params GetBannerParams) ([]GetBannerResponse, error) {
+	rows, err := q.db.QueryContext(ctx, getBannersByFeatureWithLimitQuery,
+		*params.FeatureId, pq.Array(deletedFilter(params.IncludeDeleted, params.OnlyDeleted)), *params.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var banners []Banner
+	for rows.Next() {
+		var i Banner
+		if err := rows.Scan(
+			&i.ID,
+			&i.FeatureID,
+			&i.IsActive,
+			&i.IsDeleted,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Content,
+			&i.ActiveFrom,
+			&i.ActiveUntil,
+			&i.Priority,
+			&i.LocalizedContent,
+			&i.FrequencyCap,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		banners = append(banners, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	response := make([]GetBannerResponse, 0)
+
+	for _, b := range banners {
+		t, err := q.GetTagsByBannerID(ctx, b.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		tags := make([]int, 0, 1)
+		for _, tag := range t {
+			tags = append(tags, tag.TagID)
+		}
+
+		response = append(response, newBannerResponse(b, tags))
+	}
+
+	return response, nil
+}
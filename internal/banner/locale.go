@@ -0,0 +1,156 @@
+package banner
+
+import (
+	"cmp"
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// requestedLocales returns the locales asked for by the user in order of
+// preference: the explicit locale parameter if any, otherwise the
+// Accept-Language ones by their quality. No locales means none was asked for.
+func requestedLocales(params GetUserBannerParams) ([]string, error) {
+	if params.Locale != nil {
+		tag, err := language.Parse(*params.Locale)
+		if err != nil {
+			return nil, &InvalidLocaleError{Locale: *params.Locale}
+		}
+		return []string{tag.String()}, nil
+	}
+
+	if params.AcceptLanguage != nil {
+		return acceptLanguages(*params.AcceptLanguage), nil
+	}
+
+	return make([]string, 0), nil
+}
+
+// acceptLanguages returns the locales of the Accept-Language header by their
+// quality, the most preferred first. Malformed or unknown entries are skipped
+// rather than failing the request, unlike language.ParseAcceptLanguage, which
+// drops the whole header for a single one of them.
+func acceptLanguages(header string) []string {
+	type entry struct {
+		locale  string
+		quality float64
+	}
+
+	entries := make([]entry, 0)
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		// zero quality means the locale is not acceptable
+		if quality <= 0 {
+			continue
+		}
+
+		t, err := language.Parse(strings.TrimSpace(tag))
+		if err != nil || t == language.Und {
+			continue
+		}
+
+		entries = append(entries, entry{locale: t.String(), quality: quality})
+	}
+
+	slices.SortStableFunc(entries, func(a, b entry) int {
+		return cmp.Compare(b.quality, a.quality)
+	})
+
+	locales := make([]string, 0, len(entries))
+	for _, e := range entries {
+		locales = append(locales, e.locale)
+	}
+
+	return locales
+}
+
+// localeChain returns locales to look banner content up in order:
+// every requested locale followed by its base language, then the
+// configured fallbacks.
+func localeChain(requested []string, fallback []string) []string {
+	chain := make([]string, 0, 2*len(requested)+len(fallback))
+	seen := make(map[string]struct{}, 2*len(requested)+len(fallback))
+
+	add := func(tag language.Tag) {
+		if tag == language.Und {
+			return
+		}
+		if _, ok := seen[tag.String()]; ok {
+			return
+		}
+		seen[tag.String()] = struct{}{}
+		chain = append(chain, tag.String())
+	}
+
+	for _, locale := range requested {
+		if tag, err := language.Parse(locale); err == nil {
+			add(tag)
+			if base, conf := tag.Base(); conf != language.No {
+				add(language.Make(base.String()))
+			}
+		}
+	}
+
+	for _, f := range fallback {
+		if tag, err := language.Parse(f); err == nil {
+			add(tag)
+		}
+	}
+
+	return chain
+}
+
+// localize replaces the banner content with the first localized one found
+// in the chain. The banner keeps its own content if none is found.
+func localize(banner *UserBanner, chain []string) error {
+	localized := make(map[string]json.RawMessage)
+	if len(banner.LocalizedContent) > 0 {
+		if err := json.Unmarshal(banner.LocalizedContent, &localized); err != nil {
+			return err
+		}
+	}
+
+	// only the served content is worth keeping
+	banner.LocalizedContent = nil
+
+	for _, locale := range chain {
+		if content, ok := localized[locale]; ok {
+			banner.Content = content
+			banner.Locale = locale
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// normalizeLocales brings locale keys of the content to the canonical form,
+// so that they are matched against requested locales.
+func normalizeLocales(content map[string]map[string]interface{}) (map[string]map[string]interface{}, error) {
+	normalized := make(map[string]map[string]interface{}, len(content))
+
+	for locale, c := range content {
+		tag, err := language.Parse(locale)
+		if err != nil || tag == language.Und {
+			return nil, &InvalidLocaleError{Locale: locale}
+		}
+		if _, ok := normalized[tag.String()]; ok {
+			return nil, &InvalidLocaleError{Locale: locale}
+		}
+		normalized[tag.String()] = c
+	}
+
+	return normalized, nil
+}
@@ -0,0 +1,186 @@
+package banner
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestRequestedLocales(t *testing.T) {
+	tests := []struct {
+		name           string
+		locale         *string
+		acceptLanguage *string
+		want           []string
+		wantErr        bool
+	}{
+		{
+			name: "nothing asked for",
+			want: []string{},
+		},
+		{
+			name:   "locale parameter in canonical form",
+			locale: ref("EN-us"),
+			want:   []string{"en-US"},
+		},
+		{
+			name:           "locale parameter over the header",
+			locale:         ref("de"),
+			acceptLanguage: ref("ru"),
+			want:           []string{"de"},
+		},
+		{
+			name:    "malformed locale parameter",
+			locale:  ref("en-"),
+			wantErr: true,
+		},
+		{
+			name:           "header by quality",
+			acceptLanguage: ref("ru;q=0.5, de, en-US;q=0.8"),
+			want:           []string{"de", "en-US", "ru"},
+		},
+		{
+			name:           "equal quality in header order",
+			acceptLanguage: ref("fr, de"),
+			want:           []string{"fr", "de"},
+		},
+		{
+			name:           "unknown tag skipped",
+			acceptLanguage: ref("xx, ru;q=0.8"),
+			want:           []string{"ru"},
+		},
+		{
+			name:           "wildcard, zero and malformed quality skipped",
+			acceptLanguage: ref("*, en;q=0, de;q=high, ru;q=0.1"),
+			want:           []string{"ru"},
+		},
+		{
+			name:           "malformed header ignored",
+			acceptLanguage: ref("???"),
+			want:           []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := requestedLocales(GetUserBannerParams{Locale: tt.locale, AcceptLanguage: tt.acceptLanguage})
+			if tt.wantErr {
+				var localeErr *InvalidLocaleError
+				if !errors.As(err, &localeErr) {
+					t.Fatalf("got error %v, want InvalidLocaleError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocaleChain(t *testing.T) {
+	tests := []struct {
+		name      string
+		requested []string
+		fallback  []string
+		want      []string
+	}{
+		{
+			name: "nothing to look up",
+			want: []string{},
+		},
+		{
+			name:      "base language after every locale",
+			requested: []string{"en-US", "ru-RU"},
+			want:      []string{"en-US", "en", "ru-RU", "ru"},
+		},
+		{
+			name:      "fallbacks last",
+			requested: []string{"de"},
+			fallback:  []string{"en", "ru"},
+			want:      []string{"de", "en", "ru"},
+		},
+		{
+			name:      "repeated locales once",
+			requested: []string{"en-GB", "en"},
+			fallback:  []string{"en", "en-GB"},
+			want:      []string{"en-GB", "en"},
+		},
+		{
+			name:     "malformed fallback skipped",
+			fallback: []string{"en-", "ru"},
+			want:     []string{"ru"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			if got := localeChain(tt.requested, tt.fallback); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	localized := json.RawMessage(`{"ru": {"title": "привет"}, "en": {"title": "hello"}}`)
+
+	tests := []struct {
+		name        string
+		chain       []string
+		wantContent string
+		wantLocale  string
+	}{
+		{
+			name:        "first locale found",
+			chain:       []string{"de", "en", "ru"},
+			wantContent: `{"title": "hello"}`,
+			wantLocale:  "en",
+		},
+		{
+			name:        "own content when none found",
+			chain:       []string{"de"},
+			wantContent: `{"title": "default"}`,
+		},
+		{
+			name:        "own content without a chain",
+			wantContent: `{"title": "default"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			banner := UserBanner{Banner: Banner{
+				Content:          json.RawMessage(`{"title": "default"}`),
+				LocalizedContent: localized,
+			}}
+
+			if err := localize(&banner, tt.chain); err != nil {
+				t.Fatal(err)
+			}
+			if string(banner.Content) != tt.wantContent {
+				t.Errorf("got content %s, want %s", banner.Content, tt.wantContent)
+			}
+			if banner.Locale != tt.wantLocale {
+				t.Errorf("got locale %q, want %q", banner.Locale, tt.wantLocale)
+			}
+			if banner.LocalizedContent != nil {
+				t.Errorf("localized content kept: %s", banner.LocalizedContent)
+			}
+		})
+	}
+}
+
+func ref[T any](v T) *T {
+	return &v
+}
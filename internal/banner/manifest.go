@@ -0,0 +1,242 @@
+package banner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// manifestNameLength is the longest name of a manifest.
+const manifestNameLength = 255
+
+// manifest describes the desired state of banners kept as code.
+// Banners are keyed by external id and carry the same fields as the body
+// of banner creation. A manifest owns the banners it created under its name,
+// the ones of them missing from the manifest get deactivated.
+type manifest struct {
+	Name    string                   `yaml:"name"`
+	Banners []map[string]interface{} `yaml:"banners"`
+}
+
+// ManifestEntry is a banner of the manifest along with the banner it is applied to.
+type ManifestEntry struct {
+	ExternalID string
+	BannerID   int
+	Data       PostBannerJSONBody
+}
+
+// ManifestChanges bring the banners managed by the manifest to its state.
+type ManifestChanges struct {
+	Manifest   string
+	Create     []ManifestEntry
+	Update     []ManifestEntry
+	Deactivate []int
+}
+
+func (c ManifestChanges) empty() bool {
+	return len(c.Create) == 0 && len(c.Update) == 0 && len(c.Deactivate) == 0
+}
+
+// planManifest validates the manifest and diffs it against the managed banners.
+func (s *BannerService) planManifest(ctx context.Context, body []byte) (*ManifestPlan, *ManifestChanges, error) {
+	m := new(manifest)
+	if err := yaml.Unmarshal(body, m); err != nil {
+		return nil, nil, &InvalidFileError{Reason: err.Error()}
+	}
+	if m.Name == "" {
+		return nil, nil, &MissingFieldError{Field: "name"}
+	}
+	if len(m.Name) > manifestNameLength {
+		return nil, nil, &InvalidFileError{Reason: fmt.Sprintf("name is longer than %d bytes", manifestNameLength)}
+	}
+
+	entries := make([]ManifestEntry, 0, len(m.Banners))
+	invalid := make([]RowError, 0)
+	seen := make(map[string]struct{}, len(m.Banners))
+
+	for i, raw := range m.Banners {
+		id, _ := raw["id"].(string)
+		if id == "" {
+			invalid = append(invalid, newRowError(i+1, &MissingFieldError{Field: "id"}))
+			continue
+		}
+		if _, ok := seen[id]; ok {
+			invalid = append(invalid, newRowError(i+1, &RepeatedExternalIDError{ExternalID: id}))
+			continue
+		}
+		seen[id] = struct{}{}
+
+		delete(raw, "id")
+		b, err := json.Marshal(raw)
+		if err != nil {
+			invalid = append(invalid, newRowError(i+1, &InvalidFileError{Reason: err.Error()}))
+			continue
+		}
+
+		data, err := s.prepareBanner(ctx, b)
+		if err != nil {
+			if !isRowError(err) {
+				return nil, nil, err
+			}
+			invalid = append(invalid, newRowError(i+1, err))
+			continue
+		}
+
+		entries = append(entries, ManifestEntry{ExternalID: id, Data: *data})
+	}
+
+	if len(invalid) > 0 {
+		return nil, nil, &InvalidRowsError{Rows: invalid}
+	}
+
+	current, err := s.repo.GetManagedBanners(ctx, m.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	plan := &ManifestPlan{
+		Create:     make([]ManifestChange, 0),
+		Update:     make([]ManifestChange, 0),
+		Deactivate: make([]ManifestChange, 0),
+	}
+	changes := &ManifestChanges{Manifest: m.Name}
+
+	for _, e := range entries {
+		banner, ok := current[e.ExternalID]
+		if !ok {
+			plan.Create = append(plan.Create, ManifestChange{
+				Id:        e.ExternalID,
+				FeatureId: *e.Data.FeatureId,
+			})
+			changes.Create = append(changes.Create, e)
+			continue
+		}
+
+		fields, err := changedFields(banner, e.Data)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(fields) == 0 {
+			plan.Unchanged++
+			continue
+		}
+
+		e.BannerID = banner.BannerID
+		plan.Update = append(plan.Update, ManifestChange{
+			Id:        e.ExternalID,
+			BannerId:  &banner.BannerID,
+			FeatureId: *e.Data.FeatureId,
+			Fields:    &fields,
+		})
+		changes.Update = append(changes.Update, e)
+	}
+
+	// managed banners dropped from the manifest
+	gone := make([]string, 0)
+	for id, banner := range current {
+		if _, ok := seen[id]; !ok && banner.IsActive {
+			gone = append(gone, id)
+		}
+	}
+	slices.Sort(gone)
+
+	for _, id := range gone {
+		banner := current[id]
+		plan.Deactivate = append(plan.Deactivate, ManifestChange{
+			Id:        id,
+			BannerId:  &banner.BannerID,
+			FeatureId: banner.FeatureID,
+		})
+		changes.Deactivate = append(changes.Deactivate, banner.BannerID)
+	}
+
+	return plan, changes, nil
+}
+
+// changedFields lists the fields of the banner that differ from the manifest.
+func changedFields(banner GetBannerResponse, data PostBannerJSONBody) ([]string, error) {
+	fields := make([]string, 0)
+
+	if banner.FeatureID != *data.FeatureId {
+		fields = append(fields, "feature_id")
+	}
+
+	current, desired := slices.Clone(banner.TagIDs), uniqueTags(*data.TagIds)
+	slices.Sort(current)
+	slices.Sort(desired)
+	if !slices.Equal(current, desired) {
+		fields = append(fields, "tag_ids")
+	}
+
+	same, err := sameJSON(banner.Content, data.Content)
+	if err != nil {
+		return nil, err
+	}
+	if !same {
+		fields = append(fields, "content")
+	}
+
+	localized := make(map[string]map[string]interface{})
+	if data.LocalizedContent != nil {
+		localized = *data.LocalizedContent
+	}
+	same, err = sameJSON(banner.LocalizedContent, localized)
+	if err != nil {
+		return nil, err
+	}
+	if !same {
+		fields = append(fields, "localized_content")
+	}
+
+	if banner.IsActive != *data.IsActive {
+		fields = append(fields, "is_active")
+	}
+
+	if banner.Priority != newPriority(data) {
+		fields = append(fields, "priority")
+	}
+
+	if !equalPtr(banner.FrequencyCap, data.FrequencyCap, func(a, b int) bool { return a == b }) {
+		fields = append(fields, "frequency_cap")
+	}
+
+	if !equalPtr(banner.ActiveFrom, data.ActiveFrom, time.Time.Equal) {
+		fields = append(fields, "active_from")
+	}
+
+	if !equalPtr(banner.ActiveUntil, data.ActiveUntil, time.Time.Equal) {
+		fields = append(fields, "active_until")
+	}
+
+	return fields, nil
+}
+
+// sameJSON reports whether the stored JSON means the same as the value.
+func sameJSON(stored json.RawMessage, v interface{}) (bool, error) {
+	var a, b interface{}
+	if err := json.Unmarshal(stored, &a); err != nil {
+		return false, err
+	}
+
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return false, err
+	}
+	if err = json.Unmarshal(buf, &b); err != nil {
+		return false, err
+	}
+
+	return reflect.DeepEqual(a, b), nil
+}
+
+func equalPtr[T any](a, b *T, eq func(T, T) bool) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return eq(*a, *b)
+}
@@ -0,0 +1,328 @@
+package banner
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestChangedFields(t *testing.T) {
+	from := time.Date(2024, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	banner := func() GetBannerResponse {
+		return GetBannerResponse{
+			BannerID:         1,
+			FeatureID:        1,
+			TagIDs:           []int{2, 1},
+			Content:          json.RawMessage(`{"title": "sale", "discount": 10}`),
+			LocalizedContent: json.RawMessage(`{}`),
+			IsActive:         true,
+			ActiveFrom:       &from,
+		}
+	}
+	data := func() PostBannerJSONBody {
+		return PostBannerJSONBody{
+			FeatureId:  ref(1),
+			TagIds:     &[]int{1, 2, 2},
+			Content:    &map[string]interface{}{"title": "sale", "discount": 10},
+			IsActive:   ref(true),
+			ActiveFrom: ref(from.In(time.FixedZone("MSK", 3*60*60))),
+		}
+	}
+
+	tests := []struct {
+		name   string
+		change func(*GetBannerResponse, *PostBannerJSONBody)
+		want   []string
+	}{
+		{
+			name:   "unchanged",
+			change: func(*GetBannerResponse, *PostBannerJSONBody) {},
+			want:   []string{},
+		},
+		{
+			name:   "zero priority given",
+			change: func(_ *GetBannerResponse, d *PostBannerJSONBody) { d.Priority = ref(0) },
+			want:   []string{},
+		},
+		{
+			name:   "feature",
+			change: func(_ *GetBannerResponse, d *PostBannerJSONBody) { d.FeatureId = ref(2) },
+			want:   []string{"feature_id"},
+		},
+		{
+			name:   "tags",
+			change: func(_ *GetBannerResponse, d *PostBannerJSONBody) { d.TagIds = &[]int{1, 3} },
+			want:   []string{"tag_ids"},
+		},
+		{
+			name: "content",
+			change: func(_ *GetBannerResponse, d *PostBannerJSONBody) {
+				d.Content = &map[string]interface{}{"title": "sale", "discount": 15}
+			},
+			want: []string{"content"},
+		},
+		{
+			name: "localized content",
+			change: func(_ *GetBannerResponse, d *PostBannerJSONBody) {
+				d.LocalizedContent = &map[string]map[string]interface{}{"ru": {"title": "скидка"}}
+			},
+			want: []string{"localized_content"},
+		},
+		{
+			name: "same localized content",
+			change: func(b *GetBannerResponse, d *PostBannerJSONBody) {
+				b.LocalizedContent = json.RawMessage(`{"ru": {"title": "скидка"}}`)
+				d.LocalizedContent = &map[string]map[string]interface{}{"ru": {"title": "скидка"}}
+			},
+			want: []string{},
+		},
+		{
+			name:   "deactivated",
+			change: func(_ *GetBannerResponse, d *PostBannerJSONBody) { d.IsActive = ref(false) },
+			want:   []string{"is_active"},
+		},
+		{
+			name:   "priority",
+			change: func(_ *GetBannerResponse, d *PostBannerJSONBody) { d.Priority = ref(5) },
+			want:   []string{"priority"},
+		},
+		{
+			name:   "frequency cap",
+			change: func(b *GetBannerResponse, d *PostBannerJSONBody) { b.FrequencyCap = ref(3) },
+			want:   []string{"frequency_cap"},
+		},
+		{
+			name:   "schedule",
+			change: func(_ *GetBannerResponse, d *PostBannerJSONBody) { d.ActiveFrom, d.ActiveUntil = nil, ref(from) },
+			want:   []string{"active_from", "active_until"},
+		},
+		{
+			name: "several fields in order",
+			change: func(_ *GetBannerResponse, d *PostBannerJSONBody) {
+				d.Priority = ref(1)
+				d.FeatureId = ref(3)
+				d.IsActive = ref(false)
+			},
+			want: []string{"feature_id", "is_active", "priority"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			b, d := banner(), data()
+			tt.change(&b, &d)
+
+			got, err := changedFields(b, d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// manifestRepository serves the banners managed by the manifest and
+// registers every feature but the archived ones and every tag.
+// Any other call panics.
+type manifestRepository struct {
+	Repository
+	managed  map[string]GetBannerResponse
+	archived map[int]bool
+}
+
+func (r manifestRepository) GetManagedBanners(ctx context.Context, manifest string) (map[string]GetBannerResponse, error) {
+	return r.managed, nil
+}
+
+func (r manifestRepository) GetFeatureSchema(ctx context.Context, featureID int) (json.RawMessage, error) {
+	return nil, sql.ErrNoRows
+}
+
+func (r manifestRepository) GetFeaturesByID(ctx context.Context, ids ...int) (map[int]Feature, error) {
+	features := make(map[int]Feature, len(ids))
+	for _, id := range ids {
+		features[id] = Feature{ID: id, IsArchived: r.archived[id]}
+	}
+	return features, nil
+}
+
+func (r manifestRepository) GetTagDefinitionsByID(ctx context.Context, ids ...int) (map[int]TagDefinition, error) {
+	tags := make(map[int]TagDefinition, len(ids))
+	for _, id := range ids {
+		tags[id] = TagDefinition{ID: id}
+	}
+	return tags, nil
+}
+
+func TestPlanManifest(t *testing.T) {
+	managed := map[string]GetBannerResponse{
+		"keep": {
+			BannerID: 1, FeatureID: 1, TagIDs: []int{1}, IsActive: true,
+			Content: json.RawMessage(`{"title": "keep"}`), LocalizedContent: json.RawMessage(`{}`),
+		},
+		"edit": {
+			BannerID: 2, FeatureID: 1, TagIDs: []int{2}, IsActive: true,
+			Content: json.RawMessage(`{"title": "old"}`), LocalizedContent: json.RawMessage(`{}`),
+		},
+		"gone": {
+			BannerID: 3, FeatureID: 2, TagIDs: []int{3}, IsActive: true,
+			Content: json.RawMessage(`{"title": "gone"}`), LocalizedContent: json.RawMessage(`{}`),
+		},
+		"off": {
+			BannerID: 4, FeatureID: 2, TagIDs: []int{4}, IsActive: false,
+			Content: json.RawMessage(`{"title": "off"}`), LocalizedContent: json.RawMessage(`{}`),
+		},
+	}
+	s := &BannerService{repo: manifestRepository{managed: managed, archived: map[int]bool{9: true}}}
+
+	body := []byte(`
+name: promo
+banners:
+  - id: new
+    feature_id: 1
+    tag_ids: [5]
+    is_active: true
+    content: {title: new}
+  - id: edit
+    feature_id: 1
+    tag_ids: [2]
+    is_active: true
+    priority: 2
+    content: {title: new}
+  - id: keep
+    feature_id: 1
+    tag_ids: [1]
+    is_active: true
+    content: {title: keep}
+`)
+
+	plan, changes, err := s.planManifest(context.Background(), body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if plan.Unchanged != 1 {
+		t.Errorf("got %d unchanged, want 1", plan.Unchanged)
+	}
+	if len(plan.Create) != 1 || plan.Create[0].Id != "new" || plan.Create[0].BannerId != nil {
+		t.Errorf("got create %+v, want new", plan.Create)
+	}
+	if len(plan.Update) != 1 || plan.Update[0].Id != "edit" || *plan.Update[0].BannerId != 2 ||
+		!slices.Equal(*plan.Update[0].Fields, []string{"content", "priority"}) {
+		t.Errorf("got update %+v, want content and priority of edit", plan.Update)
+	}
+	// inactive banners dropped from the manifest are already off
+	if len(plan.Deactivate) != 1 || plan.Deactivate[0].Id != "gone" || *plan.Deactivate[0].BannerId != 3 {
+		t.Errorf("got deactivate %+v, want gone", plan.Deactivate)
+	}
+
+	if changes.Manifest != "promo" {
+		t.Errorf("got manifest %q, want promo", changes.Manifest)
+	}
+	if len(changes.Create) != 1 || changes.Create[0].ExternalID != "new" {
+		t.Errorf("got changes create %+v, want new", changes.Create)
+	}
+	if len(changes.Update) != 1 || changes.Update[0].ExternalID != "edit" || changes.Update[0].BannerID != 2 {
+		t.Errorf("got changes update %+v, want edit of banner 2", changes.Update)
+	}
+	if !slices.Equal(changes.Deactivate, []int{3}) {
+		t.Errorf("got changes deactivate %v, want [3]", changes.Deactivate)
+	}
+}
+
+func TestPlanManifestInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		// rows are the rows reported invalid, if the manifest is read at all
+		rows []int
+		// wantErr tells the error of the manifest that can not be read
+		wantErr func(error) bool
+	}{
+		{
+			name: "not yaml",
+			body: "banners: [",
+			wantErr: func(err error) bool {
+				var fileErr *InvalidFileError
+				return errors.As(err, &fileErr)
+			},
+		},
+		{
+			name: "no name",
+			body: "banners: []",
+			wantErr: func(err error) bool {
+				var fieldErr *MissingFieldError
+				return errors.As(err, &fieldErr) && fieldErr.Field == "name"
+			},
+		},
+		{
+			name: "invalid rows",
+			body: `
+name: promo
+banners:
+  - feature_id: 1
+    tag_ids: [1]
+    is_active: true
+    content: {title: no id}
+  - id: a
+    feature_id: 1
+    tag_ids: [1]
+    is_active: true
+    content: {title: a}
+  - id: a
+    feature_id: 1
+    tag_ids: [2]
+    is_active: true
+    content: {title: a again}
+  - id: b
+    feature_id: 9
+    tag_ids: [1]
+    is_active: true
+    content: {title: archived feature}
+  - id: c
+    feature_id: 1
+    tag_ids: [1]
+    content: {title: no is_active}
+`,
+			rows: []int{1, 3, 4, 5},
+		},
+	}
+
+	s := &BannerService{repo: manifestRepository{archived: map[int]bool{9: true}}}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := s.planManifest(context.Background(), []byte(tt.body))
+
+			if tt.wantErr != nil {
+				if !tt.wantErr(err) {
+					t.Fatalf("got error %v", err)
+				}
+				return
+			}
+
+			var rowsErr *InvalidRowsError
+			if !errors.As(err, &rowsErr) {
+				t.Fatalf("got error %v, want InvalidRowsError", err)
+			}
+			rows := make([]int, 0, len(rowsErr.Rows))
+			for _, r := range rowsErr.Rows {
+				rows = append(rows, r.Row)
+			}
+			if !slices.Equal(rows, tt.rows) {
+				t.Errorf("got invalid rows %v, want %v", rows, tt.rows)
+			}
+		})
+	}
+}
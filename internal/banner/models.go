@@ -5,25 +5,148 @@
 package banner
 
 import (
+	"database/sql"
+	"encoding/json"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 type Banner struct {
-	ID        int       `db:"id" json:"id"`
+	ID               int             `db:"id" json:"id"`
+	FeatureID        int             `db:"feature_id" json:"feature_id"`
+	IsActive         bool            `db:"is_active" json:"is_active"`
+	IsDeleted        bool            `db:"is_deleted" json:"is_deleted"`
+	CreatedAt        time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time       `db:"updated_at" json:"updated_at"`
+	Content          json.RawMessage `db:"content" json:"content"`
+	ActiveFrom       sql.NullTime    `db:"active_from" json:"active_from"`
+	ActiveUntil      sql.NullTime    `db:"active_until" json:"active_until"`
+	Priority         int             `db:"priority" json:"priority"`
+	LocalizedContent json.RawMessage `db:"localized_content" json:"localized_content"`
+	FrequencyCap     sql.NullInt32   `db:"frequency_cap" json:"frequency_cap"`
+	DeletedAt        sql.NullTime    `db:"deleted_at" json:"deleted_at"`
+}
+
+type BannerDeleteJob struct {
+	ID           uuid.UUID       `db:"id" json:"id"`
+	Status       string          `db:"status" json:"status"`
+	BannerIds    json.RawMessage `db:"banner_ids" json:"banner_ids"`
+	Results      json.RawMessage `db:"results" json:"results"`
+	ClaimID      uuid.NullUUID   `db:"claim_id" json:"claim_id"`
+	ClaimedUntil sql.NullTime    `db:"claimed_until" json:"claimed_until"`
+	CreatedAt    time.Time       `db:"created_at" json:"created_at"`
+	StartedAt    sql.NullTime    `db:"started_at" json:"started_at"`
+	FinishedAt   sql.NullTime    `db:"finished_at" json:"finished_at"`
+	FeatureID    sql.NullInt32   `db:"feature_id" json:"feature_id"`
+	TagID        sql.NullInt32   `db:"tag_id" json:"tag_id"`
+	Error        sql.NullString  `db:"error" json:"error"`
+}
+
+type BannerDraft struct {
+	ID            int             `db:"id" json:"id"`
+	BannerID      sql.NullInt32   `db:"banner_id" json:"banner_id"`
+	Payload       json.RawMessage `db:"payload" json:"payload"`
+	Status        string          `db:"status" json:"status"`
+	CreatedBy     int             `db:"created_by" json:"created_by"`
+	SubmittedBy   sql.NullInt32   `db:"submitted_by" json:"submitted_by"`
+	SubmittedAt   sql.NullTime    `db:"submitted_at" json:"submitted_at"`
+	ReviewedBy    sql.NullInt32   `db:"reviewed_by" json:"reviewed_by"`
+	ReviewedAt    sql.NullTime    `db:"reviewed_at" json:"reviewed_at"`
+	ReviewComment sql.NullString  `db:"review_comment" json:"review_comment"`
+	CreatedAt     time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time       `db:"updated_at" json:"updated_at"`
+}
+
+type BannerEvent struct {
+	ID        int64     `db:"id" json:"id"`
+	BannerID  int       `db:"banner_id" json:"banner_id"`
+	FeatureID int       `db:"feature_id" json:"feature_id"`
+	UserID    int       `db:"user_id" json:"user_id"`
+	Kind      string    `db:"kind" json:"kind"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+type BannerRevision struct {
+	ID               int             `db:"id" json:"id"`
+	BannerID         int             `db:"banner_id" json:"banner_id"`
+	Version          int             `db:"version" json:"version"`
+	FeatureID        int             `db:"feature_id" json:"feature_id"`
+	IsActive         bool            `db:"is_active" json:"is_active"`
+	TagIds           json.RawMessage `db:"tag_ids" json:"tag_ids"`
+	CreatedAt        time.Time       `db:"created_at" json:"created_at"`
+	Content          json.RawMessage `db:"content" json:"content"`
+	ActiveFrom       sql.NullTime    `db:"active_from" json:"active_from"`
+	ActiveUntil      sql.NullTime    `db:"active_until" json:"active_until"`
+	Priority         int             `db:"priority" json:"priority"`
+	LocalizedContent json.RawMessage `db:"localized_content" json:"localized_content"`
+	FrequencyCap     sql.NullInt32   `db:"frequency_cap" json:"frequency_cap"`
+}
+
+type Experiment struct {
+	ID        int           `db:"id" json:"id"`
+	FeatureID int           `db:"feature_id" json:"feature_id"`
+	TagID     int           `db:"tag_id" json:"tag_id"`
+	WinnerID  sql.NullInt32 `db:"winner_id" json:"winner_id"`
+	CreatedAt time.Time     `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time     `db:"updated_at" json:"updated_at"`
+	EndedAt   sql.NullTime  `db:"ended_at" json:"ended_at"`
+}
+
+type ExperimentVariant struct {
+	ExperimentID int `db:"experiment_id" json:"experiment_id"`
+	BannerID     int `db:"banner_id" json:"banner_id"`
+	Weight       int `db:"weight" json:"weight"`
+}
+
+type Feature struct {
+	ID          int       `db:"id" json:"id"`
+	Name        string    `db:"name" json:"name"`
+	Description string    `db:"description" json:"description"`
+	IsArchived  bool      `db:"is_archived" json:"is_archived"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+}
+
+type FeatureFallback struct {
 	FeatureID int       `db:"feature_id" json:"feature_id"`
-	Title     string    `db:"title" json:"title"`
-	Text      string    `db:"text" json:"text"`
-	Url       string    `db:"url" json:"url"`
-	IsActive  bool      `db:"is_active" json:"is_active"`
-	IsDeleted bool      `db:"is_deleted" json:"is_deleted"`
+	BannerID  int       `db:"banner_id" json:"banner_id"`
 	CreatedAt time.Time `db:"created_at" json:"created_at"`
 	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
 }
 
+type FeatureSchema struct {
+	FeatureID int             `db:"feature_id" json:"feature_id"`
+	Schema    json.RawMessage `db:"schema" json:"schema"`
+	CreatedAt time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time       `db:"updated_at" json:"updated_at"`
+}
+
+type ManagedBanner struct {
+	ExternalID string    `db:"external_id" json:"external_id"`
+	BannerID   int       `db:"banner_id" json:"banner_id"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
+	Manifest   string    `db:"manifest" json:"manifest"`
+}
+
 type Tag struct {
-	ID       int `db:"id" json:"id"`
-	TagID    int `db:"tag_id" json:"tag_id"`
-	BannerID int `db:"banner_id" json:"banner_id"`
+	ID          int           `db:"id" json:"id"`
+	TagID       int           `db:"tag_id" json:"tag_id"`
+	BannerID    int           `db:"banner_id" json:"banner_id"`
+	FeatureID   sql.NullInt32 `db:"feature_id" json:"feature_id"`
+	IsLive      bool          `db:"is_live" json:"is_live"`
+	ActiveFrom  sql.NullTime  `db:"active_from" json:"active_from"`
+	ActiveUntil sql.NullTime  `db:"active_until" json:"active_until"`
+}
+
+type TagDefinition struct {
+	ID          int       `db:"id" json:"id"`
+	Name        string    `db:"name" json:"name"`
+	Description string    `db:"description" json:"description"`
+	IsArchived  bool      `db:"is_archived" json:"is_archived"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
 }
 
 type User struct {
@@ -0,0 +1,94 @@
+package banner
+
+import (
+	"context"
+	"time"
+)
+
+// PurgeSummary counts the rows deleted for good by a purge,
+// or the rows a purge would delete on a dry run.
+type PurgeSummary struct {
+	Banners   int `json:"banners"`
+	Tags      int `json:"tags"`
+	Revisions int `json:"revisions"`
+}
+
+func (p *PurgeSummary) add(other PurgeSummary) {
+	p.Banners += other.Banners
+	p.Tags += other.Tags
+	p.Revisions += other.Revisions
+}
+
+// purge hard deletes banners soft-deleted longer than the retention ago,
+// then tags left without a banner and mock deletion tags. Every batch
+// is a transaction of its own, so that a long purge does not hold locks
+// for long and the batches done before a failure stay purged.
+func (s *BannerService) purge(ctx context.Context, dryRun bool) (PurgeSummary, error) {
+	if dryRun {
+		return s.repo.CountPurgeable(ctx, s.config.PurgeRetention)
+	}
+
+	var summary PurgeSummary
+
+	for {
+		batch, err := s.repo.PurgeBanners(ctx, s.config.PurgeRetention, s.config.PurgeBatchSize)
+		if err != nil {
+			return summary, err
+		}
+		summary.add(batch)
+
+		if batch.Banners < s.config.PurgeBatchSize {
+			break
+		}
+	}
+
+	for {
+		n, err := s.repo.PurgeOrphanedTags(ctx, s.config.PurgeBatchSize)
+		if err != nil {
+			return summary, err
+		}
+		summary.Tags += n
+
+		if n < s.config.PurgeBatchSize {
+			break
+		}
+	}
+
+	return summary, nil
+}
+
+func (s *BannerService) purgeDeleted() {
+	if s.config.PurgeInterval == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.config.PurgeInterval)
+	defer ticker.Stop()
+
+	// stopping the service interrupts the running batch
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-s.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	for {
+		select {
+		case <-s.done:
+			return
+
+		case <-ticker.C:
+			start := time.Now()
+			summary, err := s.purge(ctx, false)
+			if err != nil {
+				s.logger.Errorf("purge: %v", err)
+			}
+			s.logger.Infof("purge: %d banners, %d tags, %d revisions deleted in %s",
+				summary.Banners, summary.Tags, summary.Revisions, time.Since(start))
+		}
+	}
+}
@@ -10,21 +10,40 @@ WHERE
 SELECT
     b.id,
     b.feature_id,
-    b.title,
-    b.text,
-    b.url,
     b.is_active,
     b.is_deleted,
     b.created_at,
-    b.updated_at
+    b.updated_at,
+    b.content,
+    b.active_from,
+    b.active_until,
+    b.priority,
+    b.localized_content,
+    b.frequency_cap,
+    b.deleted_at
 FROM
     banners b
-    JOIN tags t ON t.banner_id = b.id
 WHERE
-    b.feature_id = $1
+    b.feature_id = sqlc.arg(feature_id)
     AND b.is_active = TRUE
     AND b.is_deleted = FALSE
-    AND t.tag_id = $2;
+    AND (b.active_from IS NULL
+        OR b.active_from <= CURRENT_TIMESTAMP)
+    AND (b.active_until IS NULL
+        OR b.active_until > CURRENT_TIMESTAMP)
+    AND EXISTS (
+        SELECT
+            1
+        FROM
+            tags t
+        WHERE
+            t.banner_id = b.id
+            AND t.tag_id = ANY (sqlc.arg(tag_ids)::INTEGER[]))
+    AND NOT (b.id = ANY (sqlc.arg(excluded_ids)::INTEGER[]))
+ORDER BY
+    b.priority DESC,
+    b.id
+LIMIT 1;
 
 -- name: GetBannerByID :one
 SELECT
@@ -34,236 +53,1458 @@ FROM
 WHERE
     id = $1;
 
--- name: GetBannersByFeature :many
+-- name: GetBanners :many
 SELECT
-    *
+    b.id,
+    b.feature_id,
+    b.is_active,
+    b.is_deleted,
+    b.created_at,
+    b.updated_at,
+    b.content,
+    b.active_from,
+    b.active_until,
+    b.priority,
+    b.localized_content,
+    b.frequency_cap,
+    COALESCE((
+        SELECT
+            jsonb_agg(t.tag_id ORDER BY t.id)
+        FROM tags t
+        WHERE
+            t.banner_id = b.id
+            AND t.tag_id <> -1), '[]')::JSONB AS tag_ids
 FROM
-    banners
+    banners b
 WHERE
-    feature_id = $1;
+    b.is_deleted = ANY (sqlc.arg(deleted)::BOOLEAN[])
+    AND (sqlc.narg(feature_id)::INTEGER IS NULL
+        OR b.feature_id = sqlc.narg(feature_id))
+    AND (sqlc.narg(tag_id)::INTEGER IS NULL
+        OR EXISTS (
+            SELECT
+                1
+            FROM
+                tags t
+            WHERE
+                t.banner_id = b.id
+                AND t.tag_id = sqlc.narg(tag_id)))
+    AND b.id > COALESCE(sqlc.narg(after_id)::INTEGER, 0)
+ORDER BY
+    b.id
+LIMIT sqlc.narg('limit') OFFSET sqlc.narg('offset');
 
--- name: GetTagsByBannerID :many
+-- name: GetBannersByIDDesc :many
 SELECT
-    *
+    b.id,
+    b.feature_id,
+    b.is_active,
+    b.is_deleted,
+    b.created_at,
+    b.updated_at,
+    b.content,
+    b.active_from,
+    b.active_until,
+    b.priority,
+    b.localized_content,
+    b.frequency_cap,
+    COALESCE((
+        SELECT
+            jsonb_agg(t.tag_id ORDER BY t.id)
+        FROM tags t
+        WHERE
+            t.banner_id = b.id
+            AND t.tag_id <> -1), '[]')::JSONB AS tag_ids
 FROM
-    tags
+    banners b
 WHERE
-    banner_id = $1;
+    b.is_deleted = ANY (sqlc.arg(deleted)::BOOLEAN[])
+    AND (sqlc.narg(feature_id)::INTEGER IS NULL
+        OR b.feature_id = sqlc.narg(feature_id))
+    AND (sqlc.narg(tag_id)::INTEGER IS NULL
+        OR EXISTS (
+            SELECT
+                1
+            FROM
+                tags t
+            WHERE
+                t.banner_id = b.id
+                AND t.tag_id = sqlc.narg(tag_id)))
+    AND b.id < COALESCE(sqlc.narg(after_id)::BIGINT, 2147483648)
+ORDER BY
+    b.id DESC
+LIMIT sqlc.narg('limit');
 
--- name: GetBannersIDsByTag :many
+-- name: GetBannersByCreatedAt :many
 SELECT
-    *
+    b.id,
+    b.feature_id,
+    b.is_active,
+    b.is_deleted,
+    b.created_at,
+    b.updated_at,
+    b.content,
+    b.active_from,
+    b.active_until,
+    b.priority,
+    b.localized_content,
+    b.frequency_cap,
+    COALESCE((
+        SELECT
+            jsonb_agg(t.tag_id ORDER BY t.id)
+        FROM tags t
+        WHERE
+            t.banner_id = b.id
+            AND t.tag_id <> -1), '[]')::JSONB AS tag_ids
 FROM
-    tags
+    banners b
 WHERE
-    tag_id = $1;
+    b.is_deleted = ANY (sqlc.arg(deleted)::BOOLEAN[])
+    AND (sqlc.narg(feature_id)::INTEGER IS NULL
+        OR b.feature_id = sqlc.narg(feature_id))
+    AND (sqlc.narg(tag_id)::INTEGER IS NULL
+        OR EXISTS (
+            SELECT
+                1
+            FROM
+                tags t
+            WHERE
+                t.banner_id = b.id
+                AND t.tag_id = sqlc.narg(tag_id)))
+    AND (b.created_at, b.id) > (COALESCE(sqlc.narg(after_time)::TIMESTAMP, '-infinity'), COALESCE(sqlc.narg(after_id)::INTEGER, 0))
+ORDER BY
+    b.created_at,
+    b.id
+LIMIT sqlc.narg('limit');
 
--- name: GetBannersByFeatureWithLimit :many
+-- name: GetBannersByCreatedAtDesc :many
 SELECT
-    *
+    b.id,
+    b.feature_id,
+    b.is_active,
+    b.is_deleted,
+    b.created_at,
+    b.updated_at,
+    b.content,
+    b.active_from,
+    b.active_until,
+    b.priority,
+    b.localized_content,
+    b.frequency_cap,
+    COALESCE((
+        SELECT
+            jsonb_agg(t.tag_id ORDER BY t.id)
+        FROM tags t
+        WHERE
+            t.banner_id = b.id
+            AND t.tag_id <> -1), '[]')::JSONB AS tag_ids
 FROM
-    banners
+    banners b
 WHERE
-    feature_id = $1
+    b.is_deleted = ANY (sqlc.arg(deleted)::BOOLEAN[])
+    AND (sqlc.narg(feature_id)::INTEGER IS NULL
+        OR b.feature_id = sqlc.narg(feature_id))
+    AND (sqlc.narg(tag_id)::INTEGER IS NULL
+        OR EXISTS (
+            SELECT
+                1
+            FROM
+                tags t
+            WHERE
+                t.banner_id = b.id
+                AND t.tag_id = sqlc.narg(tag_id)))
+    AND (b.created_at, b.id) < (COALESCE(sqlc.narg(after_time)::TIMESTAMP, 'infinity'), COALESCE(sqlc.narg(after_id)::INTEGER, 0))
 ORDER BY
-    id
-LIMIT $2;
+    b.created_at DESC,
+    b.id DESC
+LIMIT sqlc.narg('limit');
 
--- name: GetBannersByFeatureWithOffset :many
+-- name: GetBannersByUpdatedAt :many
 SELECT
-    *
+    b.id,
+    b.feature_id,
+    b.is_active,
+    b.is_deleted,
+    b.created_at,
+    b.updated_at,
+    b.content,
+    b.active_from,
+    b.active_until,
+    b.priority,
+    b.localized_content,
+    b.frequency_cap,
+    COALESCE((
+        SELECT
+            jsonb_agg(t.tag_id ORDER BY t.id)
+        FROM tags t
+        WHERE
+            t.banner_id = b.id
+            AND t.tag_id <> -1), '[]')::JSONB AS tag_ids
 FROM
-    banners
+    banners b
 WHERE
-    feature_id = $1
+    b.is_deleted = ANY (sqlc.arg(deleted)::BOOLEAN[])
+    AND (sqlc.narg(feature_id)::INTEGER IS NULL
+        OR b.feature_id = sqlc.narg(feature_id))
+    AND (sqlc.narg(tag_id)::INTEGER IS NULL
+        OR EXISTS (
+            SELECT
+                1
+            FROM
+                tags t
+            WHERE
+                t.banner_id = b.id
+                AND t.tag_id = sqlc.narg(tag_id)))
+    AND (b.updated_at, b.id) > (COALESCE(sqlc.narg(after_time)::TIMESTAMP, '-infinity'), COALESCE(sqlc.narg(after_id)::INTEGER, 0))
 ORDER BY
-    id OFFSET $2;
+    b.updated_at,
+    b.id
+LIMIT sqlc.narg('limit');
 
--- name: GetBannersByFeatureWithLimitOffset :many
+-- name: GetBannersByUpdatedAtDesc :many
 SELECT
-    *
+    b.id,
+    b.feature_id,
+    b.is_active,
+    b.is_deleted,
+    b.created_at,
+    b.updated_at,
+    b.content,
+    b.active_from,
+    b.active_until,
+    b.priority,
+    b.localized_content,
+    b.frequency_cap,
+    COALESCE((
+        SELECT
+            jsonb_agg(t.tag_id ORDER BY t.id)
+        FROM tags t
+        WHERE
+            t.banner_id = b.id
+            AND t.tag_id <> -1), '[]')::JSONB AS tag_ids
 FROM
-    banners
+    banners b
 WHERE
-    feature_id = $1
+    b.is_deleted = ANY (sqlc.arg(deleted)::BOOLEAN[])
+    AND (sqlc.narg(feature_id)::INTEGER IS NULL
+        OR b.feature_id = sqlc.narg(feature_id))
+    AND (sqlc.narg(tag_id)::INTEGER IS NULL
+        OR EXISTS (
+            SELECT
+                1
+            FROM
+                tags t
+            WHERE
+                t.banner_id = b.id
+                AND t.tag_id = sqlc.narg(tag_id)))
+    AND (b.updated_at, b.id) < (COALESCE(sqlc.narg(after_time)::TIMESTAMP, 'infinity'), COALESCE(sqlc.narg(after_id)::INTEGER, 0))
 ORDER BY
-    id
-LIMIT $2 OFFSET $3;
+    b.updated_at DESC,
+    b.id DESC
+LIMIT sqlc.narg('limit');
 
--- name: GetBannersIDsByTagWithLimit :many
+-- name: SearchBanners :many
 SELECT
-    *
+    b.id,
+    b.feature_id,
+    b.is_active,
+    b.is_deleted,
+    b.created_at,
+    b.updated_at,
+    b.content,
+    b.active_from,
+    b.active_until,
+    b.priority,
+    b.localized_content,
+    b.frequency_cap,
+    COALESCE((
+        SELECT
+            jsonb_agg(t.tag_id ORDER BY t.id)
+        FROM tags t
+        WHERE
+            t.banner_id = b.id
+            AND t.tag_id <> -1), '[]')::JSONB AS tag_ids
 FROM
-    tags
+    banners b
 WHERE
-    tag_id = $1
+    b.is_deleted = ANY (sqlc.arg(deleted)::BOOLEAN[])
+    AND (sqlc.narg(feature_id)::INTEGER IS NULL
+        OR b.feature_id = sqlc.narg(feature_id))
+    AND (sqlc.narg(tag_id)::INTEGER IS NULL
+        OR EXISTS (
+            SELECT
+                1
+            FROM
+                tags t
+            WHERE
+                t.banner_id = b.id
+                AND t.tag_id = sqlc.narg(tag_id)))
+    AND (jsonb_to_tsvector('simple', b.content, '["string"]') || jsonb_to_tsvector('simple', b.localized_content, '["string"]')) @@ websearch_to_tsquery('simple', sqlc.arg(q)::TEXT)
 ORDER BY
-    banner_id
-LIMIT $2;
+    ts_rank(jsonb_to_tsvector('simple', b.content, '["string"]') || jsonb_to_tsvector('simple', b.localized_content, '["string"]'), websearch_to_tsquery('simple', sqlc.arg(q))) DESC,
+    b.id
+LIMIT sqlc.narg('limit') OFFSET sqlc.narg('offset');
 
--- name: GetBannersIDsByTagWithOffset :many
+-- name: GetTagsByBannerID :many
 SELECT
     *
 FROM
     tags
 WHERE
+    banner_id = $1;
+
+-- name: CreateBanner :one
+INSERT INTO banners (feature_id, content, is_active, active_from, active_until, priority, localized_content, frequency_cap)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING
+    id;
+
+-- name: CreateTag :one
+INSERT INTO tags (tag_id, banner_id)
+    VALUES ($1, $2)
+RETURNING
+    tag_id;
+
+-- name: DeleteBannerByID :one
+UPDATE
+    banners
+SET
+    is_deleted = TRUE,
+    deleted_at = COALESCE(deleted_at, CURRENT_TIMESTAMP)
+WHERE
+    id = $1
+RETURNING
+    id;
+
+-- name: RestoreBannerByID :one
+UPDATE
+    banners
+SET
+    is_deleted = FALSE,
+    deleted_at = NULL
+WHERE
+    id = $1
+RETURNING
+    id;
+
+-- name: UpdateBannerTagByID :one
+UPDATE
+    tags
+SET
     tag_id = $1
+WHERE
+    banner_id = $2
+    and id = $3
+RETURNING
+    id;
+
+-- name: UpdateFeatureByID :one
+UPDATE
+    banners
+SET
+    feature_id = $1
+WHERE
+    id = $2
+RETURNING
+    id;
+
+-- name: UpdateBannerByID :one
+UPDATE
+    banners
+SET
+    content = $1
+WHERE
+    id = $2
+RETURNING
+    id;
+
+-- name: UpdateIsActiveByID :one
+UPDATE
+    banners
+SET
+    is_active = $1
+WHERE
+    id = $2
+RETURNING
+    id;
+
+
+-- name: UpdateScheduleByID :one
+UPDATE
+    banners
+SET
+    active_from = $1,
+    active_until = $2
+WHERE
+    id = $3
+RETURNING
+    id;
+
+-- name: UpdatePriorityByID :one
+UPDATE
+    banners
+SET
+    priority = $1
+WHERE
+    id = $2
+RETURNING
+    id;
+
+-- name: UpdateFrequencyCapByID :one
+UPDATE
+    banners
+SET
+    frequency_cap = $1
+WHERE
+    id = $2
+RETURNING
+    id;
+
+-- name: UpdateLocalizedContentByID :one
+UPDATE
+    banners
+SET
+    localized_content = $1
+WHERE
+    id = $2
+RETURNING
+    id;
+
+-- name: RollbackBannerByID :one
+UPDATE
+    banners
+SET
+    feature_id = $1,
+    content = $2,
+    is_active = $3,
+    active_from = $4,
+    active_until = $5,
+    priority = $6,
+    localized_content = $7,
+    frequency_cap = $8,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = $9
+RETURNING
+    id;
+
+-- name: CreateBannerRevision :one
+INSERT INTO banner_revisions (banner_id, version, feature_id, content, is_active, active_from, active_until, priority, localized_content, frequency_cap, tag_ids)
+SELECT
+    b.id,
+    COALESCE((
+        SELECT
+            MAX(r.version)
+        FROM banner_revisions r
+        WHERE
+            r.banner_id = b.id), 0) + 1,
+    b.feature_id,
+    b.content,
+    b.is_active,
+    b.active_from,
+    b.active_until,
+    b.priority,
+    b.localized_content,
+    b.frequency_cap,
+    COALESCE((
+        SELECT
+            jsonb_agg(t.tag_id ORDER BY t.id)
+        FROM tags t
+        WHERE
+            t.banner_id = b.id
+            AND t.tag_id <> -1), '[]')
+FROM
+    banners b
+WHERE
+    b.id = $1
+RETURNING
+    version;
+
+-- name: GetBannerRevisions :many
+SELECT
+    *
+FROM
+    banner_revisions
+WHERE
+    banner_id = $1
 ORDER BY
-    banner_id OFFSET $2;
+    version DESC;
+
+-- name: GetBannerRevision :one
+SELECT
+    *
+FROM
+    banner_revisions
+WHERE
+    banner_id = $1
+    AND version = $2;
 
--- name: GetBannersIDsByTagWithLimitOffset :many
+-- name: PruneBannerRevisions :exec
+DELETE FROM banner_revisions br
+WHERE br.banner_id = $1
+    AND br.version <= (
+        SELECT
+            MAX(r.version)
+        FROM
+            banner_revisions r
+        WHERE
+            r.banner_id = $1) - sqlc.arg(keep)::INTEGER;
+
+-- name: GetFeatureSchema :one
 SELECT
     *
+FROM
+    feature_schemas
+WHERE
+    feature_id = $1;
+
+-- name: UpsertFeatureSchema :one
+INSERT INTO feature_schemas (feature_id, schema)
+    VALUES ($1, $2)
+ON CONFLICT (feature_id)
+    DO UPDATE SET
+        schema = EXCLUDED.schema,
+        updated_at = CURRENT_TIMESTAMP
+    RETURNING
+        feature_id;
+
+-- name: GetConflictingBanners :many
+SELECT DISTINCT
+    banner_id
 FROM
     tags
 WHERE
-    tag_id = $1
+    is_live = TRUE
+    AND feature_id = sqlc.arg(feature_id)::INTEGER
+    AND tag_id = ANY (sqlc.arg(tag_ids)::INTEGER[])
+    AND tag_id <> -1
+    AND banner_id <> sqlc.arg(banner_id)
+    AND tstzrange(active_from, active_until) && tstzrange(sqlc.narg(active_from)::TIMESTAMPTZ, sqlc.narg(active_until)::TIMESTAMPTZ)
 ORDER BY
-    banner_id
-LIMIT $2 OFFSET $3;
+    banner_id;
 
--- name: GetBannersByFeatureTag :many
+-- name: GetVariantBanner :one
 SELECT
     b.id,
     b.feature_id,
-    b.title,
-    b.text,
-    b.url,
     b.is_active,
     b.is_deleted,
     b.created_at,
-    b.updated_at
+    b.updated_at,
+    b.content,
+    b.active_from,
+    b.active_until,
+    b.priority,
+    b.localized_content,
+    b.frequency_cap,
+    b.deleted_at
 FROM
     banners b
-    JOIN tags t ON t.banner_id = b.id
 WHERE
-    b.feature_id = $1
-    AND t.tag_id = $2;
+    b.id = $1
+    AND b.is_deleted = FALSE
+    AND (b.active_from IS NULL
+        OR b.active_from <= CURRENT_TIMESTAMP)
+    AND (b.active_until IS NULL
+        OR b.active_until > CURRENT_TIMESTAMP);
 
--- name: GetBannersByFeatureTagWithLimit :many
+-- name: GetClickableBanner :one
 SELECT
     b.id,
     b.feature_id,
-    b.title,
-    b.text,
-    b.url,
     b.is_active,
     b.is_deleted,
     b.created_at,
-    b.updated_at
+    b.updated_at,
+    b.content,
+    b.active_from,
+    b.active_until,
+    b.priority,
+    b.localized_content,
+    b.frequency_cap,
+    b.deleted_at
 FROM
     banners b
-    JOIN tags t ON t.banner_id = b.id
 WHERE
-    b.feature_id = $1
-    AND t.tag_id = $2
+    b.id = $1
+    AND b.is_deleted = FALSE
+    AND (b.active_from IS NULL
+        OR b.active_from <= CURRENT_TIMESTAMP)
+    AND (b.active_until IS NULL
+        OR b.active_until > CURRENT_TIMESTAMP)
+    AND (b.is_active = TRUE
+        OR EXISTS (
+            SELECT
+                1
+            FROM
+                experiment_variants ev
+                JOIN experiments e ON e.id = ev.experiment_id
+            WHERE
+                ev.banner_id = b.id
+                AND e.ended_at IS NULL));
+
+-- name: CreateExperiment :one
+INSERT INTO experiments (feature_id, tag_id)
+    VALUES ($1, $2)
+RETURNING
+    id;
+
+-- name: CreateExperimentVariant :one
+INSERT INTO experiment_variants (experiment_id, banner_id, weight)
+    VALUES ($1, $2, $3)
+RETURNING
+    banner_id;
+
+-- name: GetExperimentByID :one
+SELECT
+    *
+FROM
+    experiments
+WHERE
+    id = $1;
+
+-- name: GetRunningExperimentForUpdate :one
+SELECT
+    *
+FROM
+    experiments
+WHERE
+    id = $1
+    AND ended_at IS NULL
+FOR UPDATE;
+
+-- name: GetExperimentVariants :many
+SELECT
+    *
+FROM
+    experiment_variants
+WHERE
+    experiment_id = $1
 ORDER BY
-    b.id
-LIMIT $3;
+    banner_id;
 
--- name: GetBannersByFeatureTagWithOffset :many
+-- name: GetRunningExperimentVariants :many
+SELECT
+    ev.experiment_id,
+    ev.banner_id,
+    ev.weight
+FROM
+    experiment_variants ev
+WHERE
+    ev.experiment_id = (
+        SELECT
+            MIN(e.id)
+        FROM
+            experiments e
+        WHERE
+            e.feature_id = sqlc.arg(feature_id)
+            AND e.tag_id = ANY (sqlc.arg(tag_ids)::INTEGER[])
+            AND e.ended_at IS NULL)
+ORDER BY
+    ev.banner_id;
+
+-- name: UpdateExperimentVariantWeight :one
+UPDATE
+    experiment_variants
+SET
+    weight = $1
+WHERE
+    experiment_id = $2
+    AND banner_id = $3
+RETURNING
+    banner_id;
+
+-- name: TouchExperiment :one
+UPDATE
+    experiments
+SET
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = $1
+RETURNING
+    id;
+
+-- name: EndExperiment :one
+UPDATE
+    experiments
+SET
+    winner_id = $1,
+    ended_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = $2
+RETURNING
+    id;
+
+-- name: ReleaseSlot :many
+UPDATE
+    banners
+SET
+    is_active = FALSE
+WHERE
+    id IN (
+        SELECT
+            banner_id
+        FROM
+            tags
+        WHERE
+            is_live = TRUE
+            AND feature_id = sqlc.arg(feature_id)::INTEGER
+            AND tag_id = sqlc.arg(tag_id)
+            AND banner_id <> sqlc.arg(banner_id)
+            AND tstzrange(active_from, active_until) && tstzrange(sqlc.narg(active_from)::TIMESTAMPTZ, sqlc.narg(active_until)::TIMESTAMPTZ)
+    )
+RETURNING
+    id;
+
+-- name: GetFeatureFallback :one
+SELECT
+    *
+FROM
+    feature_fallbacks
+WHERE
+    feature_id = $1;
+
+-- name: UpsertFeatureFallback :one
+INSERT INTO feature_fallbacks (feature_id, banner_id)
+    VALUES ($1, $2)
+ON CONFLICT (feature_id)
+    DO UPDATE SET
+        banner_id = EXCLUDED.banner_id,
+        updated_at = CURRENT_TIMESTAMP
+    RETURNING
+        feature_id;
+
+-- name: PruneFeatureFallback :one
+DELETE FROM feature_fallbacks
+WHERE feature_id = $1
+RETURNING
+    feature_id;
+
+-- name: GetFallbackBanner :one
 SELECT
     b.id,
     b.feature_id,
-    b.title,
-    b.text,
-    b.url,
     b.is_active,
     b.is_deleted,
     b.created_at,
-    b.updated_at
+    b.updated_at,
+    b.content,
+    b.active_from,
+    b.active_until,
+    b.priority,
+    b.localized_content,
+    b.frequency_cap,
+    b.deleted_at
 FROM
-    banners b
-    JOIN tags t ON t.banner_id = b.id
+    feature_fallbacks f
+    JOIN banners b ON b.id = f.banner_id
+WHERE
+    f.feature_id = sqlc.arg(feature_id)
+    AND b.feature_id = f.feature_id
+    AND b.is_active = TRUE
+    AND b.is_deleted = FALSE
+    AND (b.active_from IS NULL
+        OR b.active_from <= CURRENT_TIMESTAMP)
+    AND (b.active_until IS NULL
+        OR b.active_until > CURRENT_TIMESTAMP)
+    AND NOT (b.id = ANY (sqlc.arg(excluded_ids)::INTEGER[]));
+
+-- name: CreateBannerDraft :one
+INSERT INTO banner_drafts (banner_id, payload, created_by)
+    VALUES ($1, $2, $3)
+RETURNING
+    id;
+
+-- name: GetBannerDraftByID :one
+SELECT
+    *
+FROM
+    banner_drafts
+WHERE
+    id = $1;
+
+-- name: GetBannerDraftForUpdate :one
+SELECT
+    *
+FROM
+    banner_drafts
+WHERE
+    id = $1
+FOR UPDATE;
+
+-- name: GetBannerDraftsByStatus :many
+SELECT
+    *
+FROM
+    banner_drafts
+WHERE
+    status = $1
+ORDER BY
+    id;
+
+-- name: SubmitBannerDraft :one
+UPDATE
+    banner_drafts
+SET
+    status = 'pending',
+    submitted_by = $1,
+    submitted_at = CURRENT_TIMESTAMP,
+    reviewed_by = NULL,
+    reviewed_at = NULL,
+    review_comment = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = $2
+RETURNING
+    id;
+
+-- name: ReviewBannerDraft :one
+UPDATE
+    banner_drafts
+SET
+    status = $1,
+    reviewed_by = $2,
+    reviewed_at = CURRENT_TIMESTAMP,
+    review_comment = $3,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = $4
+RETURNING
+    id;
+
+-- name: UpdateBannerDraftBannerID :one
+UPDATE
+    banner_drafts
+SET
+    banner_id = $1,
+    updated_at = CURRENT_TIMESTAMP
 WHERE
-    b.feature_id = $1
-    AND t.tag_id = $2
+    id = $2
+RETURNING
+    id;
+
+-- name: CreateBannerEvents :exec
+INSERT INTO banner_events (banner_id, feature_id, user_id, kind, created_at)
+SELECT
+    unnest(sqlc.arg(banner_ids)::INTEGER[]),
+    unnest(sqlc.arg(feature_ids)::INTEGER[]),
+    unnest(sqlc.arg(user_ids)::INTEGER[]),
+    unnest(sqlc.arg(kinds)::VARCHAR[]),
+    unnest(sqlc.arg(created_ats)::TIMESTAMP[]);
+
+-- name: GetBannerStats :many
+SELECT
+    e.banner_id,
+    e.feature_id,
+    COUNT(*) FILTER (WHERE e.kind = 'impression')::INTEGER AS impressions,
+    COUNT(*) FILTER (WHERE e.kind = 'click')::INTEGER AS clicks
+FROM
+    banner_events e
+WHERE (sqlc.narg(banner_id)::INTEGER IS NULL
+    OR e.banner_id = sqlc.narg(banner_id))
+AND (sqlc.narg(feature_id)::INTEGER IS NULL
+    OR e.feature_id = sqlc.narg(feature_id))
+AND (sqlc.narg(tag_id)::INTEGER IS NULL
+    OR EXISTS (
+        SELECT
+            1
+        FROM
+            tags t
+        WHERE
+            t.banner_id = e.banner_id
+            AND t.tag_id = sqlc.narg(tag_id)))
+AND (sqlc.narg(from_time)::TIMESTAMP IS NULL
+    OR e.created_at >= sqlc.narg(from_time))
+AND (sqlc.narg(until_time)::TIMESTAMP IS NULL
+    OR e.created_at < sqlc.narg(until_time))
+GROUP BY
+    e.banner_id,
+    e.feature_id
+ORDER BY
+    e.banner_id,
+    e.feature_id;
+
+-- name: GetFeatureStats :many
+SELECT
+    e.feature_id,
+    COUNT(*) FILTER (WHERE e.kind = 'impression')::INTEGER AS impressions,
+    COUNT(*) FILTER (WHERE e.kind = 'click')::INTEGER AS clicks
+FROM
+    banner_events e
+WHERE (sqlc.narg(banner_id)::INTEGER IS NULL
+    OR e.banner_id = sqlc.narg(banner_id))
+AND (sqlc.narg(feature_id)::INTEGER IS NULL
+    OR e.feature_id = sqlc.narg(feature_id))
+AND (sqlc.narg(tag_id)::INTEGER IS NULL
+    OR EXISTS (
+        SELECT
+            1
+        FROM
+            tags t
+        WHERE
+            t.banner_id = e.banner_id
+            AND t.tag_id = sqlc.narg(tag_id)))
+AND (sqlc.narg(from_time)::TIMESTAMP IS NULL
+    OR e.created_at >= sqlc.narg(from_time))
+AND (sqlc.narg(until_time)::TIMESTAMP IS NULL
+    OR e.created_at < sqlc.narg(until_time))
+GROUP BY
+    e.feature_id
+ORDER BY
+    e.feature_id;
+
+-- name: GetTagStats :many
+SELECT
+    t.tag_id,
+    COUNT(*) FILTER (WHERE e.kind = 'impression')::INTEGER AS impressions,
+    COUNT(*) FILTER (WHERE e.kind = 'click')::INTEGER AS clicks
+FROM
+    banner_events e
+    JOIN tags t ON t.banner_id = e.banner_id
+WHERE (sqlc.narg(banner_id)::INTEGER IS NULL
+    OR e.banner_id = sqlc.narg(banner_id))
+AND (sqlc.narg(feature_id)::INTEGER IS NULL
+    OR e.feature_id = sqlc.narg(feature_id))
+AND (sqlc.narg(tag_id)::INTEGER IS NULL
+    OR t.tag_id = sqlc.narg(tag_id))
+AND t.tag_id <> -1
+AND (sqlc.narg(from_time)::TIMESTAMP IS NULL
+    OR e.created_at >= sqlc.narg(from_time))
+AND (sqlc.narg(until_time)::TIMESTAMP IS NULL
+    OR e.created_at < sqlc.narg(until_time))
+GROUP BY
+    t.tag_id
 ORDER BY
-    b.id OFFSET $3;
+    t.tag_id;
 
--- name: GetBannersByFeatureTagWithLimitOffset :many
+-- name: GetBannersForExport :many
 SELECT
     b.id,
     b.feature_id,
-    b.title,
-    b.text,
-    b.url,
     b.is_active,
     b.is_deleted,
     b.created_at,
-    b.updated_at
+    b.updated_at,
+    b.content,
+    b.active_from,
+    b.active_until,
+    b.priority,
+    b.localized_content,
+    b.frequency_cap,
+    COALESCE((
+        SELECT
+            jsonb_agg(t.tag_id ORDER BY t.id)
+        FROM tags t
+        WHERE
+            t.banner_id = b.id
+            AND t.tag_id <> -1), '[]')::JSONB AS tag_ids
 FROM
     banners b
-    JOIN tags t ON t.banner_id = b.id
 WHERE
-    b.feature_id = $1
-    AND t.tag_id = $2
+    b.is_deleted = ANY (sqlc.arg(deleted)::BOOLEAN[])
+    AND b.id > sqlc.arg(after_id)
+    AND (sqlc.narg(feature_id)::INTEGER IS NULL
+        OR b.feature_id = sqlc.narg(feature_id))
+    AND (sqlc.narg(tag_id)::INTEGER IS NULL
+        OR EXISTS (
+            SELECT
+                1
+            FROM
+                tags t
+            WHERE
+                t.banner_id = b.id
+                AND t.tag_id = sqlc.narg(tag_id)))
 ORDER BY
     b.id
-LIMIT $3 OFFSET $4;
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
--- name: CreateBanner :one
-INSERT INTO banners (feature_id, title, text, url, is_active)
-    VALUES ($1, $2, $3, $4, $5)
-RETURNING
-    id;
+-- name: CountBanners :one
+SELECT
+    COUNT(*)
+FROM
+    banners b
+WHERE
+    b.is_deleted = ANY (sqlc.arg(deleted)::BOOLEAN[])
+    AND (sqlc.narg(feature_id)::INTEGER IS NULL
+        OR b.feature_id = sqlc.narg(feature_id))
+    AND (sqlc.narg(tag_id)::INTEGER IS NULL
+        OR EXISTS (
+            SELECT
+                1
+            FROM
+                tags t
+            WHERE
+                t.banner_id = b.id
+                AND t.tag_id = sqlc.narg(tag_id)));
 
--- name: CreateTag :one
-INSERT INTO tags (tag_id, banner_id)
-    VALUES ($1, $2)
-RETURNING
-    tag_id;
+-- name: CountSearchBanners :one
+SELECT
+    COUNT(*)
+FROM
+    banners b
+WHERE
+    b.is_deleted = ANY (sqlc.arg(deleted)::BOOLEAN[])
+    AND (sqlc.narg(feature_id)::INTEGER IS NULL
+        OR b.feature_id = sqlc.narg(feature_id))
+    AND (sqlc.narg(tag_id)::INTEGER IS NULL
+        OR EXISTS (
+            SELECT
+                1
+            FROM
+                tags t
+            WHERE
+                t.banner_id = b.id
+                AND t.tag_id = sqlc.narg(tag_id)))
+    AND (jsonb_to_tsvector('simple', b.content, '["string"]') || jsonb_to_tsvector('simple', b.localized_content, '["string"]')) @@ websearch_to_tsquery('simple', sqlc.arg(q)::TEXT);
 
--- name: DeleteBannerByID :one
+-- name: GetManagedBanners :many
+SELECT
+    m.external_id,
+    b.id,
+    b.feature_id,
+    b.is_active,
+    b.is_deleted,
+    b.created_at,
+    b.updated_at,
+    b.content,
+    b.active_from,
+    b.active_until,
+    b.priority,
+    b.localized_content,
+    b.frequency_cap,
+    COALESCE((
+        SELECT
+            jsonb_agg(t.tag_id ORDER BY t.id)
+        FROM tags t
+        WHERE
+            t.banner_id = b.id
+            AND t.tag_id <> -1), '[]')::JSONB AS tag_ids
+FROM
+    managed_banners m
+    JOIN banners b ON b.id = m.banner_id
+WHERE
+    m.manifest = sqlc.arg(manifest)
+    AND b.is_deleted = FALSE
+ORDER BY
+    m.external_id;
+
+-- name: UpdateManagedBannerByID :one
 UPDATE
-    banners
+    banners b
 SET
+    feature_id = sqlc.arg(feature_id),
+    content = sqlc.arg(content),
+    active_from = sqlc.narg(active_from),
+    active_until = sqlc.narg(active_until),
+    priority = sqlc.arg(priority),
+    localized_content = sqlc.arg(localized_content),
+    frequency_cap = sqlc.narg(frequency_cap),
+    is_active = FALSE,
+    updated_at = CURRENT_TIMESTAMP
+FROM
+    managed_banners m
+WHERE
+    b.id = sqlc.arg(id)
+    AND b.is_deleted = FALSE
+    AND m.banner_id = b.id
+    AND m.manifest = sqlc.arg(manifest)
+RETURNING
+    b.id;
+
+-- name: UpsertManagedBanner :one
+INSERT INTO managed_banners (manifest, external_id, banner_id)
+    VALUES ($1, $2, $3)
+ON CONFLICT (manifest, external_id)
+    DO UPDATE SET
+        banner_id = EXCLUDED.banner_id,
+        updated_at = CURRENT_TIMESTAMP
+    RETURNING
+        external_id;
+
+-- name: CountPurgeable :one
+SELECT
+    (
+        SELECT
+            COUNT(*)
+        FROM
+            banners b
+        WHERE
+            b.is_deleted = TRUE
+            AND b.deleted_at < CURRENT_TIMESTAMP - sqlc.arg(retention_secs)::BIGINT * INTERVAL '1 second') AS banners,
+    (
+        SELECT
+            COUNT(*)
+        FROM
+            tags t
+            LEFT JOIN banners b ON b.id = t.banner_id
+        WHERE
+            t.tag_id = -1
+            OR b.id IS NULL
+            OR (b.is_deleted = TRUE
+                AND b.deleted_at < CURRENT_TIMESTAMP - sqlc.arg(retention_secs)::BIGINT * INTERVAL '1 second')) AS tags,
+    (
+        SELECT
+            COUNT(*)
+        FROM
+            banner_revisions r
+            JOIN banners b ON b.id = r.banner_id
+        WHERE
+            b.is_deleted = TRUE
+            AND b.deleted_at < CURRENT_TIMESTAMP - sqlc.arg(retention_secs)::BIGINT * INTERVAL '1 second') AS revisions;
+
+-- name: GetPurgeableBannerIDs :many
+SELECT
+    id
+FROM
+    banners
+WHERE
     is_deleted = TRUE
+    AND deleted_at < CURRENT_TIMESTAMP - sqlc.arg(retention_secs)::BIGINT * INTERVAL '1 second'
+ORDER BY
+    id
+LIMIT sqlc.arg('limit')
+FOR UPDATE
+    SKIP LOCKED;
+
+-- name: PruneBanners :execrows
+DELETE FROM banners
+WHERE id = ANY (sqlc.arg(banner_ids)::INTEGER[])
+    AND is_deleted = TRUE;
+
+-- name: PruneTagsByBannerIDs :execrows
+DELETE FROM tags
+WHERE banner_id = ANY (sqlc.arg(banner_ids)::INTEGER[]);
+
+-- name: PruneBannerRevisionsByBannerIDs :execrows
+DELETE FROM banner_revisions
+WHERE banner_id = ANY (sqlc.arg(banner_ids)::INTEGER[]);
+
+-- name: PruneBannerDraftsByBannerIDs :execrows
+DELETE FROM banner_drafts
+WHERE banner_id = ANY (sqlc.arg(banner_ids)::INTEGER[]);
+
+-- name: PruneManagedBannersByBannerIDs :execrows
+DELETE FROM managed_banners
+WHERE banner_id = ANY (sqlc.arg(banner_ids)::INTEGER[]);
+
+-- name: PruneFeatureFallbacksByBannerIDs :execrows
+DELETE FROM feature_fallbacks
+WHERE banner_id = ANY (sqlc.arg(banner_ids)::INTEGER[]);
+
+-- name: PruneExperimentVariantsByBannerIDs :execrows
+DELETE FROM experiment_variants
+WHERE banner_id = ANY (sqlc.arg(banner_ids)::INTEGER[]);
+
+-- name: UnsetExperimentWinnersByBannerIDs :execrows
+UPDATE
+    experiments
+SET
+    winner_id = NULL
 WHERE
-    id = $1
+    winner_id = ANY (sqlc.arg(banner_ids)::INTEGER[]);
+
+-- name: PruneOrphanedTags :execrows
+DELETE FROM tags
+WHERE id IN (
+        SELECT
+            t.id
+        FROM
+            tags t
+            LEFT JOIN banners b ON b.id = t.banner_id
+        WHERE
+            t.tag_id = -1
+            OR b.id IS NULL
+        ORDER BY
+            t.id
+        LIMIT sqlc.arg('limit')
+        FOR UPDATE
+            OF t SKIP LOCKED);
+
+-- name: CreateFeature :one
+INSERT INTO features (id, name, description)
+    VALUES ($1, $2, $3)
 RETURNING
+    *;
+
+-- name: GetFeature :one
+SELECT
+    *
+FROM
+    features
+WHERE
+    id = $1;
+
+-- name: GetFeatures :many
+SELECT
+    *
+FROM
+    features
+WHERE
+    is_archived = FALSE
+    OR sqlc.arg(include_archived)::BOOLEAN
+ORDER BY
     id;
 
--- name: UpdateBannerTagByID :one
+-- name: GetFeaturesByIDs :many
+SELECT
+    *
+FROM
+    features
+WHERE
+    id = ANY (sqlc.arg(ids)::INTEGER[]);
+
+-- name: UpdateFeature :one
 UPDATE
-    tags
+    features
 SET
-    tag_id = $1
+    name = COALESCE(sqlc.narg(name), name),
+    description = COALESCE(sqlc.narg(description), description),
+    is_archived = COALESCE(sqlc.narg(is_archived), is_archived),
+    updated_at = CURRENT_TIMESTAMP
 WHERE
-    banner_id = $2
-    and id = $3
+    id = sqlc.arg(id)
+RETURNING
+    *;
+
+-- name: PruneFeature :one
+DELETE FROM features f
+WHERE f.id = $1
+    AND NOT EXISTS (
+        SELECT
+            1
+        FROM
+            banners b
+        WHERE
+            b.feature_id = f.id)
+RETURNING
+    f.id;
+
+-- name: CreateTagDefinition :one
+INSERT INTO tag_definitions (id, name, description)
+    VALUES ($1, $2, $3)
 RETURNING
+    *;
+
+-- name: GetTagDefinition :one
+SELECT
+    *
+FROM
+    tag_definitions
+WHERE
+    id = $1;
+
+-- name: GetTagDefinitions :many
+SELECT
+    *
+FROM
+    tag_definitions
+WHERE
+    is_archived = FALSE
+    OR sqlc.arg(include_archived)::BOOLEAN
+ORDER BY
     id;
 
--- name: UpdateFeatureByID :one
+-- name: GetTagDefinitionsByIDs :many
+SELECT
+    *
+FROM
+    tag_definitions
+WHERE
+    id = ANY (sqlc.arg(ids)::INTEGER[]);
+
+-- name: UpdateTagDefinition :one
 UPDATE
-    banners
+    tag_definitions
 SET
-    feature_id = $1
+    name = COALESCE(sqlc.narg(name), name),
+    description = COALESCE(sqlc.narg(description), description),
+    is_archived = COALESCE(sqlc.narg(is_archived), is_archived),
+    updated_at = CURRENT_TIMESTAMP
 WHERE
-    id = $2
+    id = sqlc.arg(id)
+RETURNING
+    *;
+
+-- name: PruneTagDefinition :one
+DELETE FROM tag_definitions d
+WHERE d.id = $1
+    AND NOT EXISTS (
+        SELECT
+            1
+        FROM
+            tags t
+        WHERE
+            t.tag_id = d.id)
+RETURNING
+    d.id;
+
+-- name: CreateBannerDeleteJob :one
+INSERT INTO banner_delete_jobs (id, banner_ids, feature_id, tag_id)
+SELECT
+    $1,
+    $2,
+    $3,
+    $4
+WHERE (
+    SELECT
+        COUNT(*)
+    FROM
+        banner_delete_jobs
+    WHERE
+        status IN ('queued', 'running')) < sqlc.arg(queue_limit)::INTEGER
+RETURNING
+    *;
+
+-- name: LockBannerDeleteJobQueue :exec
+SELECT
+    pg_advisory_xact_lock(hashtext('banner_delete_jobs'));
+
+-- name: GetBannerDeleteJob :one
+SELECT
+    *
+FROM
+    banner_delete_jobs
+WHERE
+    id = $1;
+
+-- name: ClaimBannerDeleteJob :one
+UPDATE
+    banner_delete_jobs
+SET
+    status = 'running',
+    started_at = COALESCE(started_at, CURRENT_TIMESTAMP),
+    claim_id = sqlc.arg(claim_id),
+    claimed_until = CURRENT_TIMESTAMP + sqlc.arg(lease_secs)::BIGINT * INTERVAL '1 second'
+WHERE
+    id IN (
+        SELECT
+            j.id
+        FROM
+            banner_delete_jobs j
+        WHERE
+            j.status = 'queued'
+            OR (j.status = 'running'
+                AND j.claimed_until < CURRENT_TIMESTAMP)
+        ORDER BY
+            j.created_at
+        LIMIT 1
+        FOR UPDATE
+            SKIP LOCKED)
+RETURNING
+    *;
+
+-- name: RenewBannerDeleteJob :one
+UPDATE
+    banner_delete_jobs
+SET
+    claimed_until = CURRENT_TIMESTAMP + sqlc.arg(lease_secs)::BIGINT * INTERVAL '1 second'
+WHERE
+    id = sqlc.arg(id)
+    AND claim_id = sqlc.arg(claim_id)
+    AND status = 'running'
+    AND claimed_until > CURRENT_TIMESTAMP
 RETURNING
     id;
 
--- name: UpdateBannerByID :one
+-- name: FinishBannerDeleteJob :execrows
+UPDATE
+    banner_delete_jobs
+SET
+    status = sqlc.arg(status),
+    banner_ids = banner_ids || sqlc.arg(banner_ids),
+    results = results || sqlc.arg(results),
+    error = sqlc.narg(error),
+    finished_at = CURRENT_TIMESTAMP,
+    claim_id = NULL,
+    claimed_until = NULL
+WHERE
+    id = sqlc.arg(id)
+    AND claim_id = sqlc.arg(claim_id);
+
+-- name: RecordBannerDeleteJobChunk :execrows
+UPDATE
+    banner_delete_jobs
+SET
+    banner_ids = banner_ids || sqlc.arg(banner_ids),
+    results = results || sqlc.arg(results)
+WHERE
+    id = sqlc.arg(id)
+    AND claim_id = sqlc.arg(claim_id);
+
+-- name: DeleteBannersByFilter :many
 UPDATE
     banners
 SET
-    title = $1,
-    text = $2,
-    url = $3
+    is_deleted = TRUE,
+    deleted_at = COALESCE(deleted_at, CURRENT_TIMESTAMP)
 WHERE
-    id = $4
+    id IN (
+        SELECT
+            b.id
+        FROM
+            banners b
+        WHERE
+            b.is_deleted = FALSE
+            AND (sqlc.narg(feature_id)::INTEGER IS NULL
+                OR b.feature_id = sqlc.narg(feature_id))
+            AND (sqlc.narg(tag_id)::INTEGER IS NULL
+                OR EXISTS (
+                    SELECT
+                        1
+                    FROM
+                        tags t
+                    WHERE
+                        t.banner_id = b.id
+                        AND t.tag_id = sqlc.narg(tag_id)))
+        ORDER BY
+            b.id
+        LIMIT sqlc.arg('limit')
+        FOR UPDATE)
 RETURNING
-    id;
+    id,
+    feature_id,
+    COALESCE((
+        SELECT
+            jsonb_agg(t.tag_id ORDER BY t.id)
+        FROM tags t
+        WHERE
+            t.banner_id = banners.id
+            AND t.tag_id <> -1), '[]')::JSONB AS tag_ids,
+    EXISTS (
+        SELECT
+            1
+        FROM
+            feature_fallbacks f
+        WHERE
+            f.banner_id = banners.id) AS is_fallback;
 
--- name: UpdateIsActiveByID :one
+-- name: DeleteBannersByIDs :many
 UPDATE
     banners
 SET
-    is_active = $1
+    is_deleted = TRUE,
+    deleted_at = COALESCE(deleted_at, CURRENT_TIMESTAMP)
 WHERE
-    id = $2
+    id = ANY (sqlc.arg(banner_ids)::INTEGER[])
 RETURNING
-    id;
+    id,
+    feature_id,
+    COALESCE((
+        SELECT
+            jsonb_agg(t.tag_id ORDER BY t.id)
+        FROM tags t
+        WHERE
+            t.banner_id = banners.id
+            AND t.tag_id <> -1), '[]')::JSONB AS tag_ids,
+    EXISTS (
+        SELECT
+            1
+        FROM
+            feature_fallbacks f
+        WHERE
+            f.banner_id = banners.id) AS is_fallback;
 
+-- name: PruneBannerDeleteJobs :execrows
+DELETE FROM banner_delete_jobs
+WHERE finished_at < CURRENT_TIMESTAMP - sqlc.arg(retention_secs)::BIGINT * INTERVAL '1 second';
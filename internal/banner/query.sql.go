@@ -7,169 +7,568 @@ package banner
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+const claimBannerDeleteJob = `-- name: ClaimBannerDeleteJob :one
+UPDATE
+    banner_delete_jobs
+SET
+    status = 'running',
+    started_at = COALESCE(started_at, CURRENT_TIMESTAMP),
+    claim_id = $1,
+    claimed_until = CURRENT_TIMESTAMP + $2::BIGINT * INTERVAL '1 second'
+WHERE
+    id IN (
+        SELECT
+            j.id
+        FROM
+            banner_delete_jobs j
+        WHERE
+            j.status = 'queued'
+            OR (j.status = 'running'
+                AND j.claimed_until < CURRENT_TIMESTAMP)
+        ORDER BY
+            j.created_at
+        LIMIT 1
+        FOR UPDATE
+            SKIP LOCKED)
+RETURNING
+    id, status, banner_ids, results, claim_id, claimed_until, created_at, started_at, finished_at, feature_id, tag_id, error
+`
+
+type ClaimBannerDeleteJobParams struct {
+	ClaimID   uuid.NullUUID `db:"claim_id" json:"claim_id"`
+	LeaseSecs int64         `db:"lease_secs" json:"lease_secs"`
+}
+
+func (q *Queries) ClaimBannerDeleteJob(ctx context.Context, arg ClaimBannerDeleteJobParams) (BannerDeleteJob, error) {
+	row := q.db.QueryRowContext(ctx, claimBannerDeleteJob, arg.ClaimID, arg.LeaseSecs)
+	var i BannerDeleteJob
+	err := row.Scan(
+		&i.ID,
+		&i.Status,
+		&i.BannerIds,
+		&i.Results,
+		&i.ClaimID,
+		&i.ClaimedUntil,
+		&i.CreatedAt,
+		&i.StartedAt,
+		&i.FinishedAt,
+		&i.FeatureID,
+		&i.TagID,
+		&i.Error,
+	)
+	return i, err
+}
+
+const countBanners = `-- name: CountBanners :one
+SELECT
+    COUNT(*)
+FROM
+    banners b
+WHERE
+    b.is_deleted = ANY ($1::BOOLEAN[])
+    AND ($2::INTEGER IS NULL
+        OR b.feature_id = $2)
+    AND ($3::INTEGER IS NULL
+        OR EXISTS (
+            SELECT
+                1
+            FROM
+                tags t
+            WHERE
+                t.banner_id = b.id
+                AND t.tag_id = $3))
+`
+
+type CountBannersParams struct {
+	Deleted   []bool        `db:"deleted" json:"deleted"`
+	FeatureID sql.NullInt32 `db:"feature_id" json:"feature_id"`
+	TagID     sql.NullInt32 `db:"tag_id" json:"tag_id"`
+}
+
+func (q *Queries) CountBanners(ctx context.Context, arg CountBannersParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countBanners,
+		pq.Array(arg.Deleted),
+		arg.FeatureID,
+		arg.TagID,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countPurgeable = `-- name: CountPurgeable :one
+SELECT
+    (
+        SELECT
+            COUNT(*)
+        FROM
+            banners b
+        WHERE
+            b.is_deleted = TRUE
+            AND b.deleted_at < CURRENT_TIMESTAMP - $1::BIGINT * INTERVAL '1 second') AS banners,
+    (
+        SELECT
+            COUNT(*)
+        FROM
+            tags t
+            LEFT JOIN banners b ON b.id = t.banner_id
+        WHERE
+            t.tag_id = -1
+            OR b.id IS NULL
+            OR (b.is_deleted = TRUE
+                AND b.deleted_at < CURRENT_TIMESTAMP - $1::BIGINT * INTERVAL '1 second')) AS tags,
+    (
+        SELECT
+            COUNT(*)
+        FROM
+            banner_revisions r
+            JOIN banners b ON b.id = r.banner_id
+        WHERE
+            b.is_deleted = TRUE
+            AND b.deleted_at < CURRENT_TIMESTAMP - $1::BIGINT * INTERVAL '1 second') AS revisions
+`
+
+type CountPurgeableRow struct {
+	Banners   int64 `db:"banners" json:"banners"`
+	Tags      int64 `db:"tags" json:"tags"`
+	Revisions int64 `db:"revisions" json:"revisions"`
+}
+
+func (q *Queries) CountPurgeable(ctx context.Context, retentionSecs int64) (CountPurgeableRow, error) {
+	row := q.db.QueryRowContext(ctx, countPurgeable, retentionSecs)
+	var i CountPurgeableRow
+	err := row.Scan(&i.Banners, &i.Tags, &i.Revisions)
+	return i, err
+}
+
+const countSearchBanners = `-- name: CountSearchBanners :one
+SELECT
+    COUNT(*)
+FROM
+    banners b
+WHERE
+    b.is_deleted = ANY ($1::BOOLEAN[])
+    AND ($2::INTEGER IS NULL
+        OR b.feature_id = $2)
+    AND ($3::INTEGER IS NULL
+        OR EXISTS (
+            SELECT
+                1
+            FROM
+                tags t
+            WHERE
+                t.banner_id = b.id
+                AND t.tag_id = $3))
+    AND (jsonb_to_tsvector('simple', b.content, '["string"]') || jsonb_to_tsvector('simple', b.localized_content, '["string"]')) @@ websearch_to_tsquery('simple', $4::TEXT)
+`
+
+type CountSearchBannersParams struct {
+	Deleted   []bool        `db:"deleted" json:"deleted"`
+	FeatureID sql.NullInt32 `db:"feature_id" json:"feature_id"`
+	TagID     sql.NullInt32 `db:"tag_id" json:"tag_id"`
+	Q         string        `db:"q" json:"q"`
+}
+
+func (q *Queries) CountSearchBanners(ctx context.Context, arg CountSearchBannersParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countSearchBanners,
+		pq.Array(arg.Deleted),
+		arg.FeatureID,
+		arg.TagID,
+		arg.Q,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createBanner = `-- name: CreateBanner :one
-INSERT INTO banners (feature_id, title, text, url, is_active)
-    VALUES ($1, $2, $3, $4, $5)
+INSERT INTO banners (feature_id, content, is_active, active_from, active_until, priority, localized_content, frequency_cap)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 RETURNING
     id
 `
 
 type CreateBannerParams struct {
-	FeatureID int    `db:"feature_id" json:"feature_id"`
-	Title     string `db:"title" json:"title"`
-	Text      string `db:"text" json:"text"`
-	Url       string `db:"url" json:"url"`
-	IsActive  bool   `db:"is_active" json:"is_active"`
+	FeatureID        int             `db:"feature_id" json:"feature_id"`
+	Content          json.RawMessage `db:"content" json:"content"`
+	IsActive         bool            `db:"is_active" json:"is_active"`
+	ActiveFrom       sql.NullTime    `db:"active_from" json:"active_from"`
+	ActiveUntil      sql.NullTime    `db:"active_until" json:"active_until"`
+	Priority         int             `db:"priority" json:"priority"`
+	LocalizedContent json.RawMessage `db:"localized_content" json:"localized_content"`
+	FrequencyCap     sql.NullInt32   `db:"frequency_cap" json:"frequency_cap"`
 }
 
 func (q *Queries) CreateBanner(ctx context.Context, arg CreateBannerParams) (int, error) {
 	row := q.db.QueryRowContext(ctx, createBanner,
 		arg.FeatureID,
-		arg.Title,
-		arg.Text,
-		arg.Url,
+		arg.Content,
 		arg.IsActive,
+		arg.ActiveFrom,
+		arg.ActiveUntil,
+		arg.Priority,
+		arg.LocalizedContent,
+		arg.FrequencyCap,
 	)
 	var id int
 	err := row.Scan(&id)
 	return id, err
 }
 
-const createTag = `-- name: CreateTag :one
-INSERT INTO tags (tag_id, banner_id)
-    VALUES ($1, $2)
+const createBannerDeleteJob = `-- name: CreateBannerDeleteJob :one
+INSERT INTO banner_delete_jobs (id, banner_ids, feature_id, tag_id)
+SELECT
+    $1,
+    $2,
+    $3,
+    $4
+WHERE (
+    SELECT
+        COUNT(*)
+    FROM
+        banner_delete_jobs
+    WHERE
+        status IN ('queued', 'running')) < $5::INTEGER
 RETURNING
-    tag_id
+    id, status, banner_ids, results, claim_id, claimed_until, created_at, started_at, finished_at, feature_id, tag_id, error
 `
 
-type CreateTagParams struct {
-	TagID    int `db:"tag_id" json:"tag_id"`
-	BannerID int `db:"banner_id" json:"banner_id"`
+type CreateBannerDeleteJobParams struct {
+	ID         uuid.UUID       `db:"id" json:"id"`
+	BannerIds  json.RawMessage `db:"banner_ids" json:"banner_ids"`
+	FeatureID  sql.NullInt32   `db:"feature_id" json:"feature_id"`
+	TagID      sql.NullInt32   `db:"tag_id" json:"tag_id"`
+	QueueLimit int             `db:"queue_limit" json:"queue_limit"`
 }
 
-func (q *Queries) CreateTag(ctx context.Context, arg CreateTagParams) (int, error) {
-	row := q.db.QueryRowContext(ctx, createTag, arg.TagID, arg.BannerID)
-	var tag_id int
-	err := row.Scan(&tag_id)
-	return tag_id, err
+func (q *Queries) CreateBannerDeleteJob(ctx context.Context, arg CreateBannerDeleteJobParams) (BannerDeleteJob, error) {
+	row := q.db.QueryRowContext(ctx, createBannerDeleteJob,
+		arg.ID,
+		arg.BannerIds,
+		arg.FeatureID,
+		arg.TagID,
+		arg.QueueLimit,
+	)
+	var i BannerDeleteJob
+	err := row.Scan(
+		&i.ID,
+		&i.Status,
+		&i.BannerIds,
+		&i.Results,
+		&i.ClaimID,
+		&i.ClaimedUntil,
+		&i.CreatedAt,
+		&i.StartedAt,
+		&i.FinishedAt,
+		&i.FeatureID,
+		&i.TagID,
+		&i.Error,
+	)
+	return i, err
 }
 
-const deleteBannerByID = `-- name: DeleteBannerByID :one
-UPDATE
-    banners
-SET
-    is_deleted = TRUE
-WHERE
-    id = $1
+const createBannerDraft = `-- name: CreateBannerDraft :one
+INSERT INTO banner_drafts (banner_id, payload, created_by)
+    VALUES ($1, $2, $3)
 RETURNING
     id
 `
 
-func (q *Queries) DeleteBannerByID(ctx context.Context, id int) (int, error) {
-	row := q.db.QueryRowContext(ctx, deleteBannerByID, id)
+type CreateBannerDraftParams struct {
+	BannerID  sql.NullInt32   `db:"banner_id" json:"banner_id"`
+	Payload   json.RawMessage `db:"payload" json:"payload"`
+	CreatedBy int             `db:"created_by" json:"created_by"`
+}
+
+func (q *Queries) CreateBannerDraft(ctx context.Context, arg CreateBannerDraftParams) (int, error) {
+	row := q.db.QueryRowContext(ctx, createBannerDraft, arg.BannerID, arg.Payload, arg.CreatedBy)
+	var id int
 	err := row.Scan(&id)
 	return id, err
 }
 
-const getActiveBannerByFeatureTag = `-- name: GetActiveBannerByFeatureTag :one
+const createBannerEvents = `-- name: CreateBannerEvents :exec
+INSERT INTO banner_events (banner_id, feature_id, user_id, kind, created_at)
+SELECT
+    unnest($1::INTEGER[]),
+    unnest($2::INTEGER[]),
+    unnest($3::INTEGER[]),
+    unnest($4::VARCHAR[]),
+    unnest($5::TIMESTAMP[])
+`
+
+type CreateBannerEventsParams struct {
+	BannerIds  []int       `db:"banner_ids" json:"banner_ids"`
+	FeatureIds []int       `db:"feature_ids" json:"feature_ids"`
+	UserIds    []int       `db:"user_ids" json:"user_ids"`
+	Kinds      []string    `db:"kinds" json:"kinds"`
+	CreatedAts []time.Time `db:"created_ats" json:"created_ats"`
+}
+
+func (q *Queries) CreateBannerEvents(ctx context.Context, arg CreateBannerEventsParams) error {
+	_, err := q.db.ExecContext(ctx, createBannerEvents,
+		pq.Array(arg.BannerIds),
+		pq.Array(arg.FeatureIds),
+		pq.Array(arg.UserIds),
+		pq.Array(arg.Kinds),
+		pq.Array(arg.CreatedAts),
+	)
+	return err
+}
+
+const createBannerRevision = `-- name: CreateBannerRevision :one
+INSERT INTO banner_revisions (banner_id, version, feature_id, content, is_active, active_from, active_until, priority, localized_content, frequency_cap, tag_ids)
 SELECT
     b.id,
+    COALESCE((
+        SELECT
+            MAX(r.version)
+        FROM banner_revisions r
+        WHERE
+            r.banner_id = b.id), 0) + 1,
     b.feature_id,
-    b.title,
-    b.text,
-    b.url,
+    b.content,
     b.is_active,
-    b.is_deleted,
-    b.created_at,
-    b.updated_at
+    b.active_from,
+    b.active_until,
+    b.priority,
+    b.localized_content,
+    b.frequency_cap,
+    COALESCE((
+        SELECT
+            jsonb_agg(t.tag_id ORDER BY t.id)
+        FROM tags t
+        WHERE
+            t.banner_id = b.id
+            AND t.tag_id <> -1), '[]')
 FROM
     banners b
-    JOIN tags t ON t.banner_id = b.id
 WHERE
-    b.feature_id = $1
-    AND b.is_active = TRUE
-    AND b.is_deleted = FALSE
-    AND t.tag_id = $2
+    b.id = $1
+RETURNING
+    version
 `
 
-type GetActiveBannerByFeatureTagParams struct {
+func (q *Queries) CreateBannerRevision(ctx context.Context, id int) (int, error) {
+	row := q.db.QueryRowContext(ctx, createBannerRevision, id)
+	var version int
+	err := row.Scan(&version)
+	return version, err
+}
+
+const createExperiment = `-- name: CreateExperiment :one
+INSERT INTO experiments (feature_id, tag_id)
+    VALUES ($1, $2)
+RETURNING
+    id
+`
+
+type CreateExperimentParams struct {
 	FeatureID int `db:"feature_id" json:"feature_id"`
 	TagID     int `db:"tag_id" json:"tag_id"`
 }
 
-func (q *Queries) GetActiveBannerByFeatureTag(ctx context.Context, arg GetActiveBannerByFeatureTagParams) (Banner, error) {
-	row := q.db.QueryRowContext(ctx, getActiveBannerByFeatureTag, arg.FeatureID, arg.TagID)
-	var i Banner
+func (q *Queries) CreateExperiment(ctx context.Context, arg CreateExperimentParams) (int, error) {
+	row := q.db.QueryRowContext(ctx, createExperiment, arg.FeatureID, arg.TagID)
+	var id int
+	err := row.Scan(&id)
+	return id, err
+}
+
+const createExperimentVariant = `-- name: CreateExperimentVariant :one
+INSERT INTO experiment_variants (experiment_id, banner_id, weight)
+    VALUES ($1, $2, $3)
+RETURNING
+    banner_id
+`
+
+type CreateExperimentVariantParams struct {
+	ExperimentID int `db:"experiment_id" json:"experiment_id"`
+	BannerID     int `db:"banner_id" json:"banner_id"`
+	Weight       int `db:"weight" json:"weight"`
+}
+
+func (q *Queries) CreateExperimentVariant(ctx context.Context, arg CreateExperimentVariantParams) (int, error) {
+	row := q.db.QueryRowContext(ctx, createExperimentVariant, arg.ExperimentID, arg.BannerID, arg.Weight)
+	var banner_id int
+	err := row.Scan(&banner_id)
+	return banner_id, err
+}
+
+const createFeature = `-- name: CreateFeature :one
+INSERT INTO features (id, name, description)
+    VALUES ($1, $2, $3)
+RETURNING
+    id, name, description, is_archived, created_at, updated_at
+`
+
+type CreateFeatureParams struct {
+	ID          int    `db:"id" json:"id"`
+	Name        string `db:"name" json:"name"`
+	Description string `db:"description" json:"description"`
+}
+
+func (q *Queries) CreateFeature(ctx context.Context, arg CreateFeatureParams) (Feature, error) {
+	row := q.db.QueryRowContext(ctx, createFeature, arg.ID, arg.Name, arg.Description)
+	var i Feature
 	err := row.Scan(
 		&i.ID,
-		&i.FeatureID,
-		&i.Title,
-		&i.Text,
-		&i.Url,
-		&i.IsActive,
-		&i.IsDeleted,
+		&i.Name,
+		&i.Description,
+		&i.IsArchived,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const getBannerByID = `-- name: GetBannerByID :one
-SELECT
-    id, feature_id, title, text, url, is_active, is_deleted, created_at, updated_at
-FROM
-    banners
-WHERE
-    id = $1
+const createTag = `-- name: CreateTag :one
+INSERT INTO tags (tag_id, banner_id)
+    VALUES ($1, $2)
+RETURNING
+    tag_id
 `
 
-func (q *Queries) GetBannerByID(ctx context.Context, id int) (Banner, error) {
-	row := q.db.QueryRowContext(ctx, getBannerByID, id)
-	var i Banner
+type CreateTagParams struct {
+	TagID    int `db:"tag_id" json:"tag_id"`
+	BannerID int `db:"banner_id" json:"banner_id"`
+}
+
+func (q *Queries) CreateTag(ctx context.Context, arg CreateTagParams) (int, error) {
+	row := q.db.QueryRowContext(ctx, createTag, arg.TagID, arg.BannerID)
+	var tag_id int
+	err := row.Scan(&tag_id)
+	return tag_id, err
+}
+
+const createTagDefinition = `-- name: CreateTagDefinition :one
+INSERT INTO tag_definitions (id, name, description)
+    VALUES ($1, $2, $3)
+RETURNING
+    id, name, description, is_archived, created_at, updated_at
+`
+
+type CreateTagDefinitionParams struct {
+	ID          int    `db:"id" json:"id"`
+	Name        string `db:"name" json:"name"`
+	Description string `db:"description" json:"description"`
+}
+
+func (q *Queries) CreateTagDefinition(ctx context.Context, arg CreateTagDefinitionParams) (TagDefinition, error) {
+	row := q.db.QueryRowContext(ctx, createTagDefinition, arg.ID, arg.Name, arg.Description)
+	var i TagDefinition
 	err := row.Scan(
 		&i.ID,
-		&i.FeatureID,
-		&i.Title,
-		&i.Text,
-		&i.Url,
-		&i.IsActive,
-		&i.IsDeleted,
+		&i.Name,
+		&i.Description,
+		&i.IsArchived,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const getBannersByFeature = `-- name: GetBannersByFeature :many
-SELECT
-    id, feature_id, title, text, url, is_active, is_deleted, created_at, updated_at
-FROM
+const deleteBannerByID = `-- name: DeleteBannerByID :one
+UPDATE
     banners
+SET
+    is_deleted = TRUE,
+    deleted_at = COALESCE(deleted_at, CURRENT_TIMESTAMP)
 WHERE
-    feature_id = $1
+    id = $1
+RETURNING
+    id
+`
+
+func (q *Queries) DeleteBannerByID(ctx context.Context, id int) (int, error) {
+	row := q.db.QueryRowContext(ctx, deleteBannerByID, id)
+	err := row.Scan(&id)
+	return id, err
+}
+
+const deleteBannersByFilter = `-- name: DeleteBannersByFilter :many
+UPDATE
+    banners
+SET
+    is_deleted = TRUE,
+    deleted_at = COALESCE(deleted_at, CURRENT_TIMESTAMP)
+WHERE
+    id IN (
+        SELECT
+            b.id
+        FROM
+            banners b
+        WHERE
+            b.is_deleted = FALSE
+            AND ($1::INTEGER IS NULL
+                OR b.feature_id = $1)
+            AND ($2::INTEGER IS NULL
+                OR EXISTS (
+                    SELECT
+                        1
+                    FROM
+                        tags t
+                    WHERE
+                        t.banner_id = b.id
+                        AND t.tag_id = $2))
+        ORDER BY
+            b.id
+        LIMIT $3
+        FOR UPDATE)
+RETURNING
+    id,
+    feature_id,
+    COALESCE((
+        SELECT
+            jsonb_agg(t.tag_id ORDER BY t.id)
+        FROM tags t
+        WHERE
+            t.banner_id = banners.id
+            AND t.tag_id <> -1), '[]')::JSONB AS tag_ids,
+    EXISTS (
+        SELECT
+            1
+        FROM
+            feature_fallbacks f
+        WHERE
+            f.banner_id = banners.id) AS is_fallback
 `
 
-func (q *Queries) GetBannersByFeature(ctx context.Context, featureID int) ([]Banner, error) {
-	rows, err := q.db.QueryContext(ctx, getBannersByFeature, featureID)
+type DeleteBannersByFilterParams struct {
+	FeatureID sql.NullInt32 `db:"feature_id" json:"feature_id"`
+	TagID     sql.NullInt32 `db:"tag_id" json:"tag_id"`
+	Limit     int           `db:"limit" json:"limit"`
+}
+
+type DeleteBannersByFilterRow struct {
+	ID         int             `db:"id" json:"id"`
+	FeatureID  int             `db:"feature_id" json:"feature_id"`
+	TagIds     json.RawMessage `db:"tag_ids" json:"tag_ids"`
+	IsFallback bool            `db:"is_fallback" json:"is_fallback"`
+}
+
+func (q *Queries) DeleteBannersByFilter(ctx context.Context, arg DeleteBannersByFilterParams) ([]DeleteBannersByFilterRow, error) {
+	rows, err := q.db.QueryContext(ctx, deleteBannersByFilter, arg.FeatureID, arg.TagID, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Banner
+	var items []DeleteBannersByFilterRow
 	for rows.Next() {
-		var i Banner
+		var i DeleteBannersByFilterRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.FeatureID,
-			&i.Title,
-			&i.Text,
-			&i.Url,
-			&i.IsActive,
-			&i.IsDeleted,
-			&i.CreatedAt,
-			&i.UpdatedAt,
+			&i.TagIds,
+			&i.IsFallback,
 		); err != nil {
 			return nil, err
 		}
@@ -184,49 +583,54 @@ func (q *Queries) GetBannersByFeature(ctx context.Context, featureID int) ([]Ban
 	return items, nil
 }
 
-const getBannersByFeatureTag = `-- name: GetBannersByFeatureTag :many
-SELECT
-    b.id,
-    b.feature_id,
-    b.title,
-    b.text,
-    b.url,
-    b.is_active,
-    b.is_deleted,
-    b.created_at,
-    b.updated_at
-FROM
-    banners b
-    JOIN tags t ON t.banner_id = b.id
+const deleteBannersByIDs = `-- name: DeleteBannersByIDs :many
+UPDATE
+    banners
+SET
+    is_deleted = TRUE,
+    deleted_at = COALESCE(deleted_at, CURRENT_TIMESTAMP)
 WHERE
-    b.feature_id = $1
-    AND t.tag_id = $2
+    id = ANY ($1::INTEGER[])
+RETURNING
+    id,
+    feature_id,
+    COALESCE((
+        SELECT
+            jsonb_agg(t.tag_id ORDER BY t.id)
+        FROM tags t
+        WHERE
+            t.banner_id = banners.id
+            AND t.tag_id <> -1), '[]')::JSONB AS tag_ids,
+    EXISTS (
+        SELECT
+            1
+        FROM
+            feature_fallbacks f
+        WHERE
+            f.banner_id = banners.id) AS is_fallback
 `
 
-type GetBannersByFeatureTagParams struct {
-	FeatureID int `db:"feature_id" json:"feature_id"`
-	TagID     int `db:"tag_id" json:"tag_id"`
+type DeleteBannersByIDsRow struct {
+	ID         int             `db:"id" json:"id"`
+	FeatureID  int             `db:"feature_id" json:"feature_id"`
+	TagIds     json.RawMessage `db:"tag_ids" json:"tag_ids"`
+	IsFallback bool            `db:"is_fallback" json:"is_fallback"`
 }
 
-func (q *Queries) GetBannersByFeatureTag(ctx context.Context, arg GetBannersByFeatureTagParams) ([]Banner, error) {
-	rows, err := q.db.QueryContext(ctx, getBannersByFeatureTag, arg.FeatureID, arg.TagID)
+func (q *Queries) DeleteBannersByIDs(ctx context.Context, bannerIds []int) ([]DeleteBannersByIDsRow, error) {
+	rows, err := q.db.QueryContext(ctx, deleteBannersByIDs, pq.Array(bannerIds))
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Banner
+	var items []DeleteBannersByIDsRow
 	for rows.Next() {
-		var i Banner
+		var i DeleteBannersByIDsRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.FeatureID,
-			&i.Title,
-			&i.Text,
-			&i.Url,
-			&i.IsActive,
-			&i.IsDeleted,
-			&i.CreatedAt,
-			&i.UpdatedAt,
+			&i.TagIds,
+			&i.IsFallback,
 		); err != nil {
 			return nil, err
 		}
@@ -241,51 +645,287 @@ func (q *Queries) GetBannersByFeatureTag(ctx context.Context, arg GetBannersByFe
 	return items, nil
 }
 
-const getBannersByFeatureTagWithLimit = `-- name: GetBannersByFeatureTagWithLimit :many
+const endExperiment = `-- name: EndExperiment :one
+UPDATE
+    experiments
+SET
+    winner_id = $1,
+    ended_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = $2
+RETURNING
+    id
+`
+
+type EndExperimentParams struct {
+	WinnerID sql.NullInt32 `db:"winner_id" json:"winner_id"`
+	ID       int           `db:"id" json:"id"`
+}
+
+func (q *Queries) EndExperiment(ctx context.Context, arg EndExperimentParams) (int, error) {
+	row := q.db.QueryRowContext(ctx, endExperiment, arg.WinnerID, arg.ID)
+	var id int
+	err := row.Scan(&id)
+	return id, err
+}
+
+const finishBannerDeleteJob = `-- name: FinishBannerDeleteJob :execrows
+UPDATE
+    banner_delete_jobs
+SET
+    status = $1,
+    banner_ids = banner_ids || $2,
+    results = results || $3,
+    error = $4,
+    finished_at = CURRENT_TIMESTAMP,
+    claim_id = NULL,
+    claimed_until = NULL
+WHERE
+    id = $5
+    AND claim_id = $6
+`
+
+type FinishBannerDeleteJobParams struct {
+	Status    string          `db:"status" json:"status"`
+	BannerIds json.RawMessage `db:"banner_ids" json:"banner_ids"`
+	Results   json.RawMessage `db:"results" json:"results"`
+	Error     sql.NullString  `db:"error" json:"error"`
+	ID        uuid.UUID       `db:"id" json:"id"`
+	ClaimID   uuid.NullUUID   `db:"claim_id" json:"claim_id"`
+}
+
+func (q *Queries) FinishBannerDeleteJob(ctx context.Context, arg FinishBannerDeleteJobParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, finishBannerDeleteJob,
+		arg.Status,
+		arg.BannerIds,
+		arg.Results,
+		arg.Error,
+		arg.ID,
+		arg.ClaimID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getActiveBannerByFeatureTag = `-- name: GetActiveBannerByFeatureTag :one
 SELECT
     b.id,
     b.feature_id,
-    b.title,
-    b.text,
-    b.url,
     b.is_active,
     b.is_deleted,
     b.created_at,
-    b.updated_at
+    b.updated_at,
+    b.content,
+    b.active_from,
+    b.active_until,
+    b.priority,
+    b.localized_content,
+    b.frequency_cap,
+    b.deleted_at
 FROM
     banners b
-    JOIN tags t ON t.banner_id = b.id
 WHERE
     b.feature_id = $1
-    AND t.tag_id = $2
+    AND b.is_active = TRUE
+    AND b.is_deleted = FALSE
+    AND (b.active_from IS NULL
+        OR b.active_from <= CURRENT_TIMESTAMP)
+    AND (b.active_until IS NULL
+        OR b.active_until > CURRENT_TIMESTAMP)
+    AND EXISTS (
+        SELECT
+            1
+        FROM
+            tags t
+        WHERE
+            t.banner_id = b.id
+            AND t.tag_id = ANY ($2::INTEGER[]))
+    AND NOT (b.id = ANY ($3::INTEGER[]))
 ORDER BY
+    b.priority DESC,
     b.id
-LIMIT $3
+LIMIT 1
 `
 
-type GetBannersByFeatureTagWithLimitParams struct {
-	FeatureID int `db:"feature_id" json:"feature_id"`
-	TagID     int `db:"tag_id" json:"tag_id"`
-	Limit     int `db:"limit" json:"limit"`
+type GetActiveBannerByFeatureTagParams struct {
+	FeatureID   int   `db:"feature_id" json:"feature_id"`
+	TagIds      []int `db:"tag_ids" json:"tag_ids"`
+	ExcludedIds []int `db:"excluded_ids" json:"excluded_ids"`
 }
 
-func (q *Queries) GetBannersByFeatureTagWithLimit(ctx context.Context, arg GetBannersByFeatureTagWithLimitParams) ([]Banner, error) {
-	rows, err := q.db.QueryContext(ctx, getBannersByFeatureTagWithLimit, arg.FeatureID, arg.TagID, arg.Limit)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []Banner
-	for rows.Next() {
-		var i Banner
+func (q *Queries) GetActiveBannerByFeatureTag(ctx context.Context, arg GetActiveBannerByFeatureTagParams) (Banner, error) {
+	row := q.db.QueryRowContext(ctx, getActiveBannerByFeatureTag, arg.FeatureID, pq.Array(arg.TagIds), pq.Array(arg.ExcludedIds))
+	var i Banner
+	err := row.Scan(
+		&i.ID,
+		&i.FeatureID,
+		&i.IsActive,
+		&i.IsDeleted,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Content,
+		&i.ActiveFrom,
+		&i.ActiveUntil,
+		&i.Priority,
+		&i.LocalizedContent,
+		&i.FrequencyCap,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const getBannerByID = `-- name: GetBannerByID :one
+SELECT
+    id, feature_id, is_active, is_deleted, created_at, updated_at, content, active_from, active_until, priority, localized_content, frequency_cap, deleted_at
+FROM
+    banners
+WHERE
+    id = $1
+`
+
+func (q *Queries) GetBannerByID(ctx context.Context, id int) (Banner, error) {
+	row := q.db.QueryRowContext(ctx, getBannerByID, id)
+	var i Banner
+	err := row.Scan(
+		&i.ID,
+		&i.FeatureID,
+		&i.IsActive,
+		&i.IsDeleted,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Content,
+		&i.ActiveFrom,
+		&i.ActiveUntil,
+		&i.Priority,
+		&i.LocalizedContent,
+		&i.FrequencyCap,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const getBannerDeleteJob = `-- name: GetBannerDeleteJob :one
+SELECT
+    id, status, banner_ids, results, claim_id, claimed_until, created_at, started_at, finished_at, feature_id, tag_id, error
+FROM
+    banner_delete_jobs
+WHERE
+    id = $1
+`
+
+func (q *Queries) GetBannerDeleteJob(ctx context.Context, id uuid.UUID) (BannerDeleteJob, error) {
+	row := q.db.QueryRowContext(ctx, getBannerDeleteJob, id)
+	var i BannerDeleteJob
+	err := row.Scan(
+		&i.ID,
+		&i.Status,
+		&i.BannerIds,
+		&i.Results,
+		&i.ClaimID,
+		&i.ClaimedUntil,
+		&i.CreatedAt,
+		&i.StartedAt,
+		&i.FinishedAt,
+		&i.FeatureID,
+		&i.TagID,
+		&i.Error,
+	)
+	return i, err
+}
+
+const getBannerDraftByID = `-- name: GetBannerDraftByID :one
+SELECT
+    id, banner_id, payload, status, created_by, submitted_by, submitted_at, reviewed_by, reviewed_at, review_comment, created_at, updated_at
+FROM
+    banner_drafts
+WHERE
+    id = $1
+`
+
+func (q *Queries) GetBannerDraftByID(ctx context.Context, id int) (BannerDraft, error) {
+	row := q.db.QueryRowContext(ctx, getBannerDraftByID, id)
+	var i BannerDraft
+	err := row.Scan(
+		&i.ID,
+		&i.BannerID,
+		&i.Payload,
+		&i.Status,
+		&i.CreatedBy,
+		&i.SubmittedBy,
+		&i.SubmittedAt,
+		&i.ReviewedBy,
+		&i.ReviewedAt,
+		&i.ReviewComment,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getBannerDraftForUpdate = `-- name: GetBannerDraftForUpdate :one
+SELECT
+    id, banner_id, payload, status, created_by, submitted_by, submitted_at, reviewed_by, reviewed_at, review_comment, created_at, updated_at
+FROM
+    banner_drafts
+WHERE
+    id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetBannerDraftForUpdate(ctx context.Context, id int) (BannerDraft, error) {
+	row := q.db.QueryRowContext(ctx, getBannerDraftForUpdate, id)
+	var i BannerDraft
+	err := row.Scan(
+		&i.ID,
+		&i.BannerID,
+		&i.Payload,
+		&i.Status,
+		&i.CreatedBy,
+		&i.SubmittedBy,
+		&i.SubmittedAt,
+		&i.ReviewedBy,
+		&i.ReviewedAt,
+		&i.ReviewComment,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getBannerDraftsByStatus = `-- name: GetBannerDraftsByStatus :many
+SELECT
+    id, banner_id, payload, status, created_by, submitted_by, submitted_at, reviewed_by, reviewed_at, review_comment, created_at, updated_at
+FROM
+    banner_drafts
+WHERE
+    status = $1
+ORDER BY
+    id
+`
+
+func (q *Queries) GetBannerDraftsByStatus(ctx context.Context, status string) ([]BannerDraft, error) {
+	rows, err := q.db.QueryContext(ctx, getBannerDraftsByStatus, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []BannerDraft
+	for rows.Next() {
+		var i BannerDraft
 		if err := rows.Scan(
 			&i.ID,
-			&i.FeatureID,
-			&i.Title,
-			&i.Text,
-			&i.Url,
-			&i.IsActive,
-			&i.IsDeleted,
+			&i.BannerID,
+			&i.Payload,
+			&i.Status,
+			&i.CreatedBy,
+			&i.SubmittedBy,
+			&i.SubmittedAt,
+			&i.ReviewedBy,
+			&i.ReviewedAt,
+			&i.ReviewComment,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 		); err != nil {
@@ -302,59 +942,76 @@ func (q *Queries) GetBannersByFeatureTagWithLimit(ctx context.Context, arg GetBa
 	return items, nil
 }
 
-const getBannersByFeatureTagWithLimitOffset = `-- name: GetBannersByFeatureTagWithLimitOffset :many
+const getBannerRevision = `-- name: GetBannerRevision :one
 SELECT
-    b.id,
-    b.feature_id,
-    b.title,
-    b.text,
-    b.url,
-    b.is_active,
-    b.is_deleted,
-    b.created_at,
-    b.updated_at
+    id, banner_id, version, feature_id, is_active, tag_ids, created_at, content, active_from, active_until, priority, localized_content, frequency_cap
 FROM
-    banners b
-    JOIN tags t ON t.banner_id = b.id
+    banner_revisions
 WHERE
-    b.feature_id = $1
-    AND t.tag_id = $2
-ORDER BY
-    b.id
-LIMIT $3 OFFSET $4
+    banner_id = $1
+    AND version = $2
 `
 
-type GetBannersByFeatureTagWithLimitOffsetParams struct {
-	FeatureID int `db:"feature_id" json:"feature_id"`
-	TagID     int `db:"tag_id" json:"tag_id"`
-	Limit     int `db:"limit" json:"limit"`
-	Offset    int `db:"offset" json:"offset"`
+type GetBannerRevisionParams struct {
+	BannerID int `db:"banner_id" json:"banner_id"`
+	Version  int `db:"version" json:"version"`
 }
 
-func (q *Queries) GetBannersByFeatureTagWithLimitOffset(ctx context.Context, arg GetBannersByFeatureTagWithLimitOffsetParams) ([]Banner, error) {
-	rows, err := q.db.QueryContext(ctx, getBannersByFeatureTagWithLimitOffset,
-		arg.FeatureID,
-		arg.TagID,
-		arg.Limit,
-		arg.Offset,
+func (q *Queries) GetBannerRevision(ctx context.Context, arg GetBannerRevisionParams) (BannerRevision, error) {
+	row := q.db.QueryRowContext(ctx, getBannerRevision, arg.BannerID, arg.Version)
+	var i BannerRevision
+	err := row.Scan(
+		&i.ID,
+		&i.BannerID,
+		&i.Version,
+		&i.FeatureID,
+		&i.IsActive,
+		&i.TagIds,
+		&i.CreatedAt,
+		&i.Content,
+		&i.ActiveFrom,
+		&i.ActiveUntil,
+		&i.Priority,
+		&i.LocalizedContent,
+		&i.FrequencyCap,
 	)
+	return i, err
+}
+
+const getBannerRevisions = `-- name: GetBannerRevisions :many
+SELECT
+    id, banner_id, version, feature_id, is_active, tag_ids, created_at, content, active_from, active_until, priority, localized_content, frequency_cap
+FROM
+    banner_revisions
+WHERE
+    banner_id = $1
+ORDER BY
+    version DESC
+`
+
+func (q *Queries) GetBannerRevisions(ctx context.Context, bannerID int) ([]BannerRevision, error) {
+	rows, err := q.db.QueryContext(ctx, getBannerRevisions, bannerID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Banner
+	var items []BannerRevision
 	for rows.Next() {
-		var i Banner
+		var i BannerRevision
 		if err := rows.Scan(
 			&i.ID,
+			&i.BannerID,
+			&i.Version,
 			&i.FeatureID,
-			&i.Title,
-			&i.Text,
-			&i.Url,
 			&i.IsActive,
-			&i.IsDeleted,
+			&i.TagIds,
 			&i.CreatedAt,
-			&i.UpdatedAt,
+			&i.Content,
+			&i.ActiveFrom,
+			&i.ActiveUntil,
+			&i.Priority,
+			&i.LocalizedContent,
+			&i.FrequencyCap,
 		); err != nil {
 			return nil, err
 		}
@@ -369,52 +1026,74 @@ func (q *Queries) GetBannersByFeatureTagWithLimitOffset(ctx context.Context, arg
 	return items, nil
 }
 
-const getBannersByFeatureTagWithOffset = `-- name: GetBannersByFeatureTagWithOffset :many
+const getBannerStats = `-- name: GetBannerStats :many
 SELECT
-    b.id,
-    b.feature_id,
-    b.title,
-    b.text,
-    b.url,
-    b.is_active,
-    b.is_deleted,
-    b.created_at,
-    b.updated_at
+    e.banner_id,
+    e.feature_id,
+    COUNT(*) FILTER (WHERE e.kind = 'impression')::INTEGER AS impressions,
+    COUNT(*) FILTER (WHERE e.kind = 'click')::INTEGER AS clicks
 FROM
-    banners b
-    JOIN tags t ON t.banner_id = b.id
-WHERE
-    b.feature_id = $1
-    AND t.tag_id = $2
+    banner_events e
+WHERE ($1::INTEGER IS NULL
+    OR e.banner_id = $1)
+AND ($2::INTEGER IS NULL
+    OR e.feature_id = $2)
+AND ($3::INTEGER IS NULL
+    OR EXISTS (
+        SELECT
+            1
+        FROM
+            tags t
+        WHERE
+            t.banner_id = e.banner_id
+            AND t.tag_id = $3))
+AND ($4::TIMESTAMP IS NULL
+    OR e.created_at >= $4)
+AND ($5::TIMESTAMP IS NULL
+    OR e.created_at < $5)
+GROUP BY
+    e.banner_id,
+    e.feature_id
 ORDER BY
-    b.id OFFSET $3
+    e.banner_id,
+    e.feature_id
 `
 
-type GetBannersByFeatureTagWithOffsetParams struct {
-	FeatureID int `db:"feature_id" json:"feature_id"`
-	TagID     int `db:"tag_id" json:"tag_id"`
-	Offset    int `db:"offset" json:"offset"`
+type GetBannerStatsParams struct {
+	BannerID  sql.NullInt32 `db:"banner_id" json:"banner_id"`
+	FeatureID sql.NullInt32 `db:"feature_id" json:"feature_id"`
+	TagID     sql.NullInt32 `db:"tag_id" json:"tag_id"`
+	FromTime  sql.NullTime  `db:"from_time" json:"from_time"`
+	UntilTime sql.NullTime  `db:"until_time" json:"until_time"`
 }
 
-func (q *Queries) GetBannersByFeatureTagWithOffset(ctx context.Context, arg GetBannersByFeatureTagWithOffsetParams) ([]Banner, error) {
-	rows, err := q.db.QueryContext(ctx, getBannersByFeatureTagWithOffset, arg.FeatureID, arg.TagID, arg.Offset)
+type GetBannerStatsRow struct {
+	BannerID    int `db:"banner_id" json:"banner_id"`
+	FeatureID   int `db:"feature_id" json:"feature_id"`
+	Impressions int `db:"impressions" json:"impressions"`
+	Clicks      int `db:"clicks" json:"clicks"`
+}
+
+func (q *Queries) GetBannerStats(ctx context.Context, arg GetBannerStatsParams) ([]GetBannerStatsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getBannerStats,
+		arg.BannerID,
+		arg.FeatureID,
+		arg.TagID,
+		arg.FromTime,
+		arg.UntilTime,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Banner
+	var items []GetBannerStatsRow
 	for rows.Next() {
-		var i Banner
+		var i GetBannerStatsRow
 		if err := rows.Scan(
-			&i.ID,
+			&i.BannerID,
 			&i.FeatureID,
-			&i.Title,
-			&i.Text,
-			&i.Url,
-			&i.IsActive,
-			&i.IsDeleted,
-			&i.CreatedAt,
-			&i.UpdatedAt,
+			&i.Impressions,
+			&i.Clicks,
 		); err != nil {
 			return nil, err
 		}
@@ -429,42 +1108,103 @@ func (q *Queries) GetBannersByFeatureTagWithOffset(ctx context.Context, arg GetB
 	return items, nil
 }
 
-const getBannersByFeatureWithLimit = `-- name: GetBannersByFeatureWithLimit :many
+const getBanners = `-- name: GetBanners :many
 SELECT
-    id, feature_id, title, text, url, is_active, is_deleted, created_at, updated_at
+    b.id,
+    b.feature_id,
+    b.is_active,
+    b.is_deleted,
+    b.created_at,
+    b.updated_at,
+    b.content,
+    b.active_from,
+    b.active_until,
+    b.priority,
+    b.localized_content,
+    b.frequency_cap,
+    COALESCE((
+        SELECT
+            jsonb_agg(t.tag_id ORDER BY t.id)
+        FROM tags t
+        WHERE
+            t.banner_id = b.id
+            AND t.tag_id <> -1), '[]')::JSONB AS tag_ids
 FROM
-    banners
+    banners b
 WHERE
-    feature_id = $1
+    b.is_deleted = ANY ($1::BOOLEAN[])
+    AND ($2::INTEGER IS NULL
+        OR b.feature_id = $2)
+    AND ($3::INTEGER IS NULL
+        OR EXISTS (
+            SELECT
+                1
+            FROM
+                tags t
+            WHERE
+                t.banner_id = b.id
+                AND t.tag_id = $3))
+    AND b.id > COALESCE($4::INTEGER, 0)
 ORDER BY
-    id
-LIMIT $2
+    b.id
+LIMIT $5 OFFSET $6
 `
 
-type GetBannersByFeatureWithLimitParams struct {
-	FeatureID int `db:"feature_id" json:"feature_id"`
-	Limit     int `db:"limit" json:"limit"`
+type GetBannersParams struct {
+	Deleted   []bool        `db:"deleted" json:"deleted"`
+	FeatureID sql.NullInt32 `db:"feature_id" json:"feature_id"`
+	TagID     sql.NullInt32 `db:"tag_id" json:"tag_id"`
+	AfterID   sql.NullInt32 `db:"after_id" json:"after_id"`
+	Limit     sql.NullInt32 `db:"limit" json:"limit"`
+	Offset    sql.NullInt32 `db:"offset" json:"offset"`
+}
+
+type GetBannersRow struct {
+	ID               int             `db:"id" json:"id"`
+	FeatureID        int             `db:"feature_id" json:"feature_id"`
+	IsActive         bool            `db:"is_active" json:"is_active"`
+	IsDeleted        bool            `db:"is_deleted" json:"is_deleted"`
+	CreatedAt        time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time       `db:"updated_at" json:"updated_at"`
+	Content          json.RawMessage `db:"content" json:"content"`
+	ActiveFrom       sql.NullTime    `db:"active_from" json:"active_from"`
+	ActiveUntil      sql.NullTime    `db:"active_until" json:"active_until"`
+	Priority         int             `db:"priority" json:"priority"`
+	LocalizedContent json.RawMessage `db:"localized_content" json:"localized_content"`
+	FrequencyCap     sql.NullInt32   `db:"frequency_cap" json:"frequency_cap"`
+	TagIds           json.RawMessage `db:"tag_ids" json:"tag_ids"`
 }
 
-func (q *Queries) GetBannersByFeatureWithLimit(ctx context.Context, arg GetBannersByFeatureWithLimitParams) ([]Banner, error) {
-	rows, err := q.db.QueryContext(ctx, getBannersByFeatureWithLimit, arg.FeatureID, arg.Limit)
+func (q *Queries) GetBanners(ctx context.Context, arg GetBannersParams) ([]GetBannersRow, error) {
+	rows, err := q.db.QueryContext(ctx, getBanners,
+		pq.Array(arg.Deleted),
+		arg.FeatureID,
+		arg.TagID,
+		arg.AfterID,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Banner
+	var items []GetBannersRow
 	for rows.Next() {
-		var i Banner
+		var i GetBannersRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.FeatureID,
-			&i.Title,
-			&i.Text,
-			&i.Url,
 			&i.IsActive,
 			&i.IsDeleted,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Content,
+			&i.ActiveFrom,
+			&i.ActiveUntil,
+			&i.Priority,
+			&i.LocalizedContent,
+			&i.FrequencyCap,
+			&i.TagIds,
 		); err != nil {
 			return nil, err
 		}
@@ -479,43 +1219,104 @@ func (q *Queries) GetBannersByFeatureWithLimit(ctx context.Context, arg GetBanne
 	return items, nil
 }
 
-const getBannersByFeatureWithLimitOffset = `-- name: GetBannersByFeatureWithLimitOffset :many
+const getBannersByCreatedAt = `-- name: GetBannersByCreatedAt :many
 SELECT
-    id, feature_id, title, text, url, is_active, is_deleted, created_at, updated_at
+    b.id,
+    b.feature_id,
+    b.is_active,
+    b.is_deleted,
+    b.created_at,
+    b.updated_at,
+    b.content,
+    b.active_from,
+    b.active_until,
+    b.priority,
+    b.localized_content,
+    b.frequency_cap,
+    COALESCE((
+        SELECT
+            jsonb_agg(t.tag_id ORDER BY t.id)
+        FROM tags t
+        WHERE
+            t.banner_id = b.id
+            AND t.tag_id <> -1), '[]')::JSONB AS tag_ids
 FROM
-    banners
+    banners b
 WHERE
-    feature_id = $1
+    b.is_deleted = ANY ($1::BOOLEAN[])
+    AND ($2::INTEGER IS NULL
+        OR b.feature_id = $2)
+    AND ($3::INTEGER IS NULL
+        OR EXISTS (
+            SELECT
+                1
+            FROM
+                tags t
+            WHERE
+                t.banner_id = b.id
+                AND t.tag_id = $3))
+    AND (b.created_at, b.id) > (COALESCE($4::TIMESTAMP, '-infinity'), COALESCE($5::INTEGER, 0))
 ORDER BY
-    id
-LIMIT $2 OFFSET $3
+    b.created_at,
+    b.id
+LIMIT $6
 `
 
-type GetBannersByFeatureWithLimitOffsetParams struct {
-	FeatureID int `db:"feature_id" json:"feature_id"`
-	Limit     int `db:"limit" json:"limit"`
-	Offset    int `db:"offset" json:"offset"`
+type GetBannersByCreatedAtParams struct {
+	Deleted   []bool        `db:"deleted" json:"deleted"`
+	FeatureID sql.NullInt32 `db:"feature_id" json:"feature_id"`
+	TagID     sql.NullInt32 `db:"tag_id" json:"tag_id"`
+	AfterTime sql.NullTime  `db:"after_time" json:"after_time"`
+	AfterID   sql.NullInt32 `db:"after_id" json:"after_id"`
+	Limit     sql.NullInt32 `db:"limit" json:"limit"`
+}
+
+type GetBannersByCreatedAtRow struct {
+	ID               int             `db:"id" json:"id"`
+	FeatureID        int             `db:"feature_id" json:"feature_id"`
+	IsActive         bool            `db:"is_active" json:"is_active"`
+	IsDeleted        bool            `db:"is_deleted" json:"is_deleted"`
+	CreatedAt        time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time       `db:"updated_at" json:"updated_at"`
+	Content          json.RawMessage `db:"content" json:"content"`
+	ActiveFrom       sql.NullTime    `db:"active_from" json:"active_from"`
+	ActiveUntil      sql.NullTime    `db:"active_until" json:"active_until"`
+	Priority         int             `db:"priority" json:"priority"`
+	LocalizedContent json.RawMessage `db:"localized_content" json:"localized_content"`
+	FrequencyCap     sql.NullInt32   `db:"frequency_cap" json:"frequency_cap"`
+	TagIds           json.RawMessage `db:"tag_ids" json:"tag_ids"`
 }
 
-func (q *Queries) GetBannersByFeatureWithLimitOffset(ctx context.Context, arg GetBannersByFeatureWithLimitOffsetParams) ([]Banner, error) {
-	rows, err := q.db.QueryContext(ctx, getBannersByFeatureWithLimitOffset, arg.FeatureID, arg.Limit, arg.Offset)
+func (q *Queries) GetBannersByCreatedAt(ctx context.Context, arg GetBannersByCreatedAtParams) ([]GetBannersByCreatedAtRow, error) {
+	rows, err := q.db.QueryContext(ctx, getBannersByCreatedAt,
+		pq.Array(arg.Deleted),
+		arg.FeatureID,
+		arg.TagID,
+		arg.AfterTime,
+		arg.AfterID,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Banner
+	var items []GetBannersByCreatedAtRow
 	for rows.Next() {
-		var i Banner
+		var i GetBannersByCreatedAtRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.FeatureID,
-			&i.Title,
-			&i.Text,
-			&i.Url,
 			&i.IsActive,
 			&i.IsDeleted,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Content,
+			&i.ActiveFrom,
+			&i.ActiveUntil,
+			&i.Priority,
+			&i.LocalizedContent,
+			&i.FrequencyCap,
+			&i.TagIds,
 		); err != nil {
 			return nil, err
 		}
@@ -530,41 +1331,104 @@ func (q *Queries) GetBannersByFeatureWithLimitOffset(ctx context.Context, arg Ge
 	return items, nil
 }
 
-const getBannersByFeatureWithOffset = `-- name: GetBannersByFeatureWithOffset :many
+const getBannersByCreatedAtDesc = `-- name: GetBannersByCreatedAtDesc :many
 SELECT
-    id, feature_id, title, text, url, is_active, is_deleted, created_at, updated_at
+    b.id,
+    b.feature_id,
+    b.is_active,
+    b.is_deleted,
+    b.created_at,
+    b.updated_at,
+    b.content,
+    b.active_from,
+    b.active_until,
+    b.priority,
+    b.localized_content,
+    b.frequency_cap,
+    COALESCE((
+        SELECT
+            jsonb_agg(t.tag_id ORDER BY t.id)
+        FROM tags t
+        WHERE
+            t.banner_id = b.id
+            AND t.tag_id <> -1), '[]')::JSONB AS tag_ids
 FROM
-    banners
+    banners b
 WHERE
-    feature_id = $1
+    b.is_deleted = ANY ($1::BOOLEAN[])
+    AND ($2::INTEGER IS NULL
+        OR b.feature_id = $2)
+    AND ($3::INTEGER IS NULL
+        OR EXISTS (
+            SELECT
+                1
+            FROM
+                tags t
+            WHERE
+                t.banner_id = b.id
+                AND t.tag_id = $3))
+    AND (b.created_at, b.id) < (COALESCE($4::TIMESTAMP, 'infinity'), COALESCE($5::INTEGER, 0))
 ORDER BY
-    id OFFSET $2
+    b.created_at DESC,
+    b.id DESC
+LIMIT $6
 `
 
-type GetBannersByFeatureWithOffsetParams struct {
-	FeatureID int `db:"feature_id" json:"feature_id"`
-	Offset    int `db:"offset" json:"offset"`
+type GetBannersByCreatedAtDescParams struct {
+	Deleted   []bool        `db:"deleted" json:"deleted"`
+	FeatureID sql.NullInt32 `db:"feature_id" json:"feature_id"`
+	TagID     sql.NullInt32 `db:"tag_id" json:"tag_id"`
+	AfterTime sql.NullTime  `db:"after_time" json:"after_time"`
+	AfterID   sql.NullInt32 `db:"after_id" json:"after_id"`
+	Limit     sql.NullInt32 `db:"limit" json:"limit"`
 }
 
-func (q *Queries) GetBannersByFeatureWithOffset(ctx context.Context, arg GetBannersByFeatureWithOffsetParams) ([]Banner, error) {
-	rows, err := q.db.QueryContext(ctx, getBannersByFeatureWithOffset, arg.FeatureID, arg.Offset)
+type GetBannersByCreatedAtDescRow struct {
+	ID               int             `db:"id" json:"id"`
+	FeatureID        int             `db:"feature_id" json:"feature_id"`
+	IsActive         bool            `db:"is_active" json:"is_active"`
+	IsDeleted        bool            `db:"is_deleted" json:"is_deleted"`
+	CreatedAt        time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time       `db:"updated_at" json:"updated_at"`
+	Content          json.RawMessage `db:"content" json:"content"`
+	ActiveFrom       sql.NullTime    `db:"active_from" json:"active_from"`
+	ActiveUntil      sql.NullTime    `db:"active_until" json:"active_until"`
+	Priority         int             `db:"priority" json:"priority"`
+	LocalizedContent json.RawMessage `db:"localized_content" json:"localized_content"`
+	FrequencyCap     sql.NullInt32   `db:"frequency_cap" json:"frequency_cap"`
+	TagIds           json.RawMessage `db:"tag_ids" json:"tag_ids"`
+}
+
+func (q *Queries) GetBannersByCreatedAtDesc(ctx context.Context, arg GetBannersByCreatedAtDescParams) ([]GetBannersByCreatedAtDescRow, error) {
+	rows, err := q.db.QueryContext(ctx, getBannersByCreatedAtDesc,
+		pq.Array(arg.Deleted),
+		arg.FeatureID,
+		arg.TagID,
+		arg.AfterTime,
+		arg.AfterID,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Banner
+	var items []GetBannersByCreatedAtDescRow
 	for rows.Next() {
-		var i Banner
+		var i GetBannersByCreatedAtDescRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.FeatureID,
-			&i.Title,
-			&i.Text,
-			&i.Url,
 			&i.IsActive,
 			&i.IsDeleted,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Content,
+			&i.ActiveFrom,
+			&i.ActiveUntil,
+			&i.Priority,
+			&i.LocalizedContent,
+			&i.FrequencyCap,
+			&i.TagIds,
 		); err != nil {
 			return nil, err
 		}
@@ -579,25 +1443,102 @@ func (q *Queries) GetBannersByFeatureWithOffset(ctx context.Context, arg GetBann
 	return items, nil
 }
 
-const getBannersIDsByTag = `-- name: GetBannersIDsByTag :many
+const getBannersByIDDesc = `-- name: GetBannersByIDDesc :many
 SELECT
-    id, tag_id, banner_id
+    b.id,
+    b.feature_id,
+    b.is_active,
+    b.is_deleted,
+    b.created_at,
+    b.updated_at,
+    b.content,
+    b.active_from,
+    b.active_until,
+    b.priority,
+    b.localized_content,
+    b.frequency_cap,
+    COALESCE((
+        SELECT
+            jsonb_agg(t.tag_id ORDER BY t.id)
+        FROM tags t
+        WHERE
+            t.banner_id = b.id
+            AND t.tag_id <> -1), '[]')::JSONB AS tag_ids
 FROM
-    tags
+    banners b
 WHERE
-    tag_id = $1
+    b.is_deleted = ANY ($1::BOOLEAN[])
+    AND ($2::INTEGER IS NULL
+        OR b.feature_id = $2)
+    AND ($3::INTEGER IS NULL
+        OR EXISTS (
+            SELECT
+                1
+            FROM
+                tags t
+            WHERE
+                t.banner_id = b.id
+                AND t.tag_id = $3))
+    AND b.id < COALESCE($4::BIGINT, 2147483648)
+ORDER BY
+    b.id DESC
+LIMIT $5
 `
 
-func (q *Queries) GetBannersIDsByTag(ctx context.Context, tagID int) ([]Tag, error) {
-	rows, err := q.db.QueryContext(ctx, getBannersIDsByTag, tagID)
+type GetBannersByIDDescParams struct {
+	Deleted   []bool        `db:"deleted" json:"deleted"`
+	FeatureID sql.NullInt32 `db:"feature_id" json:"feature_id"`
+	TagID     sql.NullInt32 `db:"tag_id" json:"tag_id"`
+	AfterID   sql.NullInt64 `db:"after_id" json:"after_id"`
+	Limit     sql.NullInt32 `db:"limit" json:"limit"`
+}
+
+type GetBannersByIDDescRow struct {
+	ID               int             `db:"id" json:"id"`
+	FeatureID        int             `db:"feature_id" json:"feature_id"`
+	IsActive         bool            `db:"is_active" json:"is_active"`
+	IsDeleted        bool            `db:"is_deleted" json:"is_deleted"`
+	CreatedAt        time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time       `db:"updated_at" json:"updated_at"`
+	Content          json.RawMessage `db:"content" json:"content"`
+	ActiveFrom       sql.NullTime    `db:"active_from" json:"active_from"`
+	ActiveUntil      sql.NullTime    `db:"active_until" json:"active_until"`
+	Priority         int             `db:"priority" json:"priority"`
+	LocalizedContent json.RawMessage `db:"localized_content" json:"localized_content"`
+	FrequencyCap     sql.NullInt32   `db:"frequency_cap" json:"frequency_cap"`
+	TagIds           json.RawMessage `db:"tag_ids" json:"tag_ids"`
+}
+
+func (q *Queries) GetBannersByIDDesc(ctx context.Context, arg GetBannersByIDDescParams) ([]GetBannersByIDDescRow, error) {
+	rows, err := q.db.QueryContext(ctx, getBannersByIDDesc,
+		pq.Array(arg.Deleted),
+		arg.FeatureID,
+		arg.TagID,
+		arg.AfterID,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Tag
+	var items []GetBannersByIDDescRow
 	for rows.Next() {
-		var i Tag
-		if err := rows.Scan(&i.ID, &i.TagID, &i.BannerID); err != nil {
+		var i GetBannersByIDDescRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FeatureID,
+			&i.IsActive,
+			&i.IsDeleted,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Content,
+			&i.ActiveFrom,
+			&i.ActiveUntil,
+			&i.Priority,
+			&i.LocalizedContent,
+			&i.FrequencyCap,
+			&i.TagIds,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -611,33 +1552,105 @@ func (q *Queries) GetBannersIDsByTag(ctx context.Context, tagID int) ([]Tag, err
 	return items, nil
 }
 
-const getBannersIDsByTagWithLimit = `-- name: GetBannersIDsByTagWithLimit :many
+const getBannersByUpdatedAt = `-- name: GetBannersByUpdatedAt :many
 SELECT
-    id, tag_id, banner_id
+    b.id,
+    b.feature_id,
+    b.is_active,
+    b.is_deleted,
+    b.created_at,
+    b.updated_at,
+    b.content,
+    b.active_from,
+    b.active_until,
+    b.priority,
+    b.localized_content,
+    b.frequency_cap,
+    COALESCE((
+        SELECT
+            jsonb_agg(t.tag_id ORDER BY t.id)
+        FROM tags t
+        WHERE
+            t.banner_id = b.id
+            AND t.tag_id <> -1), '[]')::JSONB AS tag_ids
 FROM
-    tags
+    banners b
 WHERE
-    tag_id = $1
+    b.is_deleted = ANY ($1::BOOLEAN[])
+    AND ($2::INTEGER IS NULL
+        OR b.feature_id = $2)
+    AND ($3::INTEGER IS NULL
+        OR EXISTS (
+            SELECT
+                1
+            FROM
+                tags t
+            WHERE
+                t.banner_id = b.id
+                AND t.tag_id = $3))
+    AND (b.updated_at, b.id) > (COALESCE($4::TIMESTAMP, '-infinity'), COALESCE($5::INTEGER, 0))
 ORDER BY
-    banner_id
-LIMIT $2
+    b.updated_at,
+    b.id
+LIMIT $6
 `
 
-type GetBannersIDsByTagWithLimitParams struct {
-	TagID int `db:"tag_id" json:"tag_id"`
-	Limit int `db:"limit" json:"limit"`
+type GetBannersByUpdatedAtParams struct {
+	Deleted   []bool        `db:"deleted" json:"deleted"`
+	FeatureID sql.NullInt32 `db:"feature_id" json:"feature_id"`
+	TagID     sql.NullInt32 `db:"tag_id" json:"tag_id"`
+	AfterTime sql.NullTime  `db:"after_time" json:"after_time"`
+	AfterID   sql.NullInt32 `db:"after_id" json:"after_id"`
+	Limit     sql.NullInt32 `db:"limit" json:"limit"`
 }
 
-func (q *Queries) GetBannersIDsByTagWithLimit(ctx context.Context, arg GetBannersIDsByTagWithLimitParams) ([]Tag, error) {
-	rows, err := q.db.QueryContext(ctx, getBannersIDsByTagWithLimit, arg.TagID, arg.Limit)
+type GetBannersByUpdatedAtRow struct {
+	ID               int             `db:"id" json:"id"`
+	FeatureID        int             `db:"feature_id" json:"feature_id"`
+	IsActive         bool            `db:"is_active" json:"is_active"`
+	IsDeleted        bool            `db:"is_deleted" json:"is_deleted"`
+	CreatedAt        time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time       `db:"updated_at" json:"updated_at"`
+	Content          json.RawMessage `db:"content" json:"content"`
+	ActiveFrom       sql.NullTime    `db:"active_from" json:"active_from"`
+	ActiveUntil      sql.NullTime    `db:"active_until" json:"active_until"`
+	Priority         int             `db:"priority" json:"priority"`
+	LocalizedContent json.RawMessage `db:"localized_content" json:"localized_content"`
+	FrequencyCap     sql.NullInt32   `db:"frequency_cap" json:"frequency_cap"`
+	TagIds           json.RawMessage `db:"tag_ids" json:"tag_ids"`
+}
+
+func (q *Queries) GetBannersByUpdatedAt(ctx context.Context, arg GetBannersByUpdatedAtParams) ([]GetBannersByUpdatedAtRow, error) {
+	rows, err := q.db.QueryContext(ctx, getBannersByUpdatedAt,
+		pq.Array(arg.Deleted),
+		arg.FeatureID,
+		arg.TagID,
+		arg.AfterTime,
+		arg.AfterID,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Tag
+	var items []GetBannersByUpdatedAtRow
 	for rows.Next() {
-		var i Tag
-		if err := rows.Scan(&i.ID, &i.TagID, &i.BannerID); err != nil {
+		var i GetBannersByUpdatedAtRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FeatureID,
+			&i.IsActive,
+			&i.IsDeleted,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Content,
+			&i.ActiveFrom,
+			&i.ActiveUntil,
+			&i.Priority,
+			&i.LocalizedContent,
+			&i.FrequencyCap,
+			&i.TagIds,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -651,34 +1664,216 @@ func (q *Queries) GetBannersIDsByTagWithLimit(ctx context.Context, arg GetBanner
 	return items, nil
 }
 
-const getBannersIDsByTagWithLimitOffset = `-- name: GetBannersIDsByTagWithLimitOffset :many
+const getBannersByUpdatedAtDesc = `-- name: GetBannersByUpdatedAtDesc :many
 SELECT
-    id, tag_id, banner_id
-FROM
-    tags
-WHERE
-    tag_id = $1
+    b.id,
+    b.feature_id,
+    b.is_active,
+    b.is_deleted,
+    b.created_at,
+    b.updated_at,
+    b.content,
+    b.active_from,
+    b.active_until,
+    b.priority,
+    b.localized_content,
+    b.frequency_cap,
+    COALESCE((
+        SELECT
+            jsonb_agg(t.tag_id ORDER BY t.id)
+        FROM tags t
+        WHERE
+            t.banner_id = b.id
+            AND t.tag_id <> -1), '[]')::JSONB AS tag_ids
+FROM
+    banners b
+WHERE
+    b.is_deleted = ANY ($1::BOOLEAN[])
+    AND ($2::INTEGER IS NULL
+        OR b.feature_id = $2)
+    AND ($3::INTEGER IS NULL
+        OR EXISTS (
+            SELECT
+                1
+            FROM
+                tags t
+            WHERE
+                t.banner_id = b.id
+                AND t.tag_id = $3))
+    AND (b.updated_at, b.id) < (COALESCE($4::TIMESTAMP, 'infinity'), COALESCE($5::INTEGER, 0))
 ORDER BY
-    banner_id
-LIMIT $2 OFFSET $3
+    b.updated_at DESC,
+    b.id DESC
+LIMIT $6
 `
 
-type GetBannersIDsByTagWithLimitOffsetParams struct {
-	TagID  int `db:"tag_id" json:"tag_id"`
-	Limit  int `db:"limit" json:"limit"`
-	Offset int `db:"offset" json:"offset"`
+type GetBannersByUpdatedAtDescParams struct {
+	Deleted   []bool        `db:"deleted" json:"deleted"`
+	FeatureID sql.NullInt32 `db:"feature_id" json:"feature_id"`
+	TagID     sql.NullInt32 `db:"tag_id" json:"tag_id"`
+	AfterTime sql.NullTime  `db:"after_time" json:"after_time"`
+	AfterID   sql.NullInt32 `db:"after_id" json:"after_id"`
+	Limit     sql.NullInt32 `db:"limit" json:"limit"`
+}
+
+type GetBannersByUpdatedAtDescRow struct {
+	ID               int             `db:"id" json:"id"`
+	FeatureID        int             `db:"feature_id" json:"feature_id"`
+	IsActive         bool            `db:"is_active" json:"is_active"`
+	IsDeleted        bool            `db:"is_deleted" json:"is_deleted"`
+	CreatedAt        time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time       `db:"updated_at" json:"updated_at"`
+	Content          json.RawMessage `db:"content" json:"content"`
+	ActiveFrom       sql.NullTime    `db:"active_from" json:"active_from"`
+	ActiveUntil      sql.NullTime    `db:"active_until" json:"active_until"`
+	Priority         int             `db:"priority" json:"priority"`
+	LocalizedContent json.RawMessage `db:"localized_content" json:"localized_content"`
+	FrequencyCap     sql.NullInt32   `db:"frequency_cap" json:"frequency_cap"`
+	TagIds           json.RawMessage `db:"tag_ids" json:"tag_ids"`
 }
 
-func (q *Queries) GetBannersIDsByTagWithLimitOffset(ctx context.Context, arg GetBannersIDsByTagWithLimitOffsetParams) ([]Tag, error) {
-	rows, err := q.db.QueryContext(ctx, getBannersIDsByTagWithLimitOffset, arg.TagID, arg.Limit, arg.Offset)
+func (q *Queries) GetBannersByUpdatedAtDesc(ctx context.Context, arg GetBannersByUpdatedAtDescParams) ([]GetBannersByUpdatedAtDescRow, error) {
+	rows, err := q.db.QueryContext(ctx, getBannersByUpdatedAtDesc,
+		pq.Array(arg.Deleted),
+		arg.FeatureID,
+		arg.TagID,
+		arg.AfterTime,
+		arg.AfterID,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Tag
+	var items []GetBannersByUpdatedAtDescRow
 	for rows.Next() {
-		var i Tag
-		if err := rows.Scan(&i.ID, &i.TagID, &i.BannerID); err != nil {
+		var i GetBannersByUpdatedAtDescRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FeatureID,
+			&i.IsActive,
+			&i.IsDeleted,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Content,
+			&i.ActiveFrom,
+			&i.ActiveUntil,
+			&i.Priority,
+			&i.LocalizedContent,
+			&i.FrequencyCap,
+			&i.TagIds,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getBannersForExport = `-- name: GetBannersForExport :many
+SELECT
+    b.id,
+    b.feature_id,
+    b.is_active,
+    b.is_deleted,
+    b.created_at,
+    b.updated_at,
+    b.content,
+    b.active_from,
+    b.active_until,
+    b.priority,
+    b.localized_content,
+    b.frequency_cap,
+    COALESCE((
+        SELECT
+            jsonb_agg(t.tag_id ORDER BY t.id)
+        FROM tags t
+        WHERE
+            t.banner_id = b.id
+            AND t.tag_id <> -1), '[]')::JSONB AS tag_ids
+FROM
+    banners b
+WHERE
+    b.is_deleted = ANY ($1::BOOLEAN[])
+    AND b.id > $2
+    AND ($3::INTEGER IS NULL
+        OR b.feature_id = $3)
+    AND ($4::INTEGER IS NULL
+        OR EXISTS (
+            SELECT
+                1
+            FROM
+                tags t
+            WHERE
+                t.banner_id = b.id
+                AND t.tag_id = $4))
+ORDER BY
+    b.id
+LIMIT $5 OFFSET $6
+`
+
+type GetBannersForExportParams struct {
+	Deleted   []bool        `db:"deleted" json:"deleted"`
+	AfterID   int           `db:"after_id" json:"after_id"`
+	FeatureID sql.NullInt32 `db:"feature_id" json:"feature_id"`
+	TagID     sql.NullInt32 `db:"tag_id" json:"tag_id"`
+	Limit     int           `db:"limit" json:"limit"`
+	Offset    int           `db:"offset" json:"offset"`
+}
+
+type GetBannersForExportRow struct {
+	ID               int             `db:"id" json:"id"`
+	FeatureID        int             `db:"feature_id" json:"feature_id"`
+	IsActive         bool            `db:"is_active" json:"is_active"`
+	IsDeleted        bool            `db:"is_deleted" json:"is_deleted"`
+	CreatedAt        time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time       `db:"updated_at" json:"updated_at"`
+	Content          json.RawMessage `db:"content" json:"content"`
+	ActiveFrom       sql.NullTime    `db:"active_from" json:"active_from"`
+	ActiveUntil      sql.NullTime    `db:"active_until" json:"active_until"`
+	Priority         int             `db:"priority" json:"priority"`
+	LocalizedContent json.RawMessage `db:"localized_content" json:"localized_content"`
+	FrequencyCap     sql.NullInt32   `db:"frequency_cap" json:"frequency_cap"`
+	TagIds           json.RawMessage `db:"tag_ids" json:"tag_ids"`
+}
+
+func (q *Queries) GetBannersForExport(ctx context.Context, arg GetBannersForExportParams) ([]GetBannersForExportRow, error) {
+	rows, err := q.db.QueryContext(ctx, getBannersForExport,
+		pq.Array(arg.Deleted),
+		arg.AfterID,
+		arg.FeatureID,
+		arg.TagID,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetBannersForExportRow
+	for rows.Next() {
+		var i GetBannersForExportRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FeatureID,
+			&i.IsActive,
+			&i.IsDeleted,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Content,
+			&i.ActiveFrom,
+			&i.ActiveUntil,
+			&i.Priority,
+			&i.LocalizedContent,
+			&i.FrequencyCap,
+			&i.TagIds,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -692,32 +1887,1475 @@ func (q *Queries) GetBannersIDsByTagWithLimitOffset(ctx context.Context, arg Get
 	return items, nil
 }
 
-const getBannersIDsByTagWithOffset = `-- name: GetBannersIDsByTagWithOffset :many
+const getClickableBanner = `-- name: GetClickableBanner :one
 SELECT
-    id, tag_id, banner_id
+    b.id,
+    b.feature_id,
+    b.is_active,
+    b.is_deleted,
+    b.created_at,
+    b.updated_at,
+    b.content,
+    b.active_from,
+    b.active_until,
+    b.priority,
+    b.localized_content,
+    b.frequency_cap,
+    b.deleted_at
+FROM
+    banners b
+WHERE
+    b.id = $1
+    AND b.is_deleted = FALSE
+    AND (b.active_from IS NULL
+        OR b.active_from <= CURRENT_TIMESTAMP)
+    AND (b.active_until IS NULL
+        OR b.active_until > CURRENT_TIMESTAMP)
+    AND (b.is_active = TRUE
+        OR EXISTS (
+            SELECT
+                1
+            FROM
+                experiment_variants ev
+                JOIN experiments e ON e.id = ev.experiment_id
+            WHERE
+                ev.banner_id = b.id
+                AND e.ended_at IS NULL))
+`
+
+func (q *Queries) GetClickableBanner(ctx context.Context, id int) (Banner, error) {
+	row := q.db.QueryRowContext(ctx, getClickableBanner, id)
+	var i Banner
+	err := row.Scan(
+		&i.ID,
+		&i.FeatureID,
+		&i.IsActive,
+		&i.IsDeleted,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Content,
+		&i.ActiveFrom,
+		&i.ActiveUntil,
+		&i.Priority,
+		&i.LocalizedContent,
+		&i.FrequencyCap,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const getConflictingBanners = `-- name: GetConflictingBanners :many
+SELECT DISTINCT
+    banner_id
 FROM
     tags
 WHERE
-    tag_id = $1
+    is_live = TRUE
+    AND feature_id = $1::INTEGER
+    AND tag_id = ANY ($2::INTEGER[])
+    AND tag_id <> -1
+    AND banner_id <> $3
+    AND tstzrange(active_from, active_until) && tstzrange($4::TIMESTAMPTZ, $5::TIMESTAMPTZ)
+ORDER BY
+    banner_id
+`
+
+type GetConflictingBannersParams struct {
+	FeatureID   int          `db:"feature_id" json:"feature_id"`
+	TagIds      []int        `db:"tag_ids" json:"tag_ids"`
+	BannerID    int          `db:"banner_id" json:"banner_id"`
+	ActiveFrom  sql.NullTime `db:"active_from" json:"active_from"`
+	ActiveUntil sql.NullTime `db:"active_until" json:"active_until"`
+}
+
+func (q *Queries) GetConflictingBanners(ctx context.Context, arg GetConflictingBannersParams) ([]int, error) {
+	rows, err := q.db.QueryContext(ctx, getConflictingBanners,
+		arg.FeatureID,
+		pq.Array(arg.TagIds),
+		arg.BannerID,
+		arg.ActiveFrom,
+		arg.ActiveUntil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int
+	for rows.Next() {
+		var banner_id int
+		if err := rows.Scan(&banner_id); err != nil {
+			return nil, err
+		}
+		items = append(items, banner_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getExperimentByID = `-- name: GetExperimentByID :one
+SELECT
+    id, feature_id, tag_id, winner_id, created_at, updated_at, ended_at
+FROM
+    experiments
+WHERE
+    id = $1
+`
+
+func (q *Queries) GetExperimentByID(ctx context.Context, id int) (Experiment, error) {
+	row := q.db.QueryRowContext(ctx, getExperimentByID, id)
+	var i Experiment
+	err := row.Scan(
+		&i.ID,
+		&i.FeatureID,
+		&i.TagID,
+		&i.WinnerID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.EndedAt,
+	)
+	return i, err
+}
+
+const getExperimentVariants = `-- name: GetExperimentVariants :many
+SELECT
+    experiment_id, banner_id, weight
+FROM
+    experiment_variants
+WHERE
+    experiment_id = $1
+ORDER BY
+    banner_id
+`
+
+func (q *Queries) GetExperimentVariants(ctx context.Context, experimentID int) ([]ExperimentVariant, error) {
+	rows, err := q.db.QueryContext(ctx, getExperimentVariants, experimentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ExperimentVariant
+	for rows.Next() {
+		var i ExperimentVariant
+		if err := rows.Scan(&i.ExperimentID, &i.BannerID, &i.Weight); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFallbackBanner = `-- name: GetFallbackBanner :one
+SELECT
+    b.id,
+    b.feature_id,
+    b.is_active,
+    b.is_deleted,
+    b.created_at,
+    b.updated_at,
+    b.content,
+    b.active_from,
+    b.active_until,
+    b.priority,
+    b.localized_content,
+    b.frequency_cap,
+    b.deleted_at
+FROM
+    feature_fallbacks f
+    JOIN banners b ON b.id = f.banner_id
+WHERE
+    f.feature_id = $1
+    AND b.feature_id = f.feature_id
+    AND b.is_active = TRUE
+    AND b.is_deleted = FALSE
+    AND (b.active_from IS NULL
+        OR b.active_from <= CURRENT_TIMESTAMP)
+    AND (b.active_until IS NULL
+        OR b.active_until > CURRENT_TIMESTAMP)
+    AND NOT (b.id = ANY ($2::INTEGER[]))
+`
+
+type GetFallbackBannerParams struct {
+	FeatureID   int   `db:"feature_id" json:"feature_id"`
+	ExcludedIds []int `db:"excluded_ids" json:"excluded_ids"`
+}
+
+func (q *Queries) GetFallbackBanner(ctx context.Context, arg GetFallbackBannerParams) (Banner, error) {
+	row := q.db.QueryRowContext(ctx, getFallbackBanner, arg.FeatureID, pq.Array(arg.ExcludedIds))
+	var i Banner
+	err := row.Scan(
+		&i.ID,
+		&i.FeatureID,
+		&i.IsActive,
+		&i.IsDeleted,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Content,
+		&i.ActiveFrom,
+		&i.ActiveUntil,
+		&i.Priority,
+		&i.LocalizedContent,
+		&i.FrequencyCap,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const getFeature = `-- name: GetFeature :one
+SELECT
+    id, name, description, is_archived, created_at, updated_at
+FROM
+    features
+WHERE
+    id = $1
+`
+
+func (q *Queries) GetFeature(ctx context.Context, id int) (Feature, error) {
+	row := q.db.QueryRowContext(ctx, getFeature, id)
+	var i Feature
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.IsArchived,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getFeatureFallback = `-- name: GetFeatureFallback :one
+SELECT
+    feature_id, banner_id, created_at, updated_at
+FROM
+    feature_fallbacks
+WHERE
+    feature_id = $1
+`
+
+func (q *Queries) GetFeatureFallback(ctx context.Context, featureID int) (FeatureFallback, error) {
+	row := q.db.QueryRowContext(ctx, getFeatureFallback, featureID)
+	var i FeatureFallback
+	err := row.Scan(
+		&i.FeatureID,
+		&i.BannerID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getFeatureSchema = `-- name: GetFeatureSchema :one
+SELECT
+    feature_id, schema, created_at, updated_at
+FROM
+    feature_schemas
+WHERE
+    feature_id = $1
+`
+
+func (q *Queries) GetFeatureSchema(ctx context.Context, featureID int) (FeatureSchema, error) {
+	row := q.db.QueryRowContext(ctx, getFeatureSchema, featureID)
+	var i FeatureSchema
+	err := row.Scan(
+		&i.FeatureID,
+		&i.Schema,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getFeatureStats = `-- name: GetFeatureStats :many
+SELECT
+    e.feature_id,
+    COUNT(*) FILTER (WHERE e.kind = 'impression')::INTEGER AS impressions,
+    COUNT(*) FILTER (WHERE e.kind = 'click')::INTEGER AS clicks
+FROM
+    banner_events e
+WHERE ($1::INTEGER IS NULL
+    OR e.banner_id = $1)
+AND ($2::INTEGER IS NULL
+    OR e.feature_id = $2)
+AND ($3::INTEGER IS NULL
+    OR EXISTS (
+        SELECT
+            1
+        FROM
+            tags t
+        WHERE
+            t.banner_id = e.banner_id
+            AND t.tag_id = $3))
+AND ($4::TIMESTAMP IS NULL
+    OR e.created_at >= $4)
+AND ($5::TIMESTAMP IS NULL
+    OR e.created_at < $5)
+GROUP BY
+    e.feature_id
 ORDER BY
-    banner_id OFFSET $2
+    e.feature_id
 `
 
-type GetBannersIDsByTagWithOffsetParams struct {
-	TagID  int `db:"tag_id" json:"tag_id"`
-	Offset int `db:"offset" json:"offset"`
+type GetFeatureStatsParams struct {
+	BannerID  sql.NullInt32 `db:"banner_id" json:"banner_id"`
+	FeatureID sql.NullInt32 `db:"feature_id" json:"feature_id"`
+	TagID     sql.NullInt32 `db:"tag_id" json:"tag_id"`
+	FromTime  sql.NullTime  `db:"from_time" json:"from_time"`
+	UntilTime sql.NullTime  `db:"until_time" json:"until_time"`
+}
+
+type GetFeatureStatsRow struct {
+	FeatureID   int `db:"feature_id" json:"feature_id"`
+	Impressions int `db:"impressions" json:"impressions"`
+	Clicks      int `db:"clicks" json:"clicks"`
+}
+
+func (q *Queries) GetFeatureStats(ctx context.Context, arg GetFeatureStatsParams) ([]GetFeatureStatsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFeatureStats,
+		arg.BannerID,
+		arg.FeatureID,
+		arg.TagID,
+		arg.FromTime,
+		arg.UntilTime,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFeatureStatsRow
+	for rows.Next() {
+		var i GetFeatureStatsRow
+		if err := rows.Scan(
+			&i.FeatureID,
+			&i.Impressions,
+			&i.Clicks,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFeatures = `-- name: GetFeatures :many
+SELECT
+    id, name, description, is_archived, created_at, updated_at
+FROM
+    features
+WHERE
+    is_archived = FALSE
+    OR $1::BOOLEAN
+ORDER BY
+    id
+`
+
+func (q *Queries) GetFeatures(ctx context.Context, includeArchived bool) ([]Feature, error) {
+	rows, err := q.db.QueryContext(ctx, getFeatures, includeArchived)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Feature
+	for rows.Next() {
+		var i Feature
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.IsArchived,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFeaturesByIDs = `-- name: GetFeaturesByIDs :many
+SELECT
+    id, name, description, is_archived, created_at, updated_at
+FROM
+    features
+WHERE
+    id = ANY ($1::INTEGER[])
+`
+
+func (q *Queries) GetFeaturesByIDs(ctx context.Context, ids []int) ([]Feature, error) {
+	rows, err := q.db.QueryContext(ctx, getFeaturesByIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Feature
+	for rows.Next() {
+		var i Feature
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.IsArchived,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getManagedBanners = `-- name: GetManagedBanners :many
+SELECT
+    m.external_id,
+    b.id,
+    b.feature_id,
+    b.is_active,
+    b.is_deleted,
+    b.created_at,
+    b.updated_at,
+    b.content,
+    b.active_from,
+    b.active_until,
+    b.priority,
+    b.localized_content,
+    b.frequency_cap,
+    COALESCE((
+        SELECT
+            jsonb_agg(t.tag_id ORDER BY t.id)
+        FROM tags t
+        WHERE
+            t.banner_id = b.id
+            AND t.tag_id <> -1), '[]')::JSONB AS tag_ids
+FROM
+    managed_banners m
+    JOIN banners b ON b.id = m.banner_id
+WHERE
+    m.manifest = $1
+    AND b.is_deleted = FALSE
+ORDER BY
+    m.external_id
+`
+
+type GetManagedBannersRow struct {
+	ExternalID       string          `db:"external_id" json:"external_id"`
+	ID               int             `db:"id" json:"id"`
+	FeatureID        int             `db:"feature_id" json:"feature_id"`
+	IsActive         bool            `db:"is_active" json:"is_active"`
+	IsDeleted        bool            `db:"is_deleted" json:"is_deleted"`
+	CreatedAt        time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time       `db:"updated_at" json:"updated_at"`
+	Content          json.RawMessage `db:"content" json:"content"`
+	ActiveFrom       sql.NullTime    `db:"active_from" json:"active_from"`
+	ActiveUntil      sql.NullTime    `db:"active_until" json:"active_until"`
+	Priority         int             `db:"priority" json:"priority"`
+	LocalizedContent json.RawMessage `db:"localized_content" json:"localized_content"`
+	FrequencyCap     sql.NullInt32   `db:"frequency_cap" json:"frequency_cap"`
+	TagIds           json.RawMessage `db:"tag_ids" json:"tag_ids"`
+}
+
+func (q *Queries) GetManagedBanners(ctx context.Context, manifest string) ([]GetManagedBannersRow, error) {
+	rows, err := q.db.QueryContext(ctx, getManagedBanners, manifest)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetManagedBannersRow
+	for rows.Next() {
+		var i GetManagedBannersRow
+		if err := rows.Scan(
+			&i.ExternalID,
+			&i.ID,
+			&i.FeatureID,
+			&i.IsActive,
+			&i.IsDeleted,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Content,
+			&i.ActiveFrom,
+			&i.ActiveUntil,
+			&i.Priority,
+			&i.LocalizedContent,
+			&i.FrequencyCap,
+			&i.TagIds,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPurgeableBannerIDs = `-- name: GetPurgeableBannerIDs :many
+SELECT
+    id
+FROM
+    banners
+WHERE
+    is_deleted = TRUE
+    AND deleted_at < CURRENT_TIMESTAMP - $1::BIGINT * INTERVAL '1 second'
+ORDER BY
+    id
+LIMIT $2
+FOR UPDATE
+    SKIP LOCKED
+`
+
+type GetPurgeableBannerIDsParams struct {
+	RetentionSecs int64 `db:"retention_secs" json:"retention_secs"`
+	Limit         int   `db:"limit" json:"limit"`
+}
+
+func (q *Queries) GetPurgeableBannerIDs(ctx context.Context, arg GetPurgeableBannerIDsParams) ([]int, error) {
+	rows, err := q.db.QueryContext(ctx, getPurgeableBannerIDs, arg.RetentionSecs, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRunningExperimentForUpdate = `-- name: GetRunningExperimentForUpdate :one
+SELECT
+    id, feature_id, tag_id, winner_id, created_at, updated_at, ended_at
+FROM
+    experiments
+WHERE
+    id = $1
+    AND ended_at IS NULL
+FOR UPDATE
+`
+
+func (q *Queries) GetRunningExperimentForUpdate(ctx context.Context, id int) (Experiment, error) {
+	row := q.db.QueryRowContext(ctx, getRunningExperimentForUpdate, id)
+	var i Experiment
+	err := row.Scan(
+		&i.ID,
+		&i.FeatureID,
+		&i.TagID,
+		&i.WinnerID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.EndedAt,
+	)
+	return i, err
+}
+
+const getRunningExperimentVariants = `-- name: GetRunningExperimentVariants :many
+SELECT
+    ev.experiment_id,
+    ev.banner_id,
+    ev.weight
+FROM
+    experiment_variants ev
+WHERE
+    ev.experiment_id = (
+        SELECT
+            MIN(e.id)
+        FROM
+            experiments e
+        WHERE
+            e.feature_id = $1
+            AND e.tag_id = ANY ($2::INTEGER[])
+            AND e.ended_at IS NULL)
+ORDER BY
+    ev.banner_id
+`
+
+type GetRunningExperimentVariantsParams struct {
+	FeatureID int   `db:"feature_id" json:"feature_id"`
+	TagIds    []int `db:"tag_ids" json:"tag_ids"`
+}
+
+func (q *Queries) GetRunningExperimentVariants(ctx context.Context, arg GetRunningExperimentVariantsParams) ([]ExperimentVariant, error) {
+	rows, err := q.db.QueryContext(ctx, getRunningExperimentVariants, arg.FeatureID, pq.Array(arg.TagIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ExperimentVariant
+	for rows.Next() {
+		var i ExperimentVariant
+		if err := rows.Scan(&i.ExperimentID, &i.BannerID, &i.Weight); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTagDefinition = `-- name: GetTagDefinition :one
+SELECT
+    id, name, description, is_archived, created_at, updated_at
+FROM
+    tag_definitions
+WHERE
+    id = $1
+`
+
+func (q *Queries) GetTagDefinition(ctx context.Context, id int) (TagDefinition, error) {
+	row := q.db.QueryRowContext(ctx, getTagDefinition, id)
+	var i TagDefinition
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.IsArchived,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getTagDefinitions = `-- name: GetTagDefinitions :many
+SELECT
+    id, name, description, is_archived, created_at, updated_at
+FROM
+    tag_definitions
+WHERE
+    is_archived = FALSE
+    OR $1::BOOLEAN
+ORDER BY
+    id
+`
+
+func (q *Queries) GetTagDefinitions(ctx context.Context, includeArchived bool) ([]TagDefinition, error) {
+	rows, err := q.db.QueryContext(ctx, getTagDefinitions, includeArchived)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TagDefinition
+	for rows.Next() {
+		var i TagDefinition
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.IsArchived,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTagDefinitionsByIDs = `-- name: GetTagDefinitionsByIDs :many
+SELECT
+    id, name, description, is_archived, created_at, updated_at
+FROM
+    tag_definitions
+WHERE
+    id = ANY ($1::INTEGER[])
+`
+
+func (q *Queries) GetTagDefinitionsByIDs(ctx context.Context, ids []int) ([]TagDefinition, error) {
+	rows, err := q.db.QueryContext(ctx, getTagDefinitionsByIDs, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TagDefinition
+	for rows.Next() {
+		var i TagDefinition
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.IsArchived,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTagStats = `-- name: GetTagStats :many
+SELECT
+    t.tag_id,
+    COUNT(*) FILTER (WHERE e.kind = 'impression')::INTEGER AS impressions,
+    COUNT(*) FILTER (WHERE e.kind = 'click')::INTEGER AS clicks
+FROM
+    banner_events e
+    JOIN tags t ON t.banner_id = e.banner_id
+WHERE ($1::INTEGER IS NULL
+    OR e.banner_id = $1)
+AND ($2::INTEGER IS NULL
+    OR e.feature_id = $2)
+AND ($3::INTEGER IS NULL
+    OR t.tag_id = $3)
+AND t.tag_id <> -1
+AND ($4::TIMESTAMP IS NULL
+    OR e.created_at >= $4)
+AND ($5::TIMESTAMP IS NULL
+    OR e.created_at < $5)
+GROUP BY
+    t.tag_id
+ORDER BY
+    t.tag_id
+`
+
+type GetTagStatsParams struct {
+	BannerID  sql.NullInt32 `db:"banner_id" json:"banner_id"`
+	FeatureID sql.NullInt32 `db:"feature_id" json:"feature_id"`
+	TagID     sql.NullInt32 `db:"tag_id" json:"tag_id"`
+	FromTime  sql.NullTime  `db:"from_time" json:"from_time"`
+	UntilTime sql.NullTime  `db:"until_time" json:"until_time"`
+}
+
+type GetTagStatsRow struct {
+	TagID       int `db:"tag_id" json:"tag_id"`
+	Impressions int `db:"impressions" json:"impressions"`
+	Clicks      int `db:"clicks" json:"clicks"`
+}
+
+func (q *Queries) GetTagStats(ctx context.Context, arg GetTagStatsParams) ([]GetTagStatsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getTagStats,
+		arg.BannerID,
+		arg.FeatureID,
+		arg.TagID,
+		arg.FromTime,
+		arg.UntilTime,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTagStatsRow
+	for rows.Next() {
+		var i GetTagStatsRow
+		if err := rows.Scan(
+			&i.TagID,
+			&i.Impressions,
+			&i.Clicks,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTagsByBannerID = `-- name: GetTagsByBannerID :many
+SELECT
+    id, tag_id, banner_id, feature_id, is_live, active_from, active_until
+FROM
+    tags
+WHERE
+    banner_id = $1
+`
+
+func (q *Queries) GetTagsByBannerID(ctx context.Context, bannerID int) ([]Tag, error) {
+	rows, err := q.db.QueryContext(ctx, getTagsByBannerID, bannerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Tag
+	for rows.Next() {
+		var i Tag
+		if err := rows.Scan(
+			&i.ID,
+			&i.TagID,
+			&i.BannerID,
+			&i.FeatureID,
+			&i.IsLive,
+			&i.ActiveFrom,
+			&i.ActiveUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUserByID = `-- name: GetUserByID :one
+SELECT
+    id, name, role, created_at, updated_at
+FROM
+    users
+WHERE
+    id = $1
+`
+
+func (q *Queries) GetUserByID(ctx context.Context, id int) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByID, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Role,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getVariantBanner = `-- name: GetVariantBanner :one
+SELECT
+    b.id,
+    b.feature_id,
+    b.is_active,
+    b.is_deleted,
+    b.created_at,
+    b.updated_at,
+    b.content,
+    b.active_from,
+    b.active_until,
+    b.priority,
+    b.localized_content,
+    b.frequency_cap,
+    b.deleted_at
+FROM
+    banners b
+WHERE
+    b.id = $1
+    AND b.is_deleted = FALSE
+    AND (b.active_from IS NULL
+        OR b.active_from <= CURRENT_TIMESTAMP)
+    AND (b.active_until IS NULL
+        OR b.active_until > CURRENT_TIMESTAMP)
+`
+
+func (q *Queries) GetVariantBanner(ctx context.Context, id int) (Banner, error) {
+	row := q.db.QueryRowContext(ctx, getVariantBanner, id)
+	var i Banner
+	err := row.Scan(
+		&i.ID,
+		&i.FeatureID,
+		&i.IsActive,
+		&i.IsDeleted,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Content,
+		&i.ActiveFrom,
+		&i.ActiveUntil,
+		&i.Priority,
+		&i.LocalizedContent,
+		&i.FrequencyCap,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const lockBannerDeleteJobQueue = `-- name: LockBannerDeleteJobQueue :exec
+SELECT
+    pg_advisory_xact_lock(hashtext('banner_delete_jobs'))
+`
+
+func (q *Queries) LockBannerDeleteJobQueue(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, lockBannerDeleteJobQueue)
+	return err
+}
+
+const pruneBannerDeleteJobs = `-- name: PruneBannerDeleteJobs :execrows
+DELETE FROM banner_delete_jobs
+WHERE finished_at < CURRENT_TIMESTAMP - $1::BIGINT * INTERVAL '1 second'
+`
+
+func (q *Queries) PruneBannerDeleteJobs(ctx context.Context, retentionSecs int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, pruneBannerDeleteJobs, retentionSecs)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const pruneBannerDraftsByBannerIDs = `-- name: PruneBannerDraftsByBannerIDs :execrows
+DELETE FROM banner_drafts
+WHERE banner_id = ANY ($1::INTEGER[])
+`
+
+func (q *Queries) PruneBannerDraftsByBannerIDs(ctx context.Context, bannerIds []int) (int64, error) {
+	result, err := q.db.ExecContext(ctx, pruneBannerDraftsByBannerIDs, pq.Array(bannerIds))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const pruneBannerRevisions = `-- name: PruneBannerRevisions :exec
+DELETE FROM banner_revisions br
+WHERE br.banner_id = $1
+    AND br.version <= (
+        SELECT
+            MAX(r.version)
+        FROM
+            banner_revisions r
+        WHERE
+            r.banner_id = $1) - $2::INTEGER
+`
+
+type PruneBannerRevisionsParams struct {
+	BannerID int `db:"banner_id" json:"banner_id"`
+	Keep     int `db:"keep" json:"keep"`
+}
+
+func (q *Queries) PruneBannerRevisions(ctx context.Context, arg PruneBannerRevisionsParams) error {
+	_, err := q.db.ExecContext(ctx, pruneBannerRevisions, arg.BannerID, arg.Keep)
+	return err
+}
+
+const pruneBannerRevisionsByBannerIDs = `-- name: PruneBannerRevisionsByBannerIDs :execrows
+DELETE FROM banner_revisions
+WHERE banner_id = ANY ($1::INTEGER[])
+`
+
+func (q *Queries) PruneBannerRevisionsByBannerIDs(ctx context.Context, bannerIds []int) (int64, error) {
+	result, err := q.db.ExecContext(ctx, pruneBannerRevisionsByBannerIDs, pq.Array(bannerIds))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const pruneBanners = `-- name: PruneBanners :execrows
+DELETE FROM banners
+WHERE id = ANY ($1::INTEGER[])
+    AND is_deleted = TRUE
+`
+
+func (q *Queries) PruneBanners(ctx context.Context, bannerIds []int) (int64, error) {
+	result, err := q.db.ExecContext(ctx, pruneBanners, pq.Array(bannerIds))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const pruneExperimentVariantsByBannerIDs = `-- name: PruneExperimentVariantsByBannerIDs :execrows
+DELETE FROM experiment_variants
+WHERE banner_id = ANY ($1::INTEGER[])
+`
+
+func (q *Queries) PruneExperimentVariantsByBannerIDs(ctx context.Context, bannerIds []int) (int64, error) {
+	result, err := q.db.ExecContext(ctx, pruneExperimentVariantsByBannerIDs, pq.Array(bannerIds))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const pruneFeature = `-- name: PruneFeature :one
+DELETE FROM features f
+WHERE f.id = $1
+    AND NOT EXISTS (
+        SELECT
+            1
+        FROM
+            banners b
+        WHERE
+            b.feature_id = f.id)
+RETURNING
+    f.id
+`
+
+func (q *Queries) PruneFeature(ctx context.Context, id int) (int, error) {
+	row := q.db.QueryRowContext(ctx, pruneFeature, id)
+	err := row.Scan(&id)
+	return id, err
+}
+
+const pruneFeatureFallback = `-- name: PruneFeatureFallback :one
+DELETE FROM feature_fallbacks
+WHERE feature_id = $1
+RETURNING
+    feature_id
+`
+
+func (q *Queries) PruneFeatureFallback(ctx context.Context, featureID int) (int, error) {
+	row := q.db.QueryRowContext(ctx, pruneFeatureFallback, featureID)
+	err := row.Scan(&featureID)
+	return featureID, err
+}
+
+const pruneFeatureFallbacksByBannerIDs = `-- name: PruneFeatureFallbacksByBannerIDs :execrows
+DELETE FROM feature_fallbacks
+WHERE banner_id = ANY ($1::INTEGER[])
+`
+
+func (q *Queries) PruneFeatureFallbacksByBannerIDs(ctx context.Context, bannerIds []int) (int64, error) {
+	result, err := q.db.ExecContext(ctx, pruneFeatureFallbacksByBannerIDs, pq.Array(bannerIds))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const pruneManagedBannersByBannerIDs = `-- name: PruneManagedBannersByBannerIDs :execrows
+DELETE FROM managed_banners
+WHERE banner_id = ANY ($1::INTEGER[])
+`
+
+func (q *Queries) PruneManagedBannersByBannerIDs(ctx context.Context, bannerIds []int) (int64, error) {
+	result, err := q.db.ExecContext(ctx, pruneManagedBannersByBannerIDs, pq.Array(bannerIds))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const pruneOrphanedTags = `-- name: PruneOrphanedTags :execrows
+DELETE FROM tags
+WHERE id IN (
+        SELECT
+            t.id
+        FROM
+            tags t
+            LEFT JOIN banners b ON b.id = t.banner_id
+        WHERE
+            t.tag_id = -1
+            OR b.id IS NULL
+        ORDER BY
+            t.id
+        LIMIT $1
+        FOR UPDATE
+            OF t SKIP LOCKED)
+`
+
+func (q *Queries) PruneOrphanedTags(ctx context.Context, limit int) (int64, error) {
+	result, err := q.db.ExecContext(ctx, pruneOrphanedTags, limit)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const pruneTagDefinition = `-- name: PruneTagDefinition :one
+DELETE FROM tag_definitions d
+WHERE d.id = $1
+    AND NOT EXISTS (
+        SELECT
+            1
+        FROM
+            tags t
+        WHERE
+            t.tag_id = d.id)
+RETURNING
+    d.id
+`
+
+func (q *Queries) PruneTagDefinition(ctx context.Context, id int) (int, error) {
+	row := q.db.QueryRowContext(ctx, pruneTagDefinition, id)
+	err := row.Scan(&id)
+	return id, err
+}
+
+const pruneTagsByBannerIDs = `-- name: PruneTagsByBannerIDs :execrows
+DELETE FROM tags
+WHERE banner_id = ANY ($1::INTEGER[])
+`
+
+func (q *Queries) PruneTagsByBannerIDs(ctx context.Context, bannerIds []int) (int64, error) {
+	result, err := q.db.ExecContext(ctx, pruneTagsByBannerIDs, pq.Array(bannerIds))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const recordBannerDeleteJobChunk = `-- name: RecordBannerDeleteJobChunk :execrows
+UPDATE
+    banner_delete_jobs
+SET
+    banner_ids = banner_ids || $1,
+    results = results || $2
+WHERE
+    id = $3
+    AND claim_id = $4
+`
+
+type RecordBannerDeleteJobChunkParams struct {
+	BannerIds json.RawMessage `db:"banner_ids" json:"banner_ids"`
+	Results   json.RawMessage `db:"results" json:"results"`
+	ID        uuid.UUID       `db:"id" json:"id"`
+	ClaimID   uuid.NullUUID   `db:"claim_id" json:"claim_id"`
+}
+
+func (q *Queries) RecordBannerDeleteJobChunk(ctx context.Context, arg RecordBannerDeleteJobChunkParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, recordBannerDeleteJobChunk,
+		arg.BannerIds,
+		arg.Results,
+		arg.ID,
+		arg.ClaimID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const releaseSlot = `-- name: ReleaseSlot :many
+UPDATE
+    banners
+SET
+    is_active = FALSE
+WHERE
+    id IN (
+        SELECT
+            banner_id
+        FROM
+            tags
+        WHERE
+            is_live = TRUE
+            AND feature_id = $1::INTEGER
+            AND tag_id = $2
+            AND banner_id <> $3
+            AND tstzrange(active_from, active_until) && tstzrange($4::TIMESTAMPTZ, $5::TIMESTAMPTZ)
+    )
+RETURNING
+    id
+`
+
+type ReleaseSlotParams struct {
+	FeatureID   int          `db:"feature_id" json:"feature_id"`
+	TagID       int          `db:"tag_id" json:"tag_id"`
+	BannerID    int          `db:"banner_id" json:"banner_id"`
+	ActiveFrom  sql.NullTime `db:"active_from" json:"active_from"`
+	ActiveUntil sql.NullTime `db:"active_until" json:"active_until"`
+}
+
+func (q *Queries) ReleaseSlot(ctx context.Context, arg ReleaseSlotParams) ([]int, error) {
+	rows, err := q.db.QueryContext(ctx, releaseSlot,
+		arg.FeatureID,
+		arg.TagID,
+		arg.BannerID,
+		arg.ActiveFrom,
+		arg.ActiveUntil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const renewBannerDeleteJob = `-- name: RenewBannerDeleteJob :one
+UPDATE
+    banner_delete_jobs
+SET
+    claimed_until = CURRENT_TIMESTAMP + $1::BIGINT * INTERVAL '1 second'
+WHERE
+    id = $2
+    AND claim_id = $3
+    AND status = 'running'
+    AND claimed_until > CURRENT_TIMESTAMP
+RETURNING
+    id
+`
+
+type RenewBannerDeleteJobParams struct {
+	LeaseSecs int64         `db:"lease_secs" json:"lease_secs"`
+	ID        uuid.UUID     `db:"id" json:"id"`
+	ClaimID   uuid.NullUUID `db:"claim_id" json:"claim_id"`
+}
+
+func (q *Queries) RenewBannerDeleteJob(ctx context.Context, arg RenewBannerDeleteJobParams) (uuid.UUID, error) {
+	row := q.db.QueryRowContext(ctx, renewBannerDeleteJob, arg.LeaseSecs, arg.ID, arg.ClaimID)
+	var id uuid.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const restoreBannerByID = `-- name: RestoreBannerByID :one
+UPDATE
+    banners
+SET
+    is_deleted = FALSE,
+    deleted_at = NULL
+WHERE
+    id = $1
+RETURNING
+    id
+`
+
+func (q *Queries) RestoreBannerByID(ctx context.Context, id int) (int, error) {
+	row := q.db.QueryRowContext(ctx, restoreBannerByID, id)
+	err := row.Scan(&id)
+	return id, err
+}
+
+const reviewBannerDraft = `-- name: ReviewBannerDraft :one
+UPDATE
+    banner_drafts
+SET
+    status = $1,
+    reviewed_by = $2,
+    reviewed_at = CURRENT_TIMESTAMP,
+    review_comment = $3,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = $4
+RETURNING
+    id
+`
+
+type ReviewBannerDraftParams struct {
+	Status        string         `db:"status" json:"status"`
+	ReviewedBy    sql.NullInt32  `db:"reviewed_by" json:"reviewed_by"`
+	ReviewComment sql.NullString `db:"review_comment" json:"review_comment"`
+	ID            int            `db:"id" json:"id"`
+}
+
+func (q *Queries) ReviewBannerDraft(ctx context.Context, arg ReviewBannerDraftParams) (int, error) {
+	row := q.db.QueryRowContext(ctx, reviewBannerDraft,
+		arg.Status,
+		arg.ReviewedBy,
+		arg.ReviewComment,
+		arg.ID,
+	)
+	var id int
+	err := row.Scan(&id)
+	return id, err
+}
+
+const rollbackBannerByID = `-- name: RollbackBannerByID :one
+UPDATE
+    banners
+SET
+    feature_id = $1,
+    content = $2,
+    is_active = $3,
+    active_from = $4,
+    active_until = $5,
+    priority = $6,
+    localized_content = $7,
+    frequency_cap = $8,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = $9
+RETURNING
+    id
+`
+
+type RollbackBannerByIDParams struct {
+	FeatureID        int             `db:"feature_id" json:"feature_id"`
+	Content          json.RawMessage `db:"content" json:"content"`
+	IsActive         bool            `db:"is_active" json:"is_active"`
+	ActiveFrom       sql.NullTime    `db:"active_from" json:"active_from"`
+	ActiveUntil      sql.NullTime    `db:"active_until" json:"active_until"`
+	Priority         int             `db:"priority" json:"priority"`
+	LocalizedContent json.RawMessage `db:"localized_content" json:"localized_content"`
+	FrequencyCap     sql.NullInt32   `db:"frequency_cap" json:"frequency_cap"`
+	ID               int             `db:"id" json:"id"`
+}
+
+func (q *Queries) RollbackBannerByID(ctx context.Context, arg RollbackBannerByIDParams) (int, error) {
+	row := q.db.QueryRowContext(ctx, rollbackBannerByID,
+		arg.FeatureID,
+		arg.Content,
+		arg.IsActive,
+		arg.ActiveFrom,
+		arg.ActiveUntil,
+		arg.Priority,
+		arg.LocalizedContent,
+		arg.FrequencyCap,
+		arg.ID,
+	)
+	var id int
+	err := row.Scan(&id)
+	return id, err
+}
+
+const searchBanners = `-- name: SearchBanners :many
+SELECT
+    b.id,
+    b.feature_id,
+    b.is_active,
+    b.is_deleted,
+    b.created_at,
+    b.updated_at,
+    b.content,
+    b.active_from,
+    b.active_until,
+    b.priority,
+    b.localized_content,
+    b.frequency_cap,
+    COALESCE((
+        SELECT
+            jsonb_agg(t.tag_id ORDER BY t.id)
+        FROM tags t
+        WHERE
+            t.banner_id = b.id
+            AND t.tag_id <> -1), '[]')::JSONB AS tag_ids
+FROM
+    banners b
+WHERE
+    b.is_deleted = ANY ($1::BOOLEAN[])
+    AND ($2::INTEGER IS NULL
+        OR b.feature_id = $2)
+    AND ($3::INTEGER IS NULL
+        OR EXISTS (
+            SELECT
+                1
+            FROM
+                tags t
+            WHERE
+                t.banner_id = b.id
+                AND t.tag_id = $3))
+    AND (jsonb_to_tsvector('simple', b.content, '["string"]') || jsonb_to_tsvector('simple', b.localized_content, '["string"]')) @@ websearch_to_tsquery('simple', $4::TEXT)
+ORDER BY
+    ts_rank(jsonb_to_tsvector('simple', b.content, '["string"]') || jsonb_to_tsvector('simple', b.localized_content, '["string"]'), websearch_to_tsquery('simple', $4)) DESC,
+    b.id
+LIMIT $5 OFFSET $6
+`
+
+type SearchBannersParams struct {
+	Deleted   []bool        `db:"deleted" json:"deleted"`
+	FeatureID sql.NullInt32 `db:"feature_id" json:"feature_id"`
+	TagID     sql.NullInt32 `db:"tag_id" json:"tag_id"`
+	Q         string        `db:"q" json:"q"`
+	Limit     sql.NullInt32 `db:"limit" json:"limit"`
+	Offset    sql.NullInt32 `db:"offset" json:"offset"`
+}
+
+type SearchBannersRow struct {
+	ID               int             `db:"id" json:"id"`
+	FeatureID        int             `db:"feature_id" json:"feature_id"`
+	IsActive         bool            `db:"is_active" json:"is_active"`
+	IsDeleted        bool            `db:"is_deleted" json:"is_deleted"`
+	CreatedAt        time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time       `db:"updated_at" json:"updated_at"`
+	Content          json.RawMessage `db:"content" json:"content"`
+	ActiveFrom       sql.NullTime    `db:"active_from" json:"active_from"`
+	ActiveUntil      sql.NullTime    `db:"active_until" json:"active_until"`
+	Priority         int             `db:"priority" json:"priority"`
+	LocalizedContent json.RawMessage `db:"localized_content" json:"localized_content"`
+	FrequencyCap     sql.NullInt32   `db:"frequency_cap" json:"frequency_cap"`
+	TagIds           json.RawMessage `db:"tag_ids" json:"tag_ids"`
 }
 
-func (q *Queries) GetBannersIDsByTagWithOffset(ctx context.Context, arg GetBannersIDsByTagWithOffsetParams) ([]Tag, error) {
-	rows, err := q.db.QueryContext(ctx, getBannersIDsByTagWithOffset, arg.TagID, arg.Offset)
+func (q *Queries) SearchBanners(ctx context.Context, arg SearchBannersParams) ([]SearchBannersRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchBanners,
+		pq.Array(arg.Deleted),
+		arg.FeatureID,
+		arg.TagID,
+		arg.Q,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Tag
+	var items []SearchBannersRow
 	for rows.Next() {
-		var i Tag
-		if err := rows.Scan(&i.ID, &i.TagID, &i.BannerID); err != nil {
+		var i SearchBannersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FeatureID,
+			&i.IsActive,
+			&i.IsDeleted,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Content,
+			&i.ActiveFrom,
+			&i.ActiveUntil,
+			&i.Priority,
+			&i.LocalizedContent,
+			&i.FrequencyCap,
+			&i.TagIds,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -731,87 +3369,111 @@ func (q *Queries) GetBannersIDsByTagWithOffset(ctx context.Context, arg GetBanne
 	return items, nil
 }
 
-const getTagsByBannerID = `-- name: GetTagsByBannerID :many
-SELECT
-    id, tag_id, banner_id
-FROM
-    tags
+const submitBannerDraft = `-- name: SubmitBannerDraft :one
+UPDATE
+    banner_drafts
+SET
+    status = 'pending',
+    submitted_by = $1,
+    submitted_at = CURRENT_TIMESTAMP,
+    reviewed_by = NULL,
+    reviewed_at = NULL,
+    review_comment = NULL,
+    updated_at = CURRENT_TIMESTAMP
 WHERE
-    banner_id = $1
+    id = $2
+RETURNING
+    id
 `
 
-func (q *Queries) GetTagsByBannerID(ctx context.Context, bannerID int) ([]Tag, error) {
-	rows, err := q.db.QueryContext(ctx, getTagsByBannerID, bannerID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []Tag
-	for rows.Next() {
-		var i Tag
-		if err := rows.Scan(&i.ID, &i.TagID, &i.BannerID); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+type SubmitBannerDraftParams struct {
+	SubmittedBy sql.NullInt32 `db:"submitted_by" json:"submitted_by"`
+	ID          int           `db:"id" json:"id"`
 }
 
-const getUserByID = `-- name: GetUserByID :one
-SELECT
-    id, name, role, created_at, updated_at
-FROM
-    users
+func (q *Queries) SubmitBannerDraft(ctx context.Context, arg SubmitBannerDraftParams) (int, error) {
+	row := q.db.QueryRowContext(ctx, submitBannerDraft, arg.SubmittedBy, arg.ID)
+	var id int
+	err := row.Scan(&id)
+	return id, err
+}
+
+const touchExperiment = `-- name: TouchExperiment :one
+UPDATE
+    experiments
+SET
+    updated_at = CURRENT_TIMESTAMP
 WHERE
     id = $1
+RETURNING
+    id
 `
 
-func (q *Queries) GetUserByID(ctx context.Context, id int) (User, error) {
-	row := q.db.QueryRowContext(ctx, getUserByID, id)
-	var i User
-	err := row.Scan(
-		&i.ID,
-		&i.Name,
-		&i.Role,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-	)
-	return i, err
+func (q *Queries) TouchExperiment(ctx context.Context, id int) (int, error) {
+	row := q.db.QueryRowContext(ctx, touchExperiment, id)
+	err := row.Scan(&id)
+	return id, err
+}
+
+const unsetExperimentWinnersByBannerIDs = `-- name: UnsetExperimentWinnersByBannerIDs :execrows
+UPDATE
+    experiments
+SET
+    winner_id = NULL
+WHERE
+    winner_id = ANY ($1::INTEGER[])
+`
+
+func (q *Queries) UnsetExperimentWinnersByBannerIDs(ctx context.Context, bannerIds []int) (int64, error) {
+	result, err := q.db.ExecContext(ctx, unsetExperimentWinnersByBannerIDs, pq.Array(bannerIds))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
 const updateBannerByID = `-- name: UpdateBannerByID :one
 UPDATE
     banners
 SET
-    title = $1,
-    text = $2,
-    url = $3
+    content = $1
 WHERE
-    id = $4
+    id = $2
 RETURNING
     id
 `
 
 type UpdateBannerByIDParams struct {
-	Title string `db:"title" json:"title"`
-	Text  string `db:"text" json:"text"`
-	Url   string `db:"url" json:"url"`
-	ID    int    `db:"id" json:"id"`
+	Content json.RawMessage `db:"content" json:"content"`
+	ID      int             `db:"id" json:"id"`
 }
 
 func (q *Queries) UpdateBannerByID(ctx context.Context, arg UpdateBannerByIDParams) (int, error) {
-	row := q.db.QueryRowContext(ctx, updateBannerByID,
-		arg.Title,
-		arg.Text,
-		arg.Url,
-		arg.ID,
-	)
+	row := q.db.QueryRowContext(ctx, updateBannerByID, arg.Content, arg.ID)
+	var id int
+	err := row.Scan(&id)
+	return id, err
+}
+
+const updateBannerDraftBannerID = `-- name: UpdateBannerDraftBannerID :one
+UPDATE
+    banner_drafts
+SET
+    banner_id = $1,
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = $2
+RETURNING
+    id
+`
+
+type UpdateBannerDraftBannerIDParams struct {
+	BannerID sql.NullInt32 `db:"banner_id" json:"banner_id"`
+	ID       int           `db:"id" json:"id"`
+}
+
+func (q *Queries) UpdateBannerDraftBannerID(ctx context.Context, arg UpdateBannerDraftBannerIDParams) (int, error) {
+	row := q.db.QueryRowContext(ctx, updateBannerDraftBannerID, arg.BannerID, arg.ID)
 	var id int
 	err := row.Scan(&id)
 	return id, err
@@ -842,6 +3504,71 @@ func (q *Queries) UpdateBannerTagByID(ctx context.Context, arg UpdateBannerTagBy
 	return id, err
 }
 
+const updateExperimentVariantWeight = `-- name: UpdateExperimentVariantWeight :one
+UPDATE
+    experiment_variants
+SET
+    weight = $1
+WHERE
+    experiment_id = $2
+    AND banner_id = $3
+RETURNING
+    banner_id
+`
+
+type UpdateExperimentVariantWeightParams struct {
+	Weight       int `db:"weight" json:"weight"`
+	ExperimentID int `db:"experiment_id" json:"experiment_id"`
+	BannerID     int `db:"banner_id" json:"banner_id"`
+}
+
+func (q *Queries) UpdateExperimentVariantWeight(ctx context.Context, arg UpdateExperimentVariantWeightParams) (int, error) {
+	row := q.db.QueryRowContext(ctx, updateExperimentVariantWeight, arg.Weight, arg.ExperimentID, arg.BannerID)
+	var banner_id int
+	err := row.Scan(&banner_id)
+	return banner_id, err
+}
+
+const updateFeature = `-- name: UpdateFeature :one
+UPDATE
+    features
+SET
+    name = COALESCE($1, name),
+    description = COALESCE($2, description),
+    is_archived = COALESCE($3, is_archived),
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = $4
+RETURNING
+    id, name, description, is_archived, created_at, updated_at
+`
+
+type UpdateFeatureParams struct {
+	Name        sql.NullString `db:"name" json:"name"`
+	Description sql.NullString `db:"description" json:"description"`
+	IsArchived  sql.NullBool   `db:"is_archived" json:"is_archived"`
+	ID          int            `db:"id" json:"id"`
+}
+
+func (q *Queries) UpdateFeature(ctx context.Context, arg UpdateFeatureParams) (Feature, error) {
+	row := q.db.QueryRowContext(ctx, updateFeature,
+		arg.Name,
+		arg.Description,
+		arg.IsArchived,
+		arg.ID,
+	)
+	var i Feature
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.IsArchived,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const updateFeatureByID = `-- name: UpdateFeatureByID :one
 UPDATE
     banners
@@ -865,6 +3592,29 @@ func (q *Queries) UpdateFeatureByID(ctx context.Context, arg UpdateFeatureByIDPa
 	return id, err
 }
 
+const updateFrequencyCapByID = `-- name: UpdateFrequencyCapByID :one
+UPDATE
+    banners
+SET
+    frequency_cap = $1
+WHERE
+    id = $2
+RETURNING
+    id
+`
+
+type UpdateFrequencyCapByIDParams struct {
+	FrequencyCap sql.NullInt32 `db:"frequency_cap" json:"frequency_cap"`
+	ID           int           `db:"id" json:"id"`
+}
+
+func (q *Queries) UpdateFrequencyCapByID(ctx context.Context, arg UpdateFrequencyCapByIDParams) (int, error) {
+	row := q.db.QueryRowContext(ctx, updateFrequencyCapByID, arg.FrequencyCap, arg.ID)
+	var id int
+	err := row.Scan(&id)
+	return id, err
+}
+
 const updateIsActiveByID = `-- name: UpdateIsActiveByID :one
 UPDATE
     banners
@@ -887,3 +3637,238 @@ func (q *Queries) UpdateIsActiveByID(ctx context.Context, arg UpdateIsActiveByID
 	err := row.Scan(&id)
 	return id, err
 }
+
+const updateLocalizedContentByID = `-- name: UpdateLocalizedContentByID :one
+UPDATE
+    banners
+SET
+    localized_content = $1
+WHERE
+    id = $2
+RETURNING
+    id
+`
+
+type UpdateLocalizedContentByIDParams struct {
+	LocalizedContent json.RawMessage `db:"localized_content" json:"localized_content"`
+	FrequencyCap     sql.NullInt32   `db:"frequency_cap" json:"frequency_cap"`
+	ID               int             `db:"id" json:"id"`
+}
+
+func (q *Queries) UpdateLocalizedContentByID(ctx context.Context, arg UpdateLocalizedContentByIDParams) (int, error) {
+	row := q.db.QueryRowContext(ctx, updateLocalizedContentByID, arg.LocalizedContent, arg.ID)
+	var id int
+	err := row.Scan(&id)
+	return id, err
+}
+
+const updateManagedBannerByID = `-- name: UpdateManagedBannerByID :one
+UPDATE
+    banners b
+SET
+    feature_id = $1,
+    content = $2,
+    active_from = $3,
+    active_until = $4,
+    priority = $5,
+    localized_content = $6,
+    frequency_cap = $7,
+    is_active = FALSE,
+    updated_at = CURRENT_TIMESTAMP
+FROM
+    managed_banners m
+WHERE
+    b.id = $8
+    AND b.is_deleted = FALSE
+    AND m.banner_id = b.id
+    AND m.manifest = $9
+RETURNING
+    b.id
+`
+
+type UpdateManagedBannerByIDParams struct {
+	FeatureID        int             `db:"feature_id" json:"feature_id"`
+	Content          json.RawMessage `db:"content" json:"content"`
+	ActiveFrom       sql.NullTime    `db:"active_from" json:"active_from"`
+	ActiveUntil      sql.NullTime    `db:"active_until" json:"active_until"`
+	Priority         int             `db:"priority" json:"priority"`
+	LocalizedContent json.RawMessage `db:"localized_content" json:"localized_content"`
+	FrequencyCap     sql.NullInt32   `db:"frequency_cap" json:"frequency_cap"`
+	ID               int             `db:"id" json:"id"`
+	Manifest         string          `db:"manifest" json:"manifest"`
+}
+
+func (q *Queries) UpdateManagedBannerByID(ctx context.Context, arg UpdateManagedBannerByIDParams) (int, error) {
+	row := q.db.QueryRowContext(ctx, updateManagedBannerByID,
+		arg.FeatureID,
+		arg.Content,
+		arg.ActiveFrom,
+		arg.ActiveUntil,
+		arg.Priority,
+		arg.LocalizedContent,
+		arg.FrequencyCap,
+		arg.ID,
+		arg.Manifest,
+	)
+	var id int
+	err := row.Scan(&id)
+	return id, err
+}
+
+const updatePriorityByID = `-- name: UpdatePriorityByID :one
+UPDATE
+    banners
+SET
+    priority = $1
+WHERE
+    id = $2
+RETURNING
+    id
+`
+
+type UpdatePriorityByIDParams struct {
+	Priority int `db:"priority" json:"priority"`
+	ID       int `db:"id" json:"id"`
+}
+
+func (q *Queries) UpdatePriorityByID(ctx context.Context, arg UpdatePriorityByIDParams) (int, error) {
+	row := q.db.QueryRowContext(ctx, updatePriorityByID, arg.Priority, arg.ID)
+	var id int
+	err := row.Scan(&id)
+	return id, err
+}
+
+const updateScheduleByID = `-- name: UpdateScheduleByID :one
+UPDATE
+    banners
+SET
+    active_from = $1,
+    active_until = $2
+WHERE
+    id = $3
+RETURNING
+    id
+`
+
+type UpdateScheduleByIDParams struct {
+	ActiveFrom  sql.NullTime `db:"active_from" json:"active_from"`
+	ActiveUntil sql.NullTime `db:"active_until" json:"active_until"`
+	ID          int          `db:"id" json:"id"`
+}
+
+func (q *Queries) UpdateScheduleByID(ctx context.Context, arg UpdateScheduleByIDParams) (int, error) {
+	row := q.db.QueryRowContext(ctx, updateScheduleByID, arg.ActiveFrom, arg.ActiveUntil, arg.ID)
+	var id int
+	err := row.Scan(&id)
+	return id, err
+}
+
+const updateTagDefinition = `-- name: UpdateTagDefinition :one
+UPDATE
+    tag_definitions
+SET
+    name = COALESCE($1, name),
+    description = COALESCE($2, description),
+    is_archived = COALESCE($3, is_archived),
+    updated_at = CURRENT_TIMESTAMP
+WHERE
+    id = $4
+RETURNING
+    id, name, description, is_archived, created_at, updated_at
+`
+
+type UpdateTagDefinitionParams struct {
+	Name        sql.NullString `db:"name" json:"name"`
+	Description sql.NullString `db:"description" json:"description"`
+	IsArchived  sql.NullBool   `db:"is_archived" json:"is_archived"`
+	ID          int            `db:"id" json:"id"`
+}
+
+func (q *Queries) UpdateTagDefinition(ctx context.Context, arg UpdateTagDefinitionParams) (TagDefinition, error) {
+	row := q.db.QueryRowContext(ctx, updateTagDefinition,
+		arg.Name,
+		arg.Description,
+		arg.IsArchived,
+		arg.ID,
+	)
+	var i TagDefinition
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.IsArchived,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertFeatureFallback = `-- name: UpsertFeatureFallback :one
+INSERT INTO feature_fallbacks (feature_id, banner_id)
+    VALUES ($1, $2)
+ON CONFLICT (feature_id)
+    DO UPDATE SET
+        banner_id = EXCLUDED.banner_id,
+        updated_at = CURRENT_TIMESTAMP
+    RETURNING
+        feature_id
+`
+
+type UpsertFeatureFallbackParams struct {
+	FeatureID int `db:"feature_id" json:"feature_id"`
+	BannerID  int `db:"banner_id" json:"banner_id"`
+}
+
+func (q *Queries) UpsertFeatureFallback(ctx context.Context, arg UpsertFeatureFallbackParams) (int, error) {
+	row := q.db.QueryRowContext(ctx, upsertFeatureFallback, arg.FeatureID, arg.BannerID)
+	var feature_id int
+	err := row.Scan(&feature_id)
+	return feature_id, err
+}
+
+const upsertFeatureSchema = `-- name: UpsertFeatureSchema :one
+INSERT INTO feature_schemas (feature_id, schema)
+    VALUES ($1, $2)
+ON CONFLICT (feature_id)
+    DO UPDATE SET
+        schema = EXCLUDED.schema,
+        updated_at = CURRENT_TIMESTAMP
+    RETURNING
+        feature_id
+`
+
+type UpsertFeatureSchemaParams struct {
+	FeatureID int             `db:"feature_id" json:"feature_id"`
+	Schema    json.RawMessage `db:"schema" json:"schema"`
+}
+
+func (q *Queries) UpsertFeatureSchema(ctx context.Context, arg UpsertFeatureSchemaParams) (int, error) {
+	row := q.db.QueryRowContext(ctx, upsertFeatureSchema, arg.FeatureID, arg.Schema)
+	var feature_id int
+	err := row.Scan(&feature_id)
+	return feature_id, err
+}
+
+const upsertManagedBanner = `-- name: UpsertManagedBanner :one
+INSERT INTO managed_banners (manifest, external_id, banner_id)
+    VALUES ($1, $2, $3)
+ON CONFLICT (manifest, external_id)
+    DO UPDATE SET
+        banner_id = EXCLUDED.banner_id,
+        updated_at = CURRENT_TIMESTAMP
+    RETURNING
+        external_id
+`
+
+type UpsertManagedBannerParams struct {
+	Manifest   string `db:"manifest" json:"manifest"`
+	ExternalID string `db:"external_id" json:"external_id"`
+	BannerID   int    `db:"banner_id" json:"banner_id"`
+}
+
+func (q *Queries) UpsertManagedBanner(ctx context.Context, arg UpsertManagedBannerParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, upsertManagedBanner, arg.Manifest, arg.ExternalID, arg.BannerID)
+	var external_id string
+	err := row.Scan(&external_id)
+	return external_id, err
+}
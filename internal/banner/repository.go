@@ -6,33 +6,77 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/KretovDmitry/avito-tech/internal/config"
 	"github.com/KretovDmitry/avito-tech/pkg/log"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/redis/go-redis/v9"
 )
 
 type Repository interface {
-	GetActiveBannerByFeatureTag(ctx context.Context, params GetUserBannerParams) (*Banner, error)
-	GetBannersByFeature(ctx context.Context, params GetBannerParams) ([]GetBannerResponse, error)
-	GetBannersByFeatureWithLimit(ctx context.Context, params GetBannerParams) ([]GetBannerResponse, error)
-	GetBannersByFeatureWithOffset(ctx context.Context, params GetBannerParams) ([]GetBannerResponse, error)
-	GetBannersByFeatureWithLimitOffset(ctx context.Context, params GetBannerParams) ([]GetBannerResponse, error)
-	GetBannersByTag(ctx context.Context, params GetBannerParams) ([]GetBannerResponse, error)
-	GetBannersByTagWithLimit(ctx context.Context, params GetBannerParams) ([]GetBannerResponse, error)
-	GetBannersByTagWithOffset(ctx context.Context, params GetBannerParams) ([]GetBannerResponse, error)
-	GetBannersByTagWithLimitOffset(ctx context.Context, params GetBannerParams) ([]GetBannerResponse, error)
-	GetBannersByFeatureTag(ctx context.Context, params GetBannerParams) ([]GetBannerResponse, error)
-	GetBannersByFeatureTagWithLimit(ctx context.Context, params GetBannerParams) ([]GetBannerResponse, error)
-	GetBannersByFeatureTagWithOffset(ctx context.Context, params GetBannerParams) ([]GetBannerResponse, error)
-	GetBannersByFeatureTagWithLimitOffset(ctx context.Context, params GetBannerParams) ([]GetBannerResponse, error)
+	GetActiveBannerByFeatureTag(ctx context.Context, params GetUserBannerParams, excluded []int) (*UserBanner, error)
+	GetVariantBanner(ctx context.Context, params GetUserBannerParams, id int) (*UserBanner, error)
+	GetExperimentVariants(ctx context.Context, params GetUserBannerParams) ([]ExperimentVariant, error)
+	GetBanners(ctx context.Context, filter BannerFilter, page BannerPage) ([]GetBannerResponse, error)
+	CountBanners(ctx context.Context, filter BannerFilter) (int, error)
 	CreateBanner(ctx context.Context, data PostBannerJSONBody) (*PostBannerResponse, error)
+	ImportBanners(ctx context.Context, rows []PostBannerJSONBody, authorID int, dryRun bool) ([]int, error)
+	ExportBanners(ctx context.Context, params GetBannerExportParams, fn func([]GetBannerResponse) error) error
+	GetManagedBanners(ctx context.Context, manifest string) (map[string]GetBannerResponse, error)
+	ApplyManifest(ctx context.Context, changes ManifestChanges) (map[string]int, error)
 	DeleteBannerByID(ctx context.Context, id int) error
-	DeleteBannersByID(ctx context.Context, id ...int) error
-	UpdateBannerByID(ctx context.Context, id int, data *map[string]interface{}) error
-	UpdateIsActiveByID(ctx context.Context, id int, isActive bool) error
-	UpdateFeatureByID(ctx context.Context, id int, featureID int) error
-	UpdateBannerTagByID(ctx context.Context, id int, tags *[]int) error
+	CreateDeleteJob(ctx context.Context, ids []int, featureID, tagID *int) (*DeleteJob, error)
+	GetDeleteJob(ctx context.Context, id uuid.UUID) (*DeleteJob, error)
+	ClaimDeleteJob(ctx context.Context, lease time.Duration) (*DeleteJobClaim, error)
+	RunDeleteJob(ctx context.Context, claim DeleteJobClaim) error
+	PruneDeleteJobs(ctx context.Context, retention time.Duration) (int, error)
+	RestoreBanner(ctx context.Context, id int) error
+	CountPurgeable(ctx context.Context, retention time.Duration) (PurgeSummary, error)
+	PurgeBanners(ctx context.Context, retention time.Duration, limit int) (PurgeSummary, error)
+	PurgeOrphanedTags(ctx context.Context, limit int) (int, error)
+	PatchBanner(ctx context.Context, id int, p *BannerPatch) error
+	GetBannerRevisions(ctx context.Context, id int) ([]GetBannerVersionResponse, error)
+	FrequencyCapped(ctx context.Context, banner Banner, userID int) (bool, error)
+	CountImpression(ctx context.Context, banner Banner, userID int) error
+	RollbackBanner(ctx context.Context, id int, version int) error
+	GetBannerByID(ctx context.Context, id int) (*Banner, error)
+	GetClickableBanner(ctx context.Context, id int) (*Banner, error)
+	GetFeatureSchema(ctx context.Context, featureID int) (json.RawMessage, error)
+	SetFeatureSchema(ctx context.Context, featureID int, schema json.RawMessage) error
+	GetFeatureFallback(ctx context.Context, featureID int) (int, error)
+	SetFeatureFallback(ctx context.Context, featureID int, bannerID int) error
+	DeleteFeatureFallback(ctx context.Context, featureID int) error
+	RunInvalidations(ctx context.Context)
+	SubscribeInvalidations(ctx context.Context, purge func(keys []string)) error
+	CreateDraft(ctx context.Context, bannerID *int, payload json.RawMessage, authorID int) (int, error)
+	GetDraft(ctx context.Context, id int) (*Draft, error)
+	GetDrafts(ctx context.Context, status DraftStatus) ([]Draft, error)
+	SubmitDraft(ctx context.Context, id int, userID int) error
+	ReviewDraft(ctx context.Context, id int, reviewerID int, status DraftStatus, comment *string) (*Draft, error)
+	ApproveDraft(ctx context.Context, id int, reviewerID int, change DraftChange) (int, error)
+	CreateBannerEvents(ctx context.Context, events ...BannerEvent) error
+	GetBannerStats(ctx context.Context, params GetAnalyticsParams) ([]GetAnalyticsResponse, error)
+	CreateExperiment(ctx context.Context, data PostExperimentJSONBody) (int, error)
+	GetExperiment(ctx context.Context, id int) (*GetExperimentResponse, error)
+	UpdateExperimentWeights(ctx context.Context, id int, variants []Variant) error
+	PromoteExperimentWinner(ctx context.Context, id int, bannerID int) error
+	GetFeatures(ctx context.Context, includeArchived bool) ([]GetFeatureResponse, error)
+	GetFeature(ctx context.Context, id int) (*GetFeatureResponse, error)
+	GetFeaturesByID(ctx context.Context, ids ...int) (map[int]Feature, error)
+	CreateFeature(ctx context.Context, data PostFeatureJSONBody) (*GetFeatureResponse, error)
+	UpdateFeature(ctx context.Context, id int, data PatchFeatureFeatureIdJSONBody) (*GetFeatureResponse, error)
+	DeleteFeature(ctx context.Context, id int) error
+	GetTagDefinitions(ctx context.Context, includeArchived bool) ([]GetTagResponse, error)
+	GetTagDefinition(ctx context.Context, id int) (*GetTagResponse, error)
+	GetTagDefinitionsByID(ctx context.Context, ids ...int) (map[int]TagDefinition, error)
+	CreateTagDefinition(ctx context.Context, data PostTagJSONBody) (*GetTagResponse, error)
+	UpdateTagDefinition(ctx context.Context, id int, data PatchTagTagIdJSONBody) (*GetTagResponse, error)
+	DeleteTagDefinition(ctx context.Context, id int) error
 }
 
 type repository struct {
@@ -41,6 +85,9 @@ type repository struct {
 	queries *Queries
 	logger  log.Logger
 	config  *config.Config
+	// invalidations are the index sets of the cached entries
+	// to drop, queued for RunInvalidations
+	invalidations chan []string
 }
 
 func NewRepository(db *sql.DB, rdb *redis.Client, logger log.Logger, config *config.Config) (*repository, error) {
@@ -52,578 +99,397 @@ func NewRepository(db *sql.DB, rdb *redis.Client, logger log.Logger, config *con
 	}
 
 	return &repository{
-		db:      db,
-		rdb:     rdb,
-		queries: New(db),
-		logger:  logger,
-		config:  config,
+		db:            db,
+		rdb:           rdb,
+		queries:       New(db),
+		logger:        logger,
+		config:        config,
+		invalidations: make(chan []string, invalidationBufferLength),
 	}, nil
 }
 
 var _ Repository = (*repository)(nil)
 
-func (r *repository) GetActiveBannerByFeatureTag(ctx context.Context, params GetUserBannerParams) (*Banner, error) {
-	key := fmt.Sprintf("feature_id:%d-tag_id:%d", params.FeatureId, params.TagId)
-
-	if params.UseLastRevision != nil && !*params.UseLastRevision {
-		data, err := r.rdb.Get(ctx, key).Bytes()
-		if err != nil && err != redis.Nil {
-			return nil, err
-		}
-
-		if err == nil {
-			banner := new(Banner)
-			if err := json.Unmarshal(data, banner); err != nil {
-				return nil, err
-			}
+func (r *repository) GetActiveBannerByFeatureTag(ctx context.Context, params GetUserBannerParams, excluded []int) (*UserBanner, error) {
+	key := slotKey(params.FeatureId, params.TagId)
+	if len(excluded) > 0 {
+		// banners capped for the user resolve the slot differently
+		key += "-excluded:" + tagSet(excluded)
+	}
 
-			return banner, nil
+	// only users fine with an older version are served from cache,
+	// the others get the banner as it is now
+	useCache := params.UseLastRevision != nil && !*params.UseLastRevision
+	if useCache {
+		banner, err := r.cachedBanner(ctx, key)
+		if err != nil || banner != nil {
+			return banner, err
 		}
 	}
 
 	banner, err := r.queries.GetActiveBannerByFeatureTag(ctx,
 		GetActiveBannerByFeatureTagParams{
-			FeatureID: params.FeatureId,
-			TagID:     params.TagId,
+			FeatureID:   params.FeatureId,
+			TagIds:      params.TagId,
+			ExcludedIds: excluded,
 		})
-	if err != nil {
-		return nil, err
-	}
-
-	buf, _ := json.Marshal(banner)
-
-	err = r.rdb.Set(ctx, key, buf, r.config.CacheExpiration).Err()
-	if err != nil {
-		return nil, err
+	fallback := false
+	if err == sql.ErrNoRows {
+		banner, err = r.queries.GetFallbackBanner(ctx,
+			GetFallbackBannerParams{
+				FeatureID:   params.FeatureId,
+				ExcludedIds: excluded,
+			})
+		fallback = true
 	}
-
-	return &banner, nil
-}
-
-func (r *repository) GetBannersByFeature(ctx context.Context, params GetBannerParams) ([]GetBannerResponse, error) {
-	banners, err := r.queries.GetBannersByFeature(ctx, *params.FeatureId)
 	if err != nil {
 		return nil, err
 	}
 
-	response := make([]GetBannerResponse, 0)
+	// the decision to fall back is cached under the slot key as well
+	resolved := UserBanner{Banner: banner, Fallback: fallback}
 
-	for _, b := range banners {
-		t, err := r.queries.GetTagsByBannerID(ctx, b.ID)
+	if useCache {
+		err = r.cacheBanner(ctx, key, resolved, slotIndexes(params.FeatureId, params.TagId)...)
 		if err != nil {
 			return nil, err
 		}
-
-		tags := make([]int, 0, 1)
-		for _, tag := range t {
-			tags = append(tags, tag.TagID)
-		}
-
-		response = append(response, GetBannerResponse{
-			BannerID:  b.ID,
-			FeatureID: b.FeatureID,
-			TagIDs:    tags,
-			Content:   b,
-			IsActive:  b.IsActive,
-			CreatedAt: b.CreatedAt,
-			UpdatedAt: b.UpdatedAt,
-		})
 	}
 
-	return response, nil
+	return &resolved, nil
 }
 
-func (r *repository) GetBannersByFeatureWithLimit(ctx context.Context, params GetBannerParams) ([]GetBannerResponse, error) {
-	banners, err := r.queries.GetBannersByFeatureWithLimit(ctx,
-		GetBannersByFeatureWithLimitParams{
-			FeatureID: *params.FeatureId,
-			Limit:     *params.Limit,
-		})
-	if err != nil {
-		return nil, err
-	}
-
-	response := make([]GetBannerResponse, 0)
-
-	for _, b := range banners {
-		t, err := r.queries.GetTagsByBannerID(ctx, b.ID)
-		if err != nil {
-			return nil, err
-		}
+func (r *repository) GetVariantBanner(ctx context.Context, params GetUserBannerParams, id int) (*UserBanner, error) {
+	key := fmt.Sprintf("banner_id:%d", id)
 
-		tags := make([]int, 0, 1)
-		for _, tag := range t {
-			tags = append(tags, tag.TagID)
+	useCache := params.UseLastRevision != nil && !*params.UseLastRevision
+	if useCache {
+		banner, err := r.cachedBanner(ctx, key)
+		if err != nil || banner != nil {
+			return banner, err
 		}
-
-		response = append(response, GetBannerResponse{
-			BannerID:  b.ID,
-			FeatureID: b.FeatureID,
-			TagIDs:    tags,
-			Content:   b,
-			IsActive:  b.IsActive,
-			CreatedAt: b.CreatedAt,
-			UpdatedAt: b.UpdatedAt,
-		})
 	}
 
-	return response, nil
-}
-
-func (r *repository) GetBannersByFeatureWithOffset(ctx context.Context, params GetBannerParams) ([]GetBannerResponse, error) {
-	banners, err := r.queries.GetBannersByFeatureWithOffset(ctx,
-		GetBannersByFeatureWithOffsetParams{
-			FeatureID: *params.FeatureId,
-			Offset:    *params.Offset,
-		})
+	banner, err := r.queries.GetVariantBanner(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	response := make([]GetBannerResponse, 0)
+	resolved := UserBanner{Banner: banner}
 
-	for _, b := range banners {
-		t, err := r.queries.GetTagsByBannerID(ctx, b.ID)
-		if err != nil {
+	if useCache {
+		if err = r.cacheBanner(ctx, key, resolved, bannerIndex(id)); err != nil {
 			return nil, err
 		}
-
-		tags := make([]int, 0, 1)
-		for _, tag := range t {
-			tags = append(tags, tag.TagID)
-		}
-
-		response = append(response, GetBannerResponse{
-			BannerID:  b.ID,
-			FeatureID: b.FeatureID,
-			TagIDs:    tags,
-			Content:   b,
-			IsActive:  b.IsActive,
-			CreatedAt: b.CreatedAt,
-			UpdatedAt: b.UpdatedAt,
-		})
 	}
 
-	return response, nil
+	return &resolved, nil
 }
 
-func (r *repository) GetBannersByFeatureWithLimitOffset(ctx context.Context, params GetBannerParams) ([]GetBannerResponse, error) {
-	banners, err := r.queries.GetBannersByFeatureWithLimitOffset(ctx,
-		GetBannersByFeatureWithLimitOffsetParams{
-			FeatureID: *params.FeatureId,
-			Limit:     *params.Limit,
-			Offset:    *params.Offset,
-		})
-	if err != nil {
-		return nil, err
-	}
-
-	response := make([]GetBannerResponse, 0)
+func (r *repository) GetExperimentVariants(ctx context.Context, params GetUserBannerParams) ([]ExperimentVariant, error) {
+	key := experimentKey(params.FeatureId, params.TagId)
 
-	for _, b := range banners {
-		t, err := r.queries.GetTagsByBannerID(ctx, b.ID)
-		if err != nil {
+	if params.UseLastRevision != nil && !*params.UseLastRevision {
+		data, err := r.rdb.Get(ctx, key).Bytes()
+		if err != nil && err != redis.Nil {
 			return nil, err
 		}
 
-		tags := make([]int, 0, 1)
-		for _, tag := range t {
-			tags = append(tags, tag.TagID)
-		}
+		if err == nil {
+			variants := make([]ExperimentVariant, 0)
+			if err := json.Unmarshal(data, &variants); err != nil {
+				return nil, err
+			}
 
-		response = append(response, GetBannerResponse{
-			BannerID:  b.ID,
-			FeatureID: b.FeatureID,
-			TagIDs:    tags,
-			Content:   b,
-			IsActive:  b.IsActive,
-			CreatedAt: b.CreatedAt,
-			UpdatedAt: b.UpdatedAt,
-		})
+			return variants, nil
+		}
 	}
 
-	return response, nil
-}
-
-func (r *repository) GetBannersByTag(ctx context.Context, params GetBannerParams) ([]GetBannerResponse, error) {
-	tags, err := r.queries.GetBannersIDsByTag(ctx, *params.TagId)
+	variants, err := r.queries.GetRunningExperimentVariants(ctx,
+		GetRunningExperimentVariantsParams{
+			FeatureID: params.FeatureId,
+			TagIds:    params.TagId,
+		})
 	if err != nil {
 		return nil, err
 	}
 
-	response := make([]GetBannerResponse, 0)
-
-	for _, tag := range tags {
-		b, err := r.queries.GetBannerByID(ctx, tag.BannerID)
-		if err != nil {
-			return nil, err
-		}
-
-		t, err := r.queries.GetTagsByBannerID(ctx, b.ID)
-		if err != nil {
-			return nil, err
-		}
-
-		tags := make([]int, 0, 1)
-		for _, tag := range t {
-			tags = append(tags, tag.TagID)
-		}
-
-		response = append(response, GetBannerResponse{
-			BannerID:  b.ID,
-			FeatureID: b.FeatureID,
-			TagIDs:    tags,
-			Content:   b,
-			IsActive:  b.IsActive,
-			CreatedAt: b.CreatedAt,
-			UpdatedAt: b.UpdatedAt,
-		})
+	// absence of an experiment is cached as well,
+	// since most slots never run one
+	if variants == nil {
+		variants = make([]ExperimentVariant, 0)
 	}
+	buf, _ := json.Marshal(variants)
 
-	return response, nil
-}
-
-func (r *repository) GetBannersByTagWithLimit(ctx context.Context, params GetBannerParams) ([]GetBannerResponse, error) {
-	tags, err := r.queries.GetBannersIDsByTagWithLimit(ctx,
-		GetBannersIDsByTagWithLimitParams{
-			TagID: *params.TagId,
-			Limit: *params.Limit,
-		})
+	err = r.cacheEntry(ctx, key, buf, r.config.CacheExpiration, experimentIndex(params.FeatureId))
 	if err != nil {
 		return nil, err
 	}
 
-	response := make([]GetBannerResponse, 0)
-
-	for _, tag := range tags {
-		b, err := r.queries.GetBannerByID(ctx, tag.BannerID)
-		if err != nil {
-			return nil, err
-		}
-
-		t, err := r.queries.GetTagsByBannerID(ctx, b.ID)
-		if err != nil {
-			return nil, err
-		}
-
-		tags := make([]int, 0, 1)
-		for _, tag := range t {
-			tags = append(tags, tag.TagID)
-		}
-
-		response = append(response, GetBannerResponse{
-			BannerID:  b.ID,
-			FeatureID: b.FeatureID,
-			TagIDs:    tags,
-			Content:   b,
-			IsActive:  b.IsActive,
-			CreatedAt: b.CreatedAt,
-			UpdatedAt: b.UpdatedAt,
-		})
-	}
-
-	return response, nil
+	return variants, nil
 }
 
-func (r *repository) GetBannersByTagWithOffset(ctx context.Context, params GetBannerParams) ([]GetBannerResponse, error) {
-	tags, err := r.queries.GetBannersIDsByTagWithOffset(ctx,
-		GetBannersIDsByTagWithOffsetParams{
-			TagID:  *params.TagId,
-			Offset: *params.Offset,
-		})
+// GetBanners returns the page of banners matching the filter with their tags
+// in a single query, the most relevant first when searching.
+func (r *repository) GetBanners(ctx context.Context, filter BannerFilter, page BannerPage) ([]GetBannerResponse, error) {
+	var (
+		rows []GetBannersRow
+		err  error
+	)
+	// search has a query of its own, so that the planner
+	// always sees the predicate the full-text index is built for
+	if filter.Query != nil {
+		rows, err = bannerRows(r.queries.SearchBanners(ctx, SearchBannersParams{
+			Deleted:   filter.Deleted,
+			FeatureID: nullInt(filter.FeatureID),
+			TagID:     nullInt(filter.TagID),
+			Q:         *filter.Query,
+			Limit:     nullInt(page.Limit),
+			Offset:    nullInt(page.Offset),
+		}))
+	} else {
+		rows, err = r.pageBanners(ctx, filter, page)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	response := make([]GetBannerResponse, 0)
-
-	for _, tag := range tags {
-		b, err := r.queries.GetBannerByID(ctx, tag.BannerID)
-		if err != nil {
-			return nil, err
-		}
-
-		t, err := r.queries.GetTagsByBannerID(ctx, b.ID)
-		if err != nil {
-			return nil, err
-		}
-
+	response := make([]GetBannerResponse, 0, len(rows))
+	for _, row := range rows {
 		tags := make([]int, 0, 1)
-		for _, tag := range t {
-			tags = append(tags, tag.TagID)
+		if err := json.Unmarshal(row.TagIds, &tags); err != nil {
+			return nil, err
 		}
 
-		response = append(response, GetBannerResponse{
-			BannerID:  b.ID,
-			FeatureID: b.FeatureID,
-			TagIDs:    tags,
-			Content:   b,
-			IsActive:  b.IsActive,
-			CreatedAt: b.CreatedAt,
-			UpdatedAt: b.UpdatedAt,
-		})
+		response = append(response, newBannerResponse(Banner{
+			ID:               row.ID,
+			FeatureID:        row.FeatureID,
+			IsActive:         row.IsActive,
+			IsDeleted:        row.IsDeleted,
+			CreatedAt:        row.CreatedAt,
+			UpdatedAt:        row.UpdatedAt,
+			Content:          row.Content,
+			ActiveFrom:       row.ActiveFrom,
+			ActiveUntil:      row.ActiveUntil,
+			Priority:         row.Priority,
+			LocalizedContent: row.LocalizedContent,
+			FrequencyCap:     row.FrequencyCap,
+		}, tags))
 	}
 
 	return response, nil
 }
 
-func (r *repository) GetBannersByTagWithLimitOffset(ctx context.Context, params GetBannerParams) ([]GetBannerResponse, error) {
-	tags, err := r.queries.GetBannersIDsByTagWithLimitOffset(ctx,
-		GetBannersIDsByTagWithLimitOffsetParams{
-			TagID:  *params.TagId,
-			Limit:  *params.Limit,
-			Offset: *params.Offset,
-		})
-	if err != nil {
-		return nil, err
+// pageBanners reads the page in its sort order. Every sort order has a query
+// of its own with a static ORDER BY and a row comparison against the cursor,
+// so that the planner can walk the (created_at, id) and (updated_at, id)
+// indexes. Without a cursor the query compares against a bound preceding
+// every banner. Limit and offset pagination always runs in the order of ids.
+func (r *repository) pageBanners(ctx context.Context, filter BannerFilter, page BannerPage) ([]GetBannersRow, error) {
+	var (
+		afterID   sql.NullInt32
+		afterTime sql.NullTime
+	)
+	if page.After != nil {
+		afterID = nullInt(&page.After.ID)
+		afterTime = nullTime(page.After.Time)
 	}
 
-	response := make([]GetBannerResponse, 0)
-
-	for _, tag := range tags {
-		b, err := r.queries.GetBannerByID(ctx, tag.BannerID)
-		if err != nil {
-			return nil, err
-		}
-
-		t, err := r.queries.GetTagsByBannerID(ctx, b.ID)
-		if err != nil {
-			return nil, err
-		}
-
-		tags := make([]int, 0, 1)
-		for _, tag := range t {
-			tags = append(tags, tag.TagID)
-		}
+	featureID, tagID, limit := nullInt(filter.FeatureID), nullInt(filter.TagID), nullInt(page.Limit)
+	desc := page.Order == GetBannerParamsOrderDesc
 
-		response = append(response, GetBannerResponse{
-			BannerID:  b.ID,
-			FeatureID: b.FeatureID,
-			TagIDs:    tags,
-			Content:   b,
-			IsActive:  b.IsActive,
-			CreatedAt: b.CreatedAt,
-			UpdatedAt: b.UpdatedAt,
+	switch {
+	case page.Sort == GetBannerParamsSortCreatedAt && desc:
+		return bannerRows(r.queries.GetBannersByCreatedAtDesc(ctx, GetBannersByCreatedAtDescParams{
+			Deleted:   filter.Deleted,
+			FeatureID: featureID,
+			TagID:     tagID,
+			AfterTime: afterTime,
+			AfterID:   afterID,
+			Limit:     limit,
+		}))
+
+	case page.Sort == GetBannerParamsSortCreatedAt:
+		return bannerRows(r.queries.GetBannersByCreatedAt(ctx, GetBannersByCreatedAtParams{
+			Deleted:   filter.Deleted,
+			FeatureID: featureID,
+			TagID:     tagID,
+			AfterTime: afterTime,
+			AfterID:   afterID,
+			Limit:     limit,
+		}))
+
+	case page.Sort == GetBannerParamsSortUpdatedAt && desc:
+		return bannerRows(r.queries.GetBannersByUpdatedAtDesc(ctx, GetBannersByUpdatedAtDescParams{
+			Deleted:   filter.Deleted,
+			FeatureID: featureID,
+			TagID:     tagID,
+			AfterTime: afterTime,
+			AfterID:   afterID,
+			Limit:     limit,
+		}))
+
+	case page.Sort == GetBannerParamsSortUpdatedAt:
+		return bannerRows(r.queries.GetBannersByUpdatedAt(ctx, GetBannersByUpdatedAtParams{
+			Deleted:   filter.Deleted,
+			FeatureID: featureID,
+			TagID:     tagID,
+			AfterTime: afterTime,
+			AfterID:   afterID,
+			Limit:     limit,
+		}))
+
+	case desc:
+		return bannerRows(r.queries.GetBannersByIDDesc(ctx, GetBannersByIDDescParams{
+			Deleted:   filter.Deleted,
+			FeatureID: featureID,
+			TagID:     tagID,
+			// the bound past the last id is out of the range of INTEGER
+			AfterID: sql.NullInt64{Int64: int64(afterID.Int32), Valid: afterID.Valid},
+			Limit:   limit,
+		}))
+
+	default:
+		return r.queries.GetBanners(ctx, GetBannersParams{
+			Deleted:   filter.Deleted,
+			FeatureID: featureID,
+			TagID:     tagID,
+			AfterID:   afterID,
+			Limit:     limit,
+			Offset:    nullInt(page.Offset),
 		})
 	}
-
-	return response, nil
 }
 
-func (r *repository) GetBannersByFeatureTag(ctx context.Context, params GetBannerParams) ([]GetBannerResponse, error) {
-	banners, err := r.queries.GetBannersByFeatureTag(ctx,
-		GetBannersByFeatureTagParams{
-			FeatureID: *params.FeatureId,
-			TagID:     *params.TagId,
-		})
+// bannerRows converts the rows of any of the listing queries, which all select the same columns.
+func bannerRows[T GetBannersByIDDescRow | GetBannersByCreatedAtRow | GetBannersByCreatedAtDescRow |
+	GetBannersByUpdatedAtRow | GetBannersByUpdatedAtDescRow | SearchBannersRow](found []T, err error) ([]GetBannersRow, error) {
 	if err != nil {
 		return nil, err
 	}
 
-	response := make([]GetBannerResponse, 0)
-
-	for _, b := range banners {
-		t, err := r.queries.GetTagsByBannerID(ctx, b.ID)
-		if err != nil {
-			return nil, err
-		}
-
-		tags := make([]int, 0, 1)
-		for _, tag := range t {
-			tags = append(tags, tag.TagID)
-		}
-
-		response = append(response, GetBannerResponse{
-			BannerID:  b.ID,
-			FeatureID: b.FeatureID,
-			TagIDs:    tags,
-			Content:   b,
-			IsActive:  b.IsActive,
-			CreatedAt: b.CreatedAt,
-			UpdatedAt: b.UpdatedAt,
-		})
+	rows := make([]GetBannersRow, 0, len(found))
+	for _, row := range found {
+		rows = append(rows, GetBannersRow(row))
 	}
 
-	return response, nil
+	return rows, nil
 }
 
-func (r *repository) GetBannersByFeatureTagWithLimit(ctx context.Context, params GetBannerParams) ([]GetBannerResponse, error) {
-	banners, err := r.queries.GetBannersByFeatureTagWithLimit(ctx,
-		GetBannersByFeatureTagWithLimitParams{
-			FeatureID: *params.FeatureId,
-			TagID:     *params.TagId,
-			Limit:     *params.Limit,
+// CountBanners returns the number of banners matching the filter.
+func (r *repository) CountBanners(ctx context.Context, filter BannerFilter) (int, error) {
+	var (
+		n   int64
+		err error
+	)
+	if filter.Query != nil {
+		n, err = r.queries.CountSearchBanners(ctx, CountSearchBannersParams{
+			Deleted:   filter.Deleted,
+			FeatureID: nullInt(filter.FeatureID),
+			TagID:     nullInt(filter.TagID),
+			Q:         *filter.Query,
 		})
-	if err != nil {
-		return nil, err
-	}
-
-	response := make([]GetBannerResponse, 0)
-
-	for _, b := range banners {
-		t, err := r.queries.GetTagsByBannerID(ctx, b.ID)
-		if err != nil {
-			return nil, err
-		}
-
-		tags := make([]int, 0, 1)
-		for _, tag := range t {
-			tags = append(tags, tag.TagID)
-		}
-
-		response = append(response, GetBannerResponse{
-			BannerID:  b.ID,
-			FeatureID: b.FeatureID,
-			TagIDs:    tags,
-			Content:   b,
-			IsActive:  b.IsActive,
-			CreatedAt: b.CreatedAt,
-			UpdatedAt: b.UpdatedAt,
+	} else {
+		n, err = r.queries.CountBanners(ctx, CountBannersParams{
+			Deleted:   filter.Deleted,
+			FeatureID: nullInt(filter.FeatureID),
+			TagID:     nullInt(filter.TagID),
 		})
 	}
+	if err != nil {
+		return 0, err
+	}
 
-	return response, nil
+	return int(n), nil
 }
 
-func (r *repository) GetBannersByFeatureTagWithOffset(ctx context.Context, params GetBannerParams) ([]GetBannerResponse, error) {
-	banners, err := r.queries.GetBannersByFeatureTagWithOffset(ctx,
-		GetBannersByFeatureTagWithOffsetParams{
-			FeatureID: *params.FeatureId,
-			TagID:     *params.TagId,
-			Offset:    *params.Offset,
-		})
+func (r *repository) CreateBanner(ctx context.Context, data PostBannerJSONBody) (*PostBannerResponse, error) {
+	tx, err := r.db.Begin()
 	if err != nil {
 		return nil, err
 	}
-
-	response := make([]GetBannerResponse, 0)
-
-	for _, b := range banners {
-		t, err := r.queries.GetTagsByBannerID(ctx, b.ID)
-		if err != nil {
-			return nil, err
+	defer func() {
+		if err := tx.Rollback(); err != nil {
+			r.logger.Error(err)
 		}
+	}()
 
-		tags := make([]int, 0, 1)
-		for _, tag := range t {
-			tags = append(tags, tag.TagID)
-		}
+	qtx := r.queries.WithTx(tx)
 
-		response = append(response, GetBannerResponse{
-			BannerID:  b.ID,
-			FeatureID: b.FeatureID,
-			TagIDs:    tags,
-			Content:   b,
-			IsActive:  b.IsActive,
-			CreatedAt: b.CreatedAt,
-			UpdatedAt: b.UpdatedAt,
-		})
+	id, err := r.createBanner(ctx, qtx, data)
+	if err != nil {
+		return nil, r.conflictOrErr(ctx, err, 0, newPlacement(data))
 	}
 
-	return response, nil
-}
-
-func (r *repository) GetBannersByFeatureTagWithLimitOffset(ctx context.Context, params GetBannerParams) ([]GetBannerResponse, error) {
-	banners, err := r.queries.GetBannersByFeatureTagWithLimitOffset(ctx,
-		GetBannersByFeatureTagWithLimitOffsetParams{
-			FeatureID: *params.FeatureId,
-			TagID:     *params.TagId,
-			Limit:     *params.Limit,
-			Offset:    *params.Offset,
-		})
+	indexes, err := cacheIndexes(ctx, qtx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	response := make([]GetBannerResponse, 0)
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
 
-	for _, b := range banners {
-		t, err := r.queries.GetTagsByBannerID(ctx, b.ID)
-		if err != nil {
-			return nil, err
-		}
+	r.invalidate(ctx, indexes...)
 
-		tags := make([]int, 0, 1)
-		for _, tag := range t {
-			tags = append(tags, tag.TagID)
-		}
+	return &PostBannerResponse{BannerID: id}, nil
+}
 
-		response = append(response, GetBannerResponse{
-			BannerID:  b.ID,
-			FeatureID: b.FeatureID,
-			TagIDs:    tags,
-			Content:   b,
-			IsActive:  b.IsActive,
-			CreatedAt: b.CreatedAt,
-			UpdatedAt: b.UpdatedAt,
-		})
+// ImportBanners creates a draft of every banner in one transaction, the banners
+// are created once other admins approve the drafts. Rows conflicting
+// with active banners or with each other fail the whole import
+// with ConflictingRowsError. Dry run only checks the rows.
+func (r *repository) ImportBanners(ctx context.Context, rows []PostBannerJSONBody, authorID int, dryRun bool) ([]int, error) {
+	if err := r.checkImport(ctx, rows); err != nil {
+		return nil, err
 	}
 
-	return response, nil
-}
+	if dryRun {
+		return make([]int, 0), nil
+	}
 
-func (r *repository) CreateBanner(ctx context.Context, data PostBannerJSONBody) (*PostBannerResponse, error) {
 	tx, err := r.db.Begin()
 	if err != nil {
 		return nil, err
 	}
 	defer func() {
-		if err := tx.Rollback(); err != nil {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
 			r.logger.Error(err)
 		}
 	}()
 
 	qtx := r.queries.WithTx(tx)
 
-	banner := *data.Content
-	fields := []string{"title", "text", "url"}
-	vals := make(map[string]string, len(fields))
+	ids := make([]int, 0, len(rows))
 
-	for _, field := range fields {
-		val, ok := banner[field].(string)
-		if !ok {
-			return nil, &InvalidTypeError{field}
+	for _, data := range rows {
+		payload, err := json.Marshal(data)
+		if err != nil {
+			return nil, err
 		}
-		vals[field] = val
-	}
-
-	id, err := qtx.CreateBanner(ctx, CreateBannerParams{
-		FeatureID: *data.FeatureId,
-		Title:     vals["title"],
-		Text:      vals["text"],
-		Url:       vals["url"],
-		IsActive:  *data.IsActive,
-	})
-	if err != nil {
-		return nil, err
-	}
 
-	for _, tagID := range *data.TagIds {
-		_, err = qtx.CreateTag(ctx, CreateTagParams{
-			TagID:    tagID,
-			BannerID: id,
+		id, err := qtx.CreateBannerDraft(ctx, CreateBannerDraftParams{
+			Payload:   payload,
+			CreatedBy: authorID,
 		})
 		if err != nil {
 			return nil, err
 		}
+
+		ids = append(ids, id)
 	}
 
 	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
 
-	return &PostBannerResponse{BannerID: id}, nil
-}
-
-func (r *repository) DeleteBannerByID(ctx context.Context, id int) error {
-	_, err := r.queries.DeleteBannerByID(ctx, id)
-	if err != nil {
-		return err
-	}
-	return nil
+	return ids, nil
 }
 
-func (r *repository) DeleteBannersByID(ctx context.Context, ids ...int) error {
+// checkImport creates the banners in a transaction that is always
+// rolled back, to find the rows conflicting with active banners
+// or with each other before any draft is created.
+func (r *repository) checkImport(ctx context.Context, rows []PostBannerJSONBody) error {
 	tx, err := r.db.Begin()
 	if err != nil {
 		return err
@@ -636,150 +502,2497 @@ func (r *repository) DeleteBannersByID(ctx context.Context, ids ...int) error {
 
 	qtx := r.queries.WithTx(tx)
 
-	for _, id := range ids {
-		if _, err := qtx.DeleteBannerByID(ctx, id); err != nil {
+	conflicts := make([]RowError, 0)
+
+	for i, data := range rows {
+		_, err := r.createBanner(ctx, qtx, data)
+
+		// a conflict found by the check leaves the transaction usable,
+		// so the rest of the rows are still checked
+		var conflict *ConflictingBannersError
+		if errors.As(err, &conflict) {
+			conflicts = append(conflicts, newRowError(i+1, err))
+			continue
+		}
+
+		if err != nil {
+			err = r.conflictOrErr(ctx, err, 0, newPlacement(data))
+			if errors.As(err, &conflict) {
+				conflicts = append(conflicts, newRowError(i+1, err))
+				return &ConflictingRowsError{Rows: conflicts}
+			}
 			return err
 		}
 	}
 
-	if err := tx.Commit(); err != nil {
-		return err
+	if len(conflicts) > 0 {
+		return &ConflictingRowsError{Rows: conflicts}
 	}
 
 	return nil
 }
 
-func (r *repository) UpdateBannerByID(ctx context.Context, id int, data *map[string]interface{}) error {
-	fields := []string{"title", "text", "url"}
-	vals := make(map[string]string, len(fields))
+// createBanner creates the banner with its tags and first revision within the transaction.
+func (r *repository) createBanner(ctx context.Context, qtx *Queries, data PostBannerJSONBody) (int, error) {
+	content, localized, err := marshalContent(data)
+	if err != nil {
+		return 0, err
+	}
+
+	p := newPlacement(data)
 
-	for _, field := range fields {
-		val, ok := (*data)[field].(string)
-		if !ok {
-			return &InvalidTypeError{field}
+	if *data.IsActive {
+		if err = checkConflicts(ctx, qtx, 0, p); err != nil {
+			return 0, err
 		}
-		vals[field] = val
 	}
 
-	_, err := r.queries.UpdateBannerByID(ctx, UpdateBannerByIDParams{
-		Title: vals["title"],
-		Text:  vals["text"],
-		Url:   vals["url"],
-		ID:    id,
+	id, err := qtx.CreateBanner(ctx, CreateBannerParams{
+		FeatureID:        p.featureID,
+		Content:          content,
+		IsActive:         *data.IsActive,
+		ActiveFrom:       p.from,
+		ActiveUntil:      p.until,
+		Priority:         newPriority(data),
+		LocalizedContent: localized,
+		FrequencyCap:     nullInt(data.FrequencyCap),
 	})
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	return nil
-}
-
-func (r *repository) UpdateIsActiveByID(ctx context.Context, id int, isActive bool) error {
-	_, err := r.queries.UpdateIsActiveByID(ctx, UpdateIsActiveByIDParams{
-		IsActive: isActive,
-		ID:       id,
-	})
+	for _, tagID := range p.tags {
+		_, err = qtx.CreateTag(ctx, CreateTagParams{
+			TagID:    tagID,
+			BannerID: id,
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err = r.createRevision(ctx, qtx, id); err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// marshalContent returns content and localized content of the new banner as stored.
+func marshalContent(data PostBannerJSONBody) (json.RawMessage, json.RawMessage, error) {
+	content, err := json.Marshal(data.Content)
 	if err != nil {
-		return err
+		return nil, nil, err
+	}
+
+	localized := []byte("{}")
+	if data.LocalizedContent != nil {
+		localized, err = json.Marshal(data.LocalizedContent)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return content, localized, nil
+}
+
+// newPriority returns priority of the new banner, zero unless given.
+func newPriority(data PostBannerJSONBody) int {
+	if data.Priority == nil {
+		return 0
+	}
+	return *data.Priority
+}
+
+// exportPageSize is the number of banners read from the database at once during export.
+const exportPageSize = 500
+
+// ExportBanners passes the banners matching the filters to the callback page by page,
+// so that the whole selection is never held in memory.
+func (r *repository) ExportBanners(ctx context.Context, params GetBannerExportParams, fn func([]GetBannerResponse) error) error {
+	arg := GetBannersForExportParams{
+		Deleted:   deletedFilter(params.IncludeDeleted, params.OnlyDeleted),
+		FeatureID: nullInt(params.FeatureId),
+		TagID:     nullInt(params.TagId),
+	}
+	if params.Offset != nil {
+		arg.Offset = *params.Offset
+	}
+
+	remaining := -1
+	if params.Limit != nil {
+		remaining = *params.Limit
+	}
+
+	for remaining != 0 {
+		arg.Limit = exportPageSize
+		if remaining > 0 && remaining < exportPageSize {
+			arg.Limit = remaining
+		}
+
+		rows, err := r.queries.GetBannersForExport(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		page := make([]GetBannerResponse, 0, len(rows))
+		for _, row := range rows {
+			tags := make([]int, 0, 1)
+			if err := json.Unmarshal(row.TagIds, &tags); err != nil {
+				return err
+			}
+
+			page = append(page, newBannerResponse(Banner{
+				ID:               row.ID,
+				FeatureID:        row.FeatureID,
+				IsActive:         row.IsActive,
+				IsDeleted:        row.IsDeleted,
+				CreatedAt:        row.CreatedAt,
+				UpdatedAt:        row.UpdatedAt,
+				Content:          row.Content,
+				ActiveFrom:       row.ActiveFrom,
+				ActiveUntil:      row.ActiveUntil,
+				Priority:         row.Priority,
+				LocalizedContent: row.LocalizedContent,
+				FrequencyCap:     row.FrequencyCap,
+			}, tags))
+		}
+
+		if len(page) > 0 {
+			if err := fn(page); err != nil {
+				return err
+			}
+		}
+
+		if len(rows) < arg.Limit {
+			break
+		}
+
+		// the offset only applies before the first page,
+		// the following ones continue after the last banner seen
+		arg.AfterID = rows[len(rows)-1].ID
+		arg.Offset = 0
+		if remaining > 0 {
+			remaining -= len(rows)
+		}
 	}
 
 	return nil
 }
 
-func (r *repository) UpdateFeatureByID(ctx context.Context, id int, featureID int) error {
-	_, err := r.queries.UpdateFeatureByID(ctx, UpdateFeatureByIDParams{
-		FeatureID: featureID,
-		ID:        id,
-	})
+func (r *repository) DeleteBannerByID(ctx context.Context, id int) error {
+	indexes, err := cacheIndexes(ctx, r.queries, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.queries.DeleteBannerByID(ctx, id)
 	if err != nil {
 		return err
 	}
 
+	r.invalidate(ctx, indexes...)
+
 	return nil
 }
 
-func (r *repository) UpdateBannerTagByID(ctx context.Context, id int, tags *[]int) error {
-	unusedTagID := -1 // mock deletion
+// CreateDeleteJob stores the job deleting either the banners, repeated ids
+// are deleted once, or every live banner of the feature and/or the tag.
+// The job is rejected as a whole while too many jobs are unfinished.
+func (r *repository) CreateDeleteJob(ctx context.Context, ids []int, featureID, tagID *int) (*DeleteJob, error) {
+	unique := make([]int, 0, len(ids))
+	seen := make(map[int]struct{}, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		unique = append(unique, id)
+	}
+
+	bannerIDs, err := json.Marshal(unique)
+	if err != nil {
+		return nil, err
+	}
 
 	tx, err := r.db.Begin()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer func() {
-		if err := tx.Rollback(); err != nil {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
 			r.logger.Error(err)
 		}
 	}()
 
 	qtx := r.queries.WithTx(tx)
 
-	currentTags, err := qtx.GetTagsByBannerID(ctx, id)
+	// the queue is counted and the job is added by one request at a time,
+	// otherwise concurrent requests all see room for their job
+	// under READ COMMITTED and overrun the limit together
+	if err = qtx.LockBannerDeleteJobQueue(ctx); err != nil {
+		return nil, err
+	}
+
+	job, err := qtx.CreateBannerDeleteJob(ctx, CreateBannerDeleteJobParams{
+		ID:         uuid.New(),
+		BannerIds:  bannerIDs,
+		FeatureID:  nullInt(featureID),
+		TagID:      nullInt(tagID),
+		QueueLimit: r.config.DeleteQueueLimit,
+	})
+	if err == sql.ErrNoRows {
+		return nil, &DeleteQueueFullError{RetryAfter: r.config.DeleteFlushInterval}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return newDeleteJob(job)
+}
+
+func (r *repository) GetDeleteJob(ctx context.Context, id uuid.UUID) (*DeleteJob, error) {
+	job, err := r.queries.GetBannerDeleteJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return newDeleteJob(job)
+}
+
+// ClaimDeleteJob marks the oldest queued job, or the running one whose lease
+// has expired, running for the lease. Jobs claimed by a worker of another
+// replica meanwhile are skipped. Jobs are claimed one at a time, so that
+// the lease of a job does not run out while it waits for the jobs before it.
+func (r *repository) ClaimDeleteJob(ctx context.Context, lease time.Duration) (*DeleteJobClaim, error) {
+	claimID := uuid.New()
+
+	job, err := r.queries.ClaimBannerDeleteJob(ctx, ClaimBannerDeleteJobParams{
+		ClaimID:   uuid.NullUUID{UUID: claimID, Valid: true},
+		LeaseSecs: int64(lease / time.Second),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0)
+	if err := json.Unmarshal(job.BannerIds, &ids); err != nil {
+		return nil, err
+	}
+
+	return &DeleteJobClaim{
+		JobID:     job.ID,
+		ClaimID:   claimID,
+		Lease:     lease,
+		BannerIDs: ids,
+		FeatureID: intPtr(job.FeatureID),
+		TagID:     intPtr(job.TagID),
+	}, nil
+}
+
+// RunDeleteJob deletes the banners of the claimed job and records the outcomes
+// in a single transaction, so the job takes effect at most once. The job is
+// left alone once its lease has expired. The cache of the deleted banners
+// is dropped once they are committed. A job of banner ids is finished even
+// if the context is done meanwhile.
+func (r *repository) RunDeleteJob(ctx context.Context, claim DeleteJobClaim) error {
+	if claim.FeatureID != nil || claim.TagID != nil {
+		return r.runFilterDeleteJob(ctx, claim)
+	}
+
+	ctx = context.WithoutCancel(ctx)
+
+	tx, err := r.db.Begin()
 	if err != nil {
 		return err
 	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			r.logger.Error(err)
+		}
+	}()
 
-	switch {
-	case len(currentTags) == len(*tags):
-		for i, tag := range *tags {
-			_, err := qtx.UpdateBannerTagByID(ctx, UpdateBannerTagByIDParams{
-				TagID:    tag,
-				BannerID: id,
-				ID:       currentTags[i].ID,
-			})
-			if err != nil {
-				return err
-			}
+	qtx := r.queries.WithTx(tx)
+
+	if err = renewDeleteJob(ctx, qtx, claim); err != nil {
+		return err
+	}
+
+	// ids missing from the deleted ones are not found
+	rows, deleteErr := qtx.DeleteBannersByIDs(ctx, claim.BannerIDs)
+
+	deleted := make([]int, 0, len(rows))
+	indexes := make([]string, 0)
+	for _, row := range rows {
+		deleted = append(deleted, row.ID)
+
+		p, err := deletedIndexes(row.ID, row.FeatureID, row.TagIds, row.IsFallback)
+		if err != nil {
+			return err
 		}
+		indexes = append(indexes, p...)
+	}
 
-	case len(currentTags) < len(*tags):
-		for i := 0; i < len(*tags); i++ {
-			if i < len(currentTags) {
-				_, err := qtx.UpdateBannerTagByID(ctx, UpdateBannerTagByIDParams{
-					TagID:    (*tags)[i],
-					BannerID: id,
-					ID:       currentTags[i].ID,
-				})
-				if err != nil {
-					return err
-				}
-				continue
-			}
-			_, err := qtx.CreateTag(ctx, CreateTagParams{
-				TagID:    (*tags)[i],
-				BannerID: id,
-			})
-			if err != nil {
-				return err
-			}
+	// a failed deletion aborts the transaction,
+	// the failure is recorded after rolling it back
+	q := qtx
+	if deleteErr != nil {
+		if err = tx.Rollback(); err != nil {
+			return err
 		}
+		q = r.queries
+	}
 
-	case len(currentTags) > len(*tags):
-		for i := 0; i < len(currentTags); i++ {
-			if i < len(*tags) {
-				_, err := qtx.UpdateBannerTagByID(ctx, UpdateBannerTagByIDParams{
-					TagID:    (*tags)[i],
-					BannerID: id,
-					ID:       currentTags[i].ID,
-				})
-				if err != nil {
-					return err
-				}
-				continue
-			}
-			_, err := qtx.UpdateBannerTagByID(ctx, UpdateBannerTagByIDParams{
-				TagID:    unusedTagID,
-				BannerID: id,
-				ID:       currentTags[i].ID,
-			})
-			if err != nil {
+	status, results := deleteJobResults(claim.BannerIDs, deleted, deleteErr)
+	if err = finishDeleteJob(ctx, q, claim, status, nil, results, deleteErr); err != nil {
+		return err
+	}
+
+	if deleteErr != nil {
+		return deleteErr
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	if len(indexes) > 0 {
+		r.invalidate(ctx, indexes...)
+	}
+
+	return nil
+}
+
+// runFilterDeleteJob deletes the live banners of the feature and/or the tag
+// of the claimed job chunk by chunk, each chunk in a transaction of its own
+// along with its outcomes, so that the banners table is not locked for long.
+// The lease is renewed before every chunk, and the cache of the deleted
+// banners is dropped. Once the context is done the job is left after the
+// chunk in progress, to be resumed when its lease expires.
+func (r *repository) runFilterDeleteJob(ctx context.Context, claim DeleteJobClaim) error {
+	// the chunk in progress is never cut short
+	chunkCtx := context.WithoutCancel(ctx)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		last, err := r.deleteChunk(chunkCtx, claim)
+		if err != nil {
+			if err == sql.ErrNoRows {
 				return err
 			}
+			// the banners deleted by the previous chunks stay deleted
+			if err := finishDeleteJob(chunkCtx, r.queries, claim, DeleteJobStatusFailed, nil, nil, err); err != nil {
+				r.logger.Error(err)
+			}
+			return err
+		}
+		if last {
+			return nil
 		}
 	}
+}
 
-	if err := tx.Commit(); err != nil {
+// deleteChunk deletes the next chunk of banners of the filter job
+// and reports whether it is the last one, which finishes the job.
+func (r *repository) deleteChunk(ctx context.Context, claim DeleteJobClaim) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			r.logger.Error(err)
+		}
+	}()
+
+	qtx := r.queries.WithTx(tx)
+
+	if err = renewDeleteJob(ctx, qtx, claim); err != nil {
+		return false, err
+	}
+
+	rows, err := qtx.DeleteBannersByFilter(ctx, DeleteBannersByFilterParams{
+		FeatureID: nullInt(claim.FeatureID),
+		TagID:     nullInt(claim.TagID),
+		Limit:     r.config.DeleteChunkSize,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	ids := make([]int, 0, len(rows))
+	indexes := make([]string, 0)
+	for _, row := range rows {
+		ids = append(ids, row.ID)
+
+		p, err := deletedIndexes(row.ID, row.FeatureID, row.TagIds, row.IsFallback)
+		if err != nil {
+			return false, err
+		}
+		indexes = append(indexes, p...)
+	}
+
+	_, results := deleteJobResults(ids, ids, nil)
+
+	last := len(rows) < r.config.DeleteChunkSize
+	if last {
+		err = finishDeleteJob(ctx, qtx, claim, DeleteJobStatusDone, ids, results, nil)
+	} else {
+		err = recordDeleteChunk(ctx, qtx, claim, ids, results)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, err
+	}
+
+	if len(indexes) > 0 {
+		r.invalidate(ctx, indexes...)
+	}
+
+	return last, nil
+}
+
+// renewDeleteJob renews the lease of the claim and locks the job for the
+// transaction. A claim whose lease has expired is lost, even if no other
+// worker has taken the job over yet, so that the job never runs twice.
+func renewDeleteJob(ctx context.Context, q *Queries, claim DeleteJobClaim) error {
+	_, err := q.RenewBannerDeleteJob(ctx, RenewBannerDeleteJobParams{
+		LeaseSecs: int64(claim.Lease / time.Second),
+		ID:        claim.JobID,
+		ClaimID:   uuid.NullUUID{UUID: claim.ClaimID, Valid: true},
+	})
+	return err
+}
+
+// finishDeleteJob records the end of the job, adding the banners
+// deleted by the filter and the outcomes to the ones recorded before.
+func finishDeleteJob(ctx context.Context, q *Queries, claim DeleteJobClaim, status DeleteJobStatus, ids []int, results []DeleteJobResult, jobErr error) error {
+	bannerIDs, resultsData, err := marshalDeleteChunk(ids, results)
+	if err != nil {
+		return err
+	}
+
+	var reason sql.NullString
+	if jobErr != nil {
+		reason = sql.NullString{String: jobErr.Error(), Valid: true}
+	}
+
+	n, err := q.FinishBannerDeleteJob(ctx, FinishBannerDeleteJobParams{
+		Status:    string(status),
+		BannerIds: bannerIDs,
+		Results:   resultsData,
+		Error:     reason,
+		ID:        claim.JobID,
+		ClaimID:   uuid.NullUUID{UUID: claim.ClaimID, Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// recordDeleteChunk adds the banners deleted by the chunk and their
+// outcomes to the job.
+func recordDeleteChunk(ctx context.Context, q *Queries, claim DeleteJobClaim, ids []int, results []DeleteJobResult) error {
+	bannerIDs, resultsData, err := marshalDeleteChunk(ids, results)
+	if err != nil {
+		return err
+	}
+
+	n, err := q.RecordBannerDeleteJobChunk(ctx, RecordBannerDeleteJobChunkParams{
+		BannerIds: bannerIDs,
+		Results:   resultsData,
+		ID:        claim.JobID,
+		ClaimID:   uuid.NullUUID{UUID: claim.ClaimID, Valid: true},
+	})
+	if err != nil {
 		return err
 	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
 
 	return nil
 }
+
+func marshalDeleteChunk(ids []int, results []DeleteJobResult) (json.RawMessage, json.RawMessage, error) {
+	if ids == nil {
+		ids = make([]int, 0)
+	}
+	if results == nil {
+		results = make([]DeleteJobResult, 0)
+	}
+
+	bannerIDs, err := json.Marshal(ids)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := json.Marshal(results)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return bannerIDs, data, nil
+}
+
+// PruneDeleteJobs removes the jobs finished longer than the retention ago.
+func (r *repository) PruneDeleteJobs(ctx context.Context, retention time.Duration) (int, error) {
+	n, err := r.queries.PruneBannerDeleteJobs(ctx, int64(retention/time.Second))
+	if err != nil {
+		return 0, err
+	}
+
+	return int(n), nil
+}
+
+// RestoreBanner undoes the soft deletion of the banner. An active banner
+// is only restored if no other live banner has taken its slots meanwhile.
+func (r *repository) RestoreBanner(ctx context.Context, id int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil {
+			r.logger.Error(err)
+		}
+	}()
+
+	qtx := r.queries.WithTx(tx)
+
+	banner, err := qtx.GetBannerByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !banner.IsDeleted {
+		return &NotDeletedError{BannerID: id}
+	}
+
+	p, err := bannerPlacement(ctx, qtx, banner)
+	if err != nil {
+		return err
+	}
+
+	if banner.IsActive {
+		if err = checkConflicts(ctx, qtx, id, p); err != nil {
+			return err
+		}
+	}
+
+	if _, err = qtx.RestoreBannerByID(ctx, id); err != nil {
+		return r.conflictOrErr(ctx, err, id, p)
+	}
+
+	indexes, err := cacheIndexes(ctx, qtx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	r.invalidate(ctx, indexes...)
+
+	return nil
+}
+
+// CountPurgeable counts the rows a purge with the given retention would delete.
+func (r *repository) CountPurgeable(ctx context.Context, retention time.Duration) (PurgeSummary, error) {
+	row, err := r.queries.CountPurgeable(ctx, int64(retention/time.Second))
+	if err != nil {
+		return PurgeSummary{}, err
+	}
+
+	return PurgeSummary{
+		Banners:   int(row.Banners),
+		Tags:      int(row.Tags),
+		Revisions: int(row.Revisions),
+	}, nil
+}
+
+// PurgeBanners hard deletes at most limit banners soft-deleted longer than
+// the retention ago along with their tags, revisions, drafts, fallbacks,
+// manifest entries and experiment variants, and unsets them as experiment
+// winners. Events of the banners are kept for the analytics.
+func (r *repository) PurgeBanners(ctx context.Context, retention time.Duration, limit int) (PurgeSummary, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return PurgeSummary{}, err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil {
+			r.logger.Error(err)
+		}
+	}()
+
+	qtx := r.queries.WithTx(tx)
+
+	// banners locked by another purge are left to it
+	ids, err := qtx.GetPurgeableBannerIDs(ctx, GetPurgeableBannerIDsParams{
+		RetentionSecs: int64(retention / time.Second),
+		Limit:         limit,
+	})
+	if err != nil {
+		return PurgeSummary{}, err
+	}
+
+	if len(ids) == 0 {
+		return PurgeSummary{}, nil
+	}
+
+	tags, err := qtx.PruneTagsByBannerIDs(ctx, ids)
+	if err != nil {
+		return PurgeSummary{}, err
+	}
+
+	revisions, err := qtx.PruneBannerRevisionsByBannerIDs(ctx, ids)
+	if err != nil {
+		return PurgeSummary{}, err
+	}
+
+	if _, err = qtx.PruneBannerDraftsByBannerIDs(ctx, ids); err != nil {
+		return PurgeSummary{}, err
+	}
+
+	if _, err = qtx.PruneFeatureFallbacksByBannerIDs(ctx, ids); err != nil {
+		return PurgeSummary{}, err
+	}
+
+	if _, err = qtx.PruneManagedBannersByBannerIDs(ctx, ids); err != nil {
+		return PurgeSummary{}, err
+	}
+
+	if _, err = qtx.PruneExperimentVariantsByBannerIDs(ctx, ids); err != nil {
+		return PurgeSummary{}, err
+	}
+
+	// the experiments themselves stay, only without the winner
+	if _, err = qtx.UnsetExperimentWinnersByBannerIDs(ctx, ids); err != nil {
+		return PurgeSummary{}, err
+	}
+
+	banners, err := qtx.PruneBanners(ctx, ids)
+	if err != nil {
+		return PurgeSummary{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return PurgeSummary{}, err
+	}
+
+	return PurgeSummary{
+		Banners:   int(banners),
+		Tags:      int(tags),
+		Revisions: int(revisions),
+	}, nil
+}
+
+// PurgeOrphanedTags hard deletes at most limit mock deletion tags
+// and tags of banners that no longer exist.
+func (r *repository) PurgeOrphanedTags(ctx context.Context, limit int) (int, error) {
+	n, err := r.queries.PruneOrphanedTags(ctx, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(n), nil
+}
+
+// PatchBanner applies the validated change to the banner and records a new
+// revision of it in a single transaction, so that a conflict leaves the banner
+// as it was. The banner is deactivated while it is rewritten and activated
+// last, so that intermediate states do not clash with other banners.
+func (r *repository) PatchBanner(ctx context.Context, id int, p *BannerPatch) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil {
+			r.logger.Error(err)
+		}
+	}()
+
+	indexes, err := r.patchBanner(ctx, r.queries.WithTx(tx), id, p)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	r.invalidate(ctx, indexes...)
+
+	return nil
+}
+
+// patchBanner applies the change within the transaction and returns the index
+// sets of the entries the banner is cached under before and after the change.
+func (r *repository) patchBanner(ctx context.Context, qtx *Queries, id int, p *BannerPatch) ([]string, error) {
+	data := p.data
+
+	banner, err := qtx.GetBannerByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// the banner is cached under both its old and its new placement
+	indexes, err := cacheIndexes(ctx, qtx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	isActive := banner.IsActive
+	if data.IsActive != nil {
+		isActive = *data.IsActive
+	}
+
+	if banner.IsActive {
+		if _, err = qtx.UpdateIsActiveByID(ctx, UpdateIsActiveByIDParams{ID: id}); err != nil {
+			return nil, err
+		}
+	}
+
+	if data.Content != nil {
+		content, err := json.Marshal(data.Content)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = qtx.UpdateBannerByID(ctx, UpdateBannerByIDParams{
+			Content: content,
+			ID:      id,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.localizedSet {
+		localized, err := json.Marshal(p.localized)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = qtx.UpdateLocalizedContentByID(ctx, UpdateLocalizedContentByIDParams{
+			LocalizedContent: localized,
+			ID:               id,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.scheduled {
+		_, err = qtx.UpdateScheduleByID(ctx, UpdateScheduleByIDParams{
+			ActiveFrom:  nullTime(p.activeFrom),
+			ActiveUntil: nullTime(p.activeUntil),
+			ID:          id,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if data.Priority != nil {
+		_, err = qtx.UpdatePriorityByID(ctx, UpdatePriorityByIDParams{
+			Priority: *data.Priority,
+			ID:       id,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if p.frequencyCapSet {
+		_, err = qtx.UpdateFrequencyCapByID(ctx, UpdateFrequencyCapByIDParams{
+			FrequencyCap: nullInt(data.FrequencyCap),
+			ID:           id,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if data.FeatureId != nil {
+		_, err = qtx.UpdateFeatureByID(ctx, UpdateFeatureByIDParams{
+			FeatureID: *data.FeatureId,
+			ID:        id,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if data.TagIds != nil {
+		if err = r.updateBannerTags(ctx, qtx, id, uniqueTags(*data.TagIds)); err != nil {
+			return nil, err
+		}
+	}
+
+	if isActive {
+		patched, err := qtx.GetBannerByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		pl, err := bannerPlacement(ctx, qtx, patched)
+		if err != nil {
+			return nil, err
+		}
+
+		if !patched.IsDeleted {
+			if err = checkConflicts(ctx, qtx, id, pl); err != nil {
+				return nil, err
+			}
+		}
+
+		_, err = qtx.UpdateIsActiveByID(ctx, UpdateIsActiveByIDParams{
+			IsActive: true,
+			ID:       id,
+		})
+		if err != nil {
+			return nil, r.conflictOrErr(ctx, err, id, pl)
+		}
+	}
+
+	if data.Content != nil || data.IsActive != nil || data.Priority != nil ||
+		data.FeatureId != nil || data.TagIds != nil || p.scheduled || p.localizedSet ||
+		p.frequencyCapSet {
+		if err = r.createRevision(ctx, qtx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	patched, err := cacheIndexes(ctx, qtx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(indexes, patched...), nil
+}
+
+// updateBannerTags replaces banner tags with the given ones reusing existing rows.
+// Rows already holding one of the new tags are kept as is, so that a tag
+// never appears twice for the banner while the rest are being rewritten.
+func (r *repository) updateBannerTags(ctx context.Context, qtx *Queries, id int, tags []int) error {
+	unusedTagID := -1 // mock deletion
+
+	currentTags, err := qtx.GetTagsByBannerID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	pending := make(map[int]struct{}, len(tags))
+	for _, tag := range tags {
+		pending[tag] = struct{}{}
+	}
+
+	free := make([]Tag, 0, len(currentTags))
+	for _, tag := range currentTags {
+		if _, ok := pending[tag.TagID]; ok {
+			delete(pending, tag.TagID)
+			continue
+		}
+		free = append(free, tag)
+	}
+
+	for _, tag := range tags {
+		if _, ok := pending[tag]; !ok {
+			continue
+		}
+
+		if len(free) == 0 {
+			_, err := qtx.CreateTag(ctx, CreateTagParams{
+				TagID:    tag,
+				BannerID: id,
+			})
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		_, err := qtx.UpdateBannerTagByID(ctx, UpdateBannerTagByIDParams{
+			TagID:    tag,
+			BannerID: id,
+			ID:       free[0].ID,
+		})
+		if err != nil {
+			return err
+		}
+		free = free[1:]
+	}
+
+	for _, tag := range free {
+		if tag.TagID == unusedTagID {
+			continue
+		}
+		_, err := qtx.UpdateBannerTagByID(ctx, UpdateBannerTagByIDParams{
+			TagID:    unusedTagID,
+			BannerID: id,
+			ID:       tag.ID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// createRevision snapshots the current state of the banner
+// and prunes revisions exceeding the configured limit.
+func (r *repository) createRevision(ctx context.Context, qtx *Queries, id int) error {
+	if _, err := qtx.CreateBannerRevision(ctx, id); err != nil {
+		return err
+	}
+
+	return qtx.PruneBannerRevisions(ctx, PruneBannerRevisionsParams{
+		BannerID: id,
+		Keep:     r.config.BannerRevisions,
+	})
+}
+
+func (r *repository) GetBannerRevisions(ctx context.Context, id int) ([]GetBannerVersionResponse, error) {
+	if _, err := r.queries.GetBannerByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	revisions, err := r.queries.GetBannerRevisions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]GetBannerVersionResponse, 0, len(revisions))
+
+	for _, rev := range revisions {
+		tags := make([]int, 0, 1)
+		if err := json.Unmarshal(rev.TagIds, &tags); err != nil {
+			return nil, err
+		}
+
+		response = append(response, GetBannerVersionResponse{
+			Version:          rev.Version,
+			FeatureID:        rev.FeatureID,
+			TagIDs:           tags,
+			Content:          rev.Content,
+			LocalizedContent: rev.LocalizedContent,
+			IsActive:         rev.IsActive,
+			ActiveFrom:       timePtr(rev.ActiveFrom),
+			ActiveUntil:      timePtr(rev.ActiveUntil),
+			Priority:         rev.Priority,
+			FrequencyCap:     intPtr(rev.FrequencyCap),
+			CreatedAt:        rev.CreatedAt,
+		})
+	}
+
+	return response, nil
+}
+
+func (r *repository) RollbackBanner(ctx context.Context, id int, version int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil {
+			r.logger.Error(err)
+		}
+	}()
+
+	qtx := r.queries.WithTx(tx)
+
+	rev, err := qtx.GetBannerRevision(ctx, GetBannerRevisionParams{
+		BannerID: id,
+		Version:  version,
+	})
+	if err != nil {
+		return err
+	}
+
+	tags := make([]int, 0, 1)
+	if err = json.Unmarshal(rev.TagIds, &tags); err != nil {
+		return err
+	}
+
+	banner, err := qtx.GetBannerByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// the banner is cached under both its old and its new placement
+	indexes, err := cacheIndexes(ctx, qtx, id)
+	if err != nil {
+		return err
+	}
+
+	p := placement{
+		featureID: rev.FeatureID,
+		tags:      tags,
+		from:      rev.ActiveFrom,
+		until:     rev.ActiveUntil,
+	}
+
+	if rev.IsActive && !banner.IsDeleted {
+		if err = checkConflicts(ctx, qtx, id, p); err != nil {
+			return err
+		}
+	}
+
+	_, err = qtx.RollbackBannerByID(ctx, RollbackBannerByIDParams{
+		FeatureID:        rev.FeatureID,
+		Content:          rev.Content,
+		IsActive:         rev.IsActive,
+		ActiveFrom:       rev.ActiveFrom,
+		ActiveUntil:      rev.ActiveUntil,
+		Priority:         rev.Priority,
+		LocalizedContent: rev.LocalizedContent,
+		FrequencyCap:     rev.FrequencyCap,
+		ID:               id,
+	})
+	if err != nil {
+		return r.conflictOrErr(ctx, err, id, p)
+	}
+
+	if err = r.updateBannerTags(ctx, qtx, id, tags); err != nil {
+		return r.conflictOrErr(ctx, err, id, p)
+	}
+
+	// rollback is recorded as a new revision to keep history linear
+	if err = r.createRevision(ctx, qtx, id); err != nil {
+		return err
+	}
+
+	rolledBack, err := cacheIndexes(ctx, qtx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	r.invalidate(ctx, append(indexes, rolledBack...)...)
+
+	return nil
+}
+
+func (r *repository) GetBannerByID(ctx context.Context, id int) (*Banner, error) {
+	banner, err := r.queries.GetBannerByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &banner, nil
+}
+
+// GetClickableBanner returns the banner if it can be shown right now:
+// it is active or a variant of a running experiment, not deleted and scheduled.
+func (r *repository) GetClickableBanner(ctx context.Context, id int) (*Banner, error) {
+	banner, err := r.queries.GetClickableBanner(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &banner, nil
+}
+
+func (r *repository) GetFeatureSchema(ctx context.Context, featureID int) (json.RawMessage, error) {
+	schema, err := r.queries.GetFeatureSchema(ctx, featureID)
+	if err != nil {
+		return nil, err
+	}
+
+	return schema.Schema, nil
+}
+
+func (r *repository) SetFeatureSchema(ctx context.Context, featureID int, schema json.RawMessage) error {
+	_, err := r.queries.UpsertFeatureSchema(ctx, UpsertFeatureSchemaParams{
+		FeatureID: featureID,
+		Schema:    schema,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *repository) GetFeatureFallback(ctx context.Context, featureID int) (int, error) {
+	fallback, err := r.queries.GetFeatureFallback(ctx, featureID)
+	if err != nil {
+		return 0, err
+	}
+
+	return fallback.BannerID, nil
+}
+
+func (r *repository) SetFeatureFallback(ctx context.Context, featureID int, bannerID int) error {
+	banner, err := r.queries.GetBannerByID(ctx, bannerID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return &InvalidFallbackError{
+				Reason: fmt.Sprintf("banner %d not found", bannerID),
+			}
+		}
+		return err
+	}
+
+	if banner.IsDeleted || banner.FeatureID != featureID {
+		return &InvalidFallbackError{
+			Reason: fmt.Sprintf("banner %d does not belong to feature %d", bannerID, featureID),
+		}
+	}
+
+	_, err = r.queries.UpsertFeatureFallback(ctx, UpsertFeatureFallbackParams{
+		FeatureID: featureID,
+		BannerID:  bannerID,
+	})
+	if err != nil {
+		return err
+	}
+
+	r.invalidate(ctx, slotIndex(featureID))
+
+	return nil
+}
+
+func (r *repository) DeleteFeatureFallback(ctx context.Context, featureID int) error {
+	if _, err := r.queries.PruneFeatureFallback(ctx, featureID); err != nil {
+		return err
+	}
+
+	r.invalidate(ctx, slotIndex(featureID))
+
+	return nil
+}
+
+// runningExperimentIndex guarantees a single running experiment per feature and tag.
+const runningExperimentIndex = "experiments_running_feature_id_tag_id_idx"
+
+// uniqueViolation is the PostgreSQL unique_violation error code.
+const uniqueViolation = "23505"
+
+// liveSlotConstraint guarantees a single active banner
+// per feature and tag at any moment of time.
+const liveSlotConstraint = "tags_live_slot_excl"
+
+// exclusionViolation is the PostgreSQL exclusion_violation error code.
+const exclusionViolation = "23P01"
+
+// placement describes where and when a banner is shown.
+type placement struct {
+	featureID int
+	tags      []int
+	from      sql.NullTime
+	until     sql.NullTime
+}
+
+// newPlacement returns the placement of the banner about to be created.
+func newPlacement(data PostBannerJSONBody) placement {
+	return placement{
+		featureID: *data.FeatureId,
+		tags:      uniqueTags(*data.TagIds),
+		from:      nullTime(data.ActiveFrom),
+		until:     nullTime(data.ActiveUntil),
+	}
+}
+
+// bannerPlacement returns the current placement of the banner.
+func bannerPlacement(ctx context.Context, q *Queries, banner Banner) (placement, error) {
+	tags, err := liveTags(ctx, q, banner.ID)
+	if err != nil {
+		return placement{}, err
+	}
+
+	return placement{
+		featureID: banner.FeatureID,
+		tags:      tags,
+		from:      banner.ActiveFrom,
+		until:     banner.ActiveUntil,
+	}, nil
+}
+
+// checkConflicts fails with ConflictingBannersError if other active banners
+// are already shown for the feature with any of the tags in an overlapping period.
+func checkConflicts(ctx context.Context, q *Queries, id int, p placement) error {
+	if len(p.tags) == 0 {
+		return nil
+	}
+
+	ids, err := q.GetConflictingBanners(ctx, GetConflictingBannersParams{
+		FeatureID:   p.featureID,
+		TagIds:      p.tags,
+		BannerID:    id,
+		ActiveFrom:  p.from,
+		ActiveUntil: p.until,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(ids) > 0 {
+		return &ConflictingBannersError{BannerIDs: ids}
+	}
+
+	return nil
+}
+
+// conflictOrErr turns a live slot constraint violation, caused by a concurrent
+// change that passed the same check, into ConflictingBannersError.
+func (r *repository) conflictOrErr(ctx context.Context, err error, id int, p placement) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != exclusionViolation ||
+		pgErr.ConstraintName != liveSlotConstraint {
+		return err
+	}
+
+	// the winning transaction is committed by now, so it is visible
+	if cerr := checkConflicts(ctx, r.queries, id, p); cerr != nil {
+		return cerr
+	}
+
+	return &ConflictingBannersError{BannerIDs: []int{}}
+}
+
+// liveTags returns the tags of the banner skipping the mock deleted ones.
+func liveTags(ctx context.Context, q *Queries, id int) ([]int, error) {
+	t, err := q.GetTagsByBannerID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]int, 0, len(t))
+	for _, tag := range t {
+		if tag.TagID != -1 {
+			tags = append(tags, tag.TagID)
+		}
+	}
+
+	return tags, nil
+}
+
+// uniqueTags drops repeated tags keeping the original order.
+func uniqueTags(tags []int) []int {
+	seen := make(map[int]struct{}, len(tags))
+	unique := make([]int, 0, len(tags))
+
+	for _, tag := range tags {
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		unique = append(unique, tag)
+	}
+
+	return unique
+}
+
+// FrequencyCapped reports whether the user has already seen the banner
+// as many times today as the cap allows. Nothing is counted here,
+// see CountImpression.
+func (r *repository) FrequencyCapped(ctx context.Context, banner Banner, userID int) (bool, error) {
+	if !banner.FrequencyCap.Valid {
+		return false, nil
+	}
+
+	count, err := r.rdb.Get(ctx, frequencyKey(banner.ID, userID, time.Now().UTC())).Int64()
+	if err != nil {
+		if err == redis.Nil {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return count >= int64(banner.FrequencyCap.Int32), nil
+}
+
+// CountImpression counts the impression of the banner for the user
+// against its frequency cap. Counters live in Redis until the end of the day in UTC.
+func (r *repository) CountImpression(ctx context.Context, banner Banner, userID int) error {
+	if !banner.FrequencyCap.Valid {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	key := frequencyKey(banner.ID, userID, now)
+
+	pipe := r.rdb.TxPipeline()
+	pipe.Incr(ctx, key)
+	pipe.ExpireAt(ctx, key, now.Truncate(24*time.Hour).Add(24*time.Hour))
+	_, err := pipe.Exec(ctx)
+
+	return err
+}
+
+// deletedFilter returns the values of is_deleted the banners are listed
+// or exported with, live banners only unless asked otherwise.
+func deletedFilter(includeDeleted, onlyDeleted *bool) []bool {
+	switch {
+	case onlyDeleted != nil && *onlyDeleted:
+		return []bool{true}
+	case includeDeleted != nil && *includeDeleted:
+		return []bool{false, true}
+	default:
+		return []bool{false}
+	}
+}
+
+// newBannerResponse builds admin representation of the banner.
+func newBannerResponse(b Banner, tags []int) GetBannerResponse {
+	return GetBannerResponse{
+		BannerID:         b.ID,
+		FeatureID:        b.FeatureID,
+		TagIDs:           tags,
+		Content:          b.Content,
+		LocalizedContent: b.LocalizedContent,
+		IsActive:         b.IsActive,
+		IsDeleted:        b.IsDeleted,
+		ActiveFrom:       timePtr(b.ActiveFrom),
+		ActiveUntil:      timePtr(b.ActiveUntil),
+		Priority:         b.Priority,
+		FrequencyCap:     intPtr(b.FrequencyCap),
+		CreatedAt:        b.CreatedAt,
+		UpdatedAt:        b.UpdatedAt,
+	}
+}
+
+// nullTime converts optional time of the API into the database one.
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
+
+// timePtr converts nullable time of the database into the API one.
+func timePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+// intPtr converts nullable integer of the database into the API one.
+func intPtr(i sql.NullInt32) *int {
+	if !i.Valid {
+		return nil
+	}
+	v := int(i.Int32)
+	return &v
+}
+
+// nullString converts optional string of the API into the database one.
+func nullString(s *string) sql.NullString {
+	if s == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *s, Valid: true}
+}
+
+// nullBool converts optional flag of the API into the database one.
+func nullBool(b *bool) sql.NullBool {
+	if b == nil {
+		return sql.NullBool{}
+	}
+	return sql.NullBool{Bool: *b, Valid: true}
+}
+
+// nullInt converts optional integer of the API into the database one.
+func nullInt(i *int) sql.NullInt32 {
+	if i == nil {
+		return sql.NullInt32{}
+	}
+	return sql.NullInt32{Int32: int32(*i), Valid: true}
+}
+
+// newDeleteJob builds admin representation of the delete job.
+func newDeleteJob(j BannerDeleteJob) (*DeleteJob, error) {
+	job := &DeleteJob{
+		JobId:      j.ID.String(),
+		Status:     DeleteJobStatus(j.Status),
+		BannerIds:  make([]int, 0),
+		Results:    make([]DeleteJobResult, 0),
+		FeatureId:  intPtr(j.FeatureID),
+		TagId:      intPtr(j.TagID),
+		CreatedAt:  j.CreatedAt,
+		StartedAt:  timePtr(j.StartedAt),
+		FinishedAt: timePtr(j.FinishedAt),
+	}
+	if j.Error.Valid {
+		job.Error = &j.Error.String
+	}
+
+	if err := json.Unmarshal(j.BannerIds, &job.BannerIds); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(j.Results, &job.Results); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// newDraft builds admin representation of the draft.
+func newDraft(d BannerDraft) (*Draft, error) {
+	payload := make(map[string]interface{})
+	if err := json.Unmarshal(d.Payload, &payload); err != nil {
+		return nil, err
+	}
+
+	draft := &Draft{
+		DraftId:     d.ID,
+		BannerId:    intPtr(d.BannerID),
+		Payload:     payload,
+		Status:      DraftStatus(d.Status),
+		CreatedBy:   d.CreatedBy,
+		SubmittedBy: intPtr(d.SubmittedBy),
+		SubmittedAt: timePtr(d.SubmittedAt),
+		ReviewedBy:  intPtr(d.ReviewedBy),
+		ReviewedAt:  timePtr(d.ReviewedAt),
+		CreatedAt:   d.CreatedAt,
+		UpdatedAt:   d.UpdatedAt,
+	}
+
+	if d.ReviewComment.Valid {
+		draft.ReviewComment = &d.ReviewComment.String
+	}
+
+	return draft, nil
+}
+
+func (r *repository) CreateDraft(ctx context.Context, bannerID *int, payload json.RawMessage, authorID int) (int, error) {
+	id, err := r.queries.CreateBannerDraft(ctx, CreateBannerDraftParams{
+		BannerID:  nullInt(bannerID),
+		Payload:   payload,
+		CreatedBy: authorID,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (r *repository) GetDraft(ctx context.Context, id int) (*Draft, error) {
+	draft, err := r.queries.GetBannerDraftByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return newDraft(draft)
+}
+
+func (r *repository) GetDrafts(ctx context.Context, status DraftStatus) ([]Draft, error) {
+	drafts, err := r.queries.GetBannerDraftsByStatus(ctx, string(status))
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]Draft, 0, len(drafts))
+
+	for _, d := range drafts {
+		draft, err := newDraft(d)
+		if err != nil {
+			return nil, err
+		}
+		response = append(response, *draft)
+	}
+
+	return response, nil
+}
+
+func (r *repository) SubmitDraft(ctx context.Context, id int, userID int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil {
+			r.logger.Error(err)
+		}
+	}()
+
+	qtx := r.queries.WithTx(tx)
+
+	draft, err := qtx.GetBannerDraftForUpdate(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// rejected drafts may be submitted once again
+	status := DraftStatus(draft.Status)
+	if status != DraftStatusDraft && status != DraftStatusRejected {
+		return &DraftStateError{Status: status}
+	}
+
+	_, err = qtx.SubmitBannerDraft(ctx, SubmitBannerDraftParams{
+		SubmittedBy: nullInt(&userID),
+		ID:          id,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ReviewDraft records the review of the pending draft. Approvals go through
+// ApproveDraft instead, which publishes the change of the draft as well.
+func (r *repository) ReviewDraft(ctx context.Context, id int, reviewerID int, status DraftStatus, comment *string) (*Draft, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil {
+			r.logger.Error(err)
+		}
+	}()
+
+	draft, err := r.reviewDraft(ctx, r.queries.WithTx(tx), id, reviewerID, status, comment)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return draft, nil
+}
+
+// ApproveDraft approves the pending draft and publishes its change in one
+// transaction: the draft is approved if and only if its change is live.
+// It returns the id of the published banner.
+func (r *repository) ApproveDraft(ctx context.Context, id int, reviewerID int, change DraftChange) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			r.logger.Error(err)
+		}
+	}()
+
+	qtx := r.queries.WithTx(tx)
+
+	draft, err := r.reviewDraft(ctx, qtx, id, reviewerID, DraftStatusApproved, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	var bannerID int
+	var indexes []string
+
+	if change.Patch != nil {
+		bannerID = *draft.BannerId
+		if indexes, err = r.patchBanner(ctx, qtx, bannerID, change.Patch); err != nil {
+			return 0, err
+		}
+	} else {
+		if bannerID, err = r.createBanner(ctx, qtx, *change.Banner); err != nil {
+			return 0, r.conflictOrErr(ctx, err, 0, newPlacement(*change.Banner))
+		}
+
+		if indexes, err = cacheIndexes(ctx, qtx, bannerID); err != nil {
+			return 0, err
+		}
+
+		_, err = qtx.UpdateBannerDraftBannerID(ctx, UpdateBannerDraftBannerIDParams{
+			BannerID: nullInt(&bannerID),
+			ID:       id,
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	r.invalidate(ctx, indexes...)
+
+	return bannerID, nil
+}
+
+// reviewDraft approves or rejects the pending draft within the transaction.
+// Neither its author nor the one who submitted it may review the draft.
+func (r *repository) reviewDraft(ctx context.Context, qtx *Queries, id int, reviewerID int, status DraftStatus, comment *string) (*Draft, error) {
+	draft, err := qtx.GetBannerDraftForUpdate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if DraftStatus(draft.Status) != DraftStatusPending {
+		return nil, &DraftStateError{Status: DraftStatus(draft.Status)}
+	}
+
+	if draft.CreatedBy == reviewerID ||
+		(draft.SubmittedBy.Valid && int(draft.SubmittedBy.Int32) == reviewerID) {
+		return nil, &SelfReviewError{}
+	}
+
+	reviewComment := sql.NullString{}
+	if comment != nil {
+		reviewComment = sql.NullString{String: *comment, Valid: true}
+	}
+
+	_, err = qtx.ReviewBannerDraft(ctx, ReviewBannerDraftParams{
+		Status:        string(status),
+		ReviewedBy:    nullInt(&reviewerID),
+		ReviewComment: reviewComment,
+		ID:            id,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newDraft(draft)
+}
+
+// GetManagedBanners returns the live banners managed by the manifest, keyed by external id.
+func (r *repository) GetManagedBanners(ctx context.Context, manifest string) (map[string]GetBannerResponse, error) {
+	rows, err := r.queries.GetManagedBanners(ctx, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	banners := make(map[string]GetBannerResponse, len(rows))
+	for _, row := range rows {
+		tags := make([]int, 0, 1)
+		if err := json.Unmarshal(row.TagIds, &tags); err != nil {
+			return nil, err
+		}
+
+		banners[row.ExternalID] = newBannerResponse(Banner{
+			ID:               row.ID,
+			FeatureID:        row.FeatureID,
+			IsActive:         row.IsActive,
+			IsDeleted:        row.IsDeleted,
+			CreatedAt:        row.CreatedAt,
+			UpdatedAt:        row.UpdatedAt,
+			Content:          row.Content,
+			ActiveFrom:       row.ActiveFrom,
+			ActiveUntil:      row.ActiveUntil,
+			Priority:         row.Priority,
+			LocalizedContent: row.LocalizedContent,
+			FrequencyCap:     row.FrequencyCap,
+		}, tags)
+	}
+
+	return banners, nil
+}
+
+// ApplyManifest applies the changes in one transaction and returns ids
+// of the created banners keyed by external id.
+func (r *repository) ApplyManifest(ctx context.Context, changes ManifestChanges) (map[string]int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil {
+			r.logger.Error(err)
+		}
+	}()
+
+	qtx := r.queries.WithTx(tx)
+
+	// the changed banners are cached under both their old and their new placement
+	changed := slices.Clone(changes.Deactivate)
+	for _, e := range changes.Update {
+		changed = append(changed, e.BannerID)
+	}
+
+	indexes, err := bannersIndexes(ctx, qtx, changed)
+	if err != nil {
+		return nil, err
+	}
+
+	// deactivate every changed banner first and activate last,
+	// so that banners trading places do not clash in between
+	for _, id := range changes.Deactivate {
+		if _, err = qtx.UpdateIsActiveByID(ctx, UpdateIsActiveByIDParams{ID: id}); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, e := range changes.Update {
+		content, localized, err := marshalContent(e.Data)
+		if err != nil {
+			return nil, err
+		}
+
+		p := newPlacement(e.Data)
+
+		// the update leaves the banner inactive, it is activated once every banner is in place;
+		// no rows means the banner has been deleted or unmanaged since the plan
+		_, err = qtx.UpdateManagedBannerByID(ctx, UpdateManagedBannerByIDParams{
+			FeatureID:        p.featureID,
+			Content:          content,
+			ActiveFrom:       p.from,
+			ActiveUntil:      p.until,
+			Priority:         newPriority(e.Data),
+			LocalizedContent: localized,
+			FrequencyCap:     nullInt(e.Data.FrequencyCap),
+			ID:               e.BannerID,
+			Manifest:         changes.Manifest,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if err = r.updateBannerTags(ctx, qtx, e.BannerID, p.tags); err != nil {
+			return nil, err
+		}
+	}
+
+	created := make(map[string]int, len(changes.Create))
+	for _, e := range changes.Create {
+		id, err := r.createBanner(ctx, qtx, e.Data)
+		if err != nil {
+			return nil, r.conflictOrErr(ctx, err, 0, newPlacement(e.Data))
+		}
+
+		_, err = qtx.UpsertManagedBanner(ctx, UpsertManagedBannerParams{
+			Manifest:   changes.Manifest,
+			ExternalID: e.ExternalID,
+			BannerID:   id,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		created[e.ExternalID] = id
+	}
+
+	for _, e := range changes.Update {
+		if *e.Data.IsActive {
+			p := newPlacement(e.Data)
+			if err = checkConflicts(ctx, qtx, e.BannerID, p); err != nil {
+				return nil, err
+			}
+
+			_, err = qtx.UpdateIsActiveByID(ctx, UpdateIsActiveByIDParams{
+				IsActive: true,
+				ID:       e.BannerID,
+			})
+			if err != nil {
+				return nil, r.conflictOrErr(ctx, err, e.BannerID, p)
+			}
+		}
+
+		if err = r.createRevision(ctx, qtx, e.BannerID); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, id := range changes.Deactivate {
+		if err = r.createRevision(ctx, qtx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, id := range created {
+		changed = append(changed, id)
+	}
+
+	applied, err := bannersIndexes(ctx, qtx, changed)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	r.invalidate(ctx, append(indexes, applied...)...)
+
+	return created, nil
+}
+
+func (r *repository) CreateBannerEvents(ctx context.Context, events ...BannerEvent) error {
+	params := CreateBannerEventsParams{
+		BannerIds:  make([]int, 0, len(events)),
+		FeatureIds: make([]int, 0, len(events)),
+		UserIds:    make([]int, 0, len(events)),
+		Kinds:      make([]string, 0, len(events)),
+		CreatedAts: make([]time.Time, 0, len(events)),
+	}
+
+	for _, e := range events {
+		params.BannerIds = append(params.BannerIds, e.BannerID)
+		params.FeatureIds = append(params.FeatureIds, e.FeatureID)
+		params.UserIds = append(params.UserIds, e.UserID)
+		params.Kinds = append(params.Kinds, e.Kind)
+		params.CreatedAts = append(params.CreatedAts, e.CreatedAt)
+	}
+
+	return r.queries.CreateBannerEvents(ctx, params)
+}
+
+// GetBannerStats counts the events grouped by banner, feature or tag.
+// Events are matched to the tags the banner has now, not the ones it had
+// at the time of the event, both by the tag filter and the tag grouping.
+func (r *repository) GetBannerStats(ctx context.Context, params GetAnalyticsParams) ([]GetAnalyticsResponse, error) {
+	groupBy := GetAnalyticsParamsGroupByBanner
+	if params.GroupBy != nil {
+		groupBy = *params.GroupBy
+	}
+
+	bannerID := nullInt(params.BannerId)
+	featureID := nullInt(params.FeatureId)
+	tagID := nullInt(params.TagId)
+	from := nullTime(params.From)
+	until := nullTime(params.Until)
+
+	response := make([]GetAnalyticsResponse, 0)
+
+	switch groupBy {
+	case GetAnalyticsParamsGroupByFeature:
+		stats, err := r.queries.GetFeatureStats(ctx, GetFeatureStatsParams{
+			BannerID:  bannerID,
+			FeatureID: featureID,
+			TagID:     tagID,
+			FromTime:  from,
+			UntilTime: until,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, s := range stats {
+			line := newAnalyticsResponse(s.Impressions, s.Clicks)
+			line.FeatureID = &s.FeatureID
+			response = append(response, line)
+		}
+
+	case GetAnalyticsParamsGroupByTag:
+		stats, err := r.queries.GetTagStats(ctx, GetTagStatsParams{
+			BannerID:  bannerID,
+			FeatureID: featureID,
+			TagID:     tagID,
+			FromTime:  from,
+			UntilTime: until,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, s := range stats {
+			line := newAnalyticsResponse(s.Impressions, s.Clicks)
+			line.TagID = &s.TagID
+			response = append(response, line)
+		}
+
+	default:
+		stats, err := r.queries.GetBannerStats(ctx, GetBannerStatsParams{
+			BannerID:  bannerID,
+			FeatureID: featureID,
+			TagID:     tagID,
+			FromTime:  from,
+			UntilTime: until,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, s := range stats {
+			line := newAnalyticsResponse(s.Impressions, s.Clicks)
+			line.BannerID = &s.BannerID
+			line.FeatureID = &s.FeatureID
+			response = append(response, line)
+		}
+	}
+
+	return response, nil
+}
+
+// newAnalyticsResponse is the line of the statistics with the counts.
+func newAnalyticsResponse(impressions, clicks int) GetAnalyticsResponse {
+	ctr := 0.0
+	if impressions > 0 {
+		ctr = float64(clicks) / float64(impressions)
+	}
+
+	return GetAnalyticsResponse{
+		Impressions: impressions,
+		Clicks:      clicks,
+		CTR:         ctr,
+	}
+}
+
+func (r *repository) CreateExperiment(ctx context.Context, data PostExperimentJSONBody) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil {
+			r.logger.Error(err)
+		}
+	}()
+
+	qtx := r.queries.WithTx(tx)
+
+	for _, v := range data.Variants {
+		banner, err := qtx.GetBannerByID(ctx, v.BannerId)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return 0, &InvalidExperimentError{
+					Reason: fmt.Sprintf("banner %d not found", v.BannerId),
+				}
+			}
+			return 0, err
+		}
+
+		if banner.IsDeleted || banner.FeatureID != data.FeatureId {
+			return 0, &InvalidExperimentError{
+				Reason: fmt.Sprintf("banner %d does not belong to feature %d", v.BannerId, data.FeatureId),
+			}
+		}
+	}
+
+	id, err := qtx.CreateExperiment(ctx, CreateExperimentParams{
+		FeatureID: data.FeatureId,
+		TagID:     data.TagId,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation &&
+			pgErr.ConstraintName == runningExperimentIndex {
+			return 0, &RunningExperimentError{}
+		}
+		return 0, err
+	}
+
+	for _, v := range data.Variants {
+		_, err = qtx.CreateExperimentVariant(ctx, CreateExperimentVariantParams{
+			ExperimentID: id,
+			BannerID:     v.BannerId,
+			Weight:       v.Weight,
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	r.invalidate(ctx, experimentIndex(data.FeatureId))
+
+	return id, nil
+}
+
+func (r *repository) GetExperiment(ctx context.Context, id int) (*GetExperimentResponse, error) {
+	experiment, err := r.queries.GetExperimentByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	variants, err := r.queries.GetExperimentVariants(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &GetExperimentResponse{
+		ExperimentID: experiment.ID,
+		FeatureID:    experiment.FeatureID,
+		TagID:        experiment.TagID,
+		Variants:     make([]Variant, 0, len(variants)),
+		CreatedAt:    experiment.CreatedAt,
+		UpdatedAt:    experiment.UpdatedAt,
+		EndedAt:      timePtr(experiment.EndedAt),
+	}
+
+	if experiment.WinnerID.Valid {
+		winnerID := int(experiment.WinnerID.Int32)
+		response.WinnerID = &winnerID
+	}
+
+	for _, v := range variants {
+		response.Variants = append(response.Variants, Variant{
+			BannerId: v.BannerID,
+			Weight:   v.Weight,
+		})
+	}
+
+	return response, nil
+}
+
+func (r *repository) UpdateExperimentWeights(ctx context.Context, id int, variants []Variant) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil {
+			r.logger.Error(err)
+		}
+	}()
+
+	qtx := r.queries.WithTx(tx)
+
+	experiment, err := qtx.GetRunningExperimentForUpdate(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range variants {
+		_, err = qtx.UpdateExperimentVariantWeight(ctx, UpdateExperimentVariantWeightParams{
+			Weight:       v.Weight,
+			ExperimentID: id,
+			BannerID:     v.BannerId,
+		})
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return &InvalidExperimentError{
+					Reason: fmt.Sprintf("banner %d is not a variant of the experiment", v.BannerId),
+				}
+			}
+			return err
+		}
+	}
+
+	if _, err = qtx.TouchExperiment(ctx, id); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	r.invalidate(ctx, experimentIndex(experiment.FeatureID))
+
+	return nil
+}
+
+func (r *repository) PromoteExperimentWinner(ctx context.Context, id int, bannerID int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil {
+			r.logger.Error(err)
+		}
+	}()
+
+	qtx := r.queries.WithTx(tx)
+
+	experiment, err := qtx.GetRunningExperimentForUpdate(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	variants, err := qtx.GetExperimentVariants(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !slices.ContainsFunc(variants, func(v ExperimentVariant) bool {
+		return v.BannerID == bannerID
+	}) {
+		return &InvalidExperimentError{
+			Reason: fmt.Sprintf("banner %d is not a variant of the experiment", bannerID),
+		}
+	}
+
+	banner, err := qtx.GetBannerByID(ctx, bannerID)
+	if err != nil {
+		return err
+	}
+
+	if banner.IsDeleted || banner.FeatureID != experiment.FeatureID {
+		return &InvalidExperimentError{
+			Reason: fmt.Sprintf("banner %d does not belong to feature %d", bannerID, experiment.FeatureID),
+		}
+	}
+
+	// banners shown with the tag at the same time as the winner are deactivated,
+	// their own tags are left as they are
+	released, err := qtx.ReleaseSlot(ctx, ReleaseSlotParams{
+		FeatureID:   experiment.FeatureID,
+		TagID:       experiment.TagID,
+		BannerID:    bannerID,
+		ActiveFrom:  banner.ActiveFrom,
+		ActiveUntil: banner.ActiveUntil,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, releasedID := range released {
+		if err = r.createRevision(ctx, qtx, releasedID); err != nil {
+			return err
+		}
+	}
+
+	p, err := bannerPlacement(ctx, qtx, banner)
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(p.tags, experiment.TagID) {
+		p.tags = append(p.tags, experiment.TagID)
+	}
+
+	if err = checkConflicts(ctx, qtx, bannerID, p); err != nil {
+		return err
+	}
+
+	if err = r.updateBannerTags(ctx, qtx, bannerID, p.tags); err != nil {
+		return r.conflictOrErr(ctx, err, bannerID, p)
+	}
+
+	if !banner.IsActive {
+		_, err = qtx.UpdateIsActiveByID(ctx, UpdateIsActiveByIDParams{
+			IsActive: true,
+			ID:       bannerID,
+		})
+		if err != nil {
+			return r.conflictOrErr(ctx, err, bannerID, p)
+		}
+	}
+
+	if err = r.createRevision(ctx, qtx, bannerID); err != nil {
+		return err
+	}
+
+	_, err = qtx.EndExperiment(ctx, EndExperimentParams{
+		WinnerID: sql.NullInt32{Int32: int32(bannerID), Valid: true},
+		ID:       id,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	r.invalidate(ctx,
+		experimentIndex(experiment.FeatureID),
+		slotIndex(experiment.FeatureID))
+
+	return nil
+}
+
+// slotKey is the cache key of the banner shown for the feature
+// and the set of user tags. The entry keeps every localization of the
+// content, so that it is shared by users asking for any locale.
+func slotKey(featureID int, tagIDs []int) string {
+	return fmt.Sprintf("feature_id:%d-tag_ids:%s", featureID, tagSet(tagIDs))
+}
+
+// frequencyKey is the key of the daily impression counter of the banner for the user.
+func frequencyKey(bannerID int, userID int, day time.Time) string {
+	return fmt.Sprintf("frequency:banner_id:%d-user_id:%d-day:%s", bannerID, userID, day.Format(time.DateOnly))
+}
+
+// experimentKey is the cache key of the experiment variants for the feature and the set of user tags.
+func experimentKey(featureID int, tagIDs []int) string {
+	return fmt.Sprintf("experiment:feature_id:%d-tag_ids:%s", featureID, tagSet(tagIDs))
+}
+
+// tagSet formats the tags sorted and without repeats,
+// so that the same set of tags always maps to the same key.
+func tagSet(tagIDs []int) string {
+	tags := uniqueTags(tagIDs)
+	slices.Sort(tags)
+
+	parts := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		parts = append(parts, strconv.Itoa(tag))
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// cachedBanner returns the banner cached under the key, or nil
+// if there is none or the banner is already past its schedule.
+func (r *repository) cachedBanner(ctx context.Context, key string) (*UserBanner, error) {
+	data, err := r.rdb.Get(ctx, key).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	banner := new(UserBanner)
+	if err := json.Unmarshal(data, banner); err != nil {
+		return nil, err
+	}
+
+	// never serve a banner past its schedule
+	if banner.ActiveUntil.Valid && !time.Now().Before(banner.ActiveUntil.Time) {
+		return nil, nil
+	}
+
+	return banner, nil
+}
+
+// cacheBanner caches the banner not longer than its schedule lasts,
+// registered in the index sets.
+func (r *repository) cacheBanner(ctx context.Context, key string, banner UserBanner, indexes ...string) error {
+	buf, _ := json.Marshal(banner)
+
+	expiration := r.config.CacheExpiration
+	if banner.ActiveUntil.Valid {
+		if untilEnd := time.Until(banner.ActiveUntil.Time); untilEnd < expiration {
+			expiration = untilEnd
+		}
+	}
+	if expiration <= 0 {
+		return nil
+	}
+
+	return r.cacheEntry(ctx, key, buf, expiration, indexes...)
+}
+
+func (r *repository) GetFeatures(ctx context.Context, includeArchived bool) ([]GetFeatureResponse, error) {
+	features, err := r.queries.GetFeatures(ctx, includeArchived)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]GetFeatureResponse, 0, len(features))
+	for _, f := range features {
+		response = append(response, newFeatureResponse(f))
+	}
+
+	return response, nil
+}
+
+func (r *repository) GetFeature(ctx context.Context, id int) (*GetFeatureResponse, error) {
+	feature, err := r.queries.GetFeature(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := newFeatureResponse(feature)
+	return &response, nil
+}
+
+// GetFeaturesByID returns the registered features of the ids keyed by id.
+func (r *repository) GetFeaturesByID(ctx context.Context, ids ...int) (map[int]Feature, error) {
+	features, err := r.queries.GetFeaturesByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make(map[int]Feature, len(features))
+	for _, f := range features {
+		response[f.ID] = f
+	}
+
+	return response, nil
+}
+
+func (r *repository) CreateFeature(ctx context.Context, data PostFeatureJSONBody) (*GetFeatureResponse, error) {
+	description := ""
+	if data.Description != nil {
+		description = *data.Description
+	}
+
+	feature, err := r.queries.CreateFeature(ctx, CreateFeatureParams{
+		ID:          *data.FeatureId,
+		Name:        *data.Name,
+		Description: description,
+	})
+	if err != nil {
+		return nil, existsOrErr(err, "feature")
+	}
+
+	response := newFeatureResponse(feature)
+	return &response, nil
+}
+
+func (r *repository) UpdateFeature(ctx context.Context, id int, data PatchFeatureFeatureIdJSONBody) (*GetFeatureResponse, error) {
+	feature, err := r.queries.UpdateFeature(ctx, UpdateFeatureParams{
+		Name:        nullString(data.Name),
+		Description: nullString(data.Description),
+		IsArchived:  nullBool(data.IsArchived),
+		ID:          id,
+	})
+	if err != nil {
+		return nil, existsOrErr(err, "feature")
+	}
+
+	response := newFeatureResponse(feature)
+	return &response, nil
+}
+
+// DeleteFeature deletes the feature no banner is placed in,
+// deleted banners waiting for the purge included.
+func (r *repository) DeleteFeature(ctx context.Context, id int) error {
+	_, err := r.queries.PruneFeature(ctx, id)
+	if err != sql.ErrNoRows {
+		return err
+	}
+
+	// tell a feature in use from a missing one
+	if _, err = r.queries.GetFeature(ctx, id); err != nil {
+		return err
+	}
+
+	return &InUseError{Entity: "feature", ID: id}
+}
+
+func (r *repository) GetTagDefinitions(ctx context.Context, includeArchived bool) ([]GetTagResponse, error) {
+	tags, err := r.queries.GetTagDefinitions(ctx, includeArchived)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]GetTagResponse, 0, len(tags))
+	for _, t := range tags {
+		response = append(response, newTagResponse(t))
+	}
+
+	return response, nil
+}
+
+func (r *repository) GetTagDefinition(ctx context.Context, id int) (*GetTagResponse, error) {
+	tag, err := r.queries.GetTagDefinition(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := newTagResponse(tag)
+	return &response, nil
+}
+
+// GetTagDefinitionsByID returns the registered tags of the ids keyed by id.
+func (r *repository) GetTagDefinitionsByID(ctx context.Context, ids ...int) (map[int]TagDefinition, error) {
+	tags, err := r.queries.GetTagDefinitionsByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make(map[int]TagDefinition, len(tags))
+	for _, t := range tags {
+		response[t.ID] = t
+	}
+
+	return response, nil
+}
+
+func (r *repository) CreateTagDefinition(ctx context.Context, data PostTagJSONBody) (*GetTagResponse, error) {
+	description := ""
+	if data.Description != nil {
+		description = *data.Description
+	}
+
+	tag, err := r.queries.CreateTagDefinition(ctx, CreateTagDefinitionParams{
+		ID:          *data.TagId,
+		Name:        *data.Name,
+		Description: description,
+	})
+	if err != nil {
+		return nil, existsOrErr(err, "tag")
+	}
+
+	response := newTagResponse(tag)
+	return &response, nil
+}
+
+func (r *repository) UpdateTagDefinition(ctx context.Context, id int, data PatchTagTagIdJSONBody) (*GetTagResponse, error) {
+	tag, err := r.queries.UpdateTagDefinition(ctx, UpdateTagDefinitionParams{
+		Name:        nullString(data.Name),
+		Description: nullString(data.Description),
+		IsArchived:  nullBool(data.IsArchived),
+		ID:          id,
+	})
+	if err != nil {
+		return nil, existsOrErr(err, "tag")
+	}
+
+	response := newTagResponse(tag)
+	return &response, nil
+}
+
+// DeleteTagDefinition deletes the tag no banner is shown for,
+// deleted banners waiting for the purge included.
+func (r *repository) DeleteTagDefinition(ctx context.Context, id int) error {
+	_, err := r.queries.PruneTagDefinition(ctx, id)
+	if err != sql.ErrNoRows {
+		return err
+	}
+
+	// tell a tag in use from a missing one
+	if _, err = r.queries.GetTagDefinition(ctx, id); err != nil {
+		return err
+	}
+
+	return &InUseError{Entity: "tag", ID: id}
+}
+
+// existsOrErr turns a clash with another entry of the catalog
+// into AlreadyExistsError and passes other errors through.
+func existsOrErr(err error, entity string) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != uniqueViolation {
+		return err
+	}
+
+	field := "name"
+	if strings.HasSuffix(pgErr.ConstraintName, "_pkey") {
+		field = "id"
+	}
+
+	return &AlreadyExistsError{Entity: entity, Field: field}
+}
+
+// newFeatureResponse builds admin representation of the feature.
+func newFeatureResponse(f Feature) GetFeatureResponse {
+	return GetFeatureResponse{
+		FeatureID:   f.ID,
+		Name:        f.Name,
+		Description: f.Description,
+		IsArchived:  f.IsArchived,
+		CreatedAt:   f.CreatedAt,
+		UpdatedAt:   f.UpdatedAt,
+	}
+}
+
+// newTagResponse builds admin representation of the tag.
+func newTagResponse(t TagDefinition) GetTagResponse {
+	return GetTagResponse{
+		TagID:       t.ID,
+		Name:        t.Name,
+		Description: t.Description,
+		IsArchived:  t.IsArchived,
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
+	}
+}
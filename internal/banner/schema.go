@@ -0,0 +1,80 @@
+package banner
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// schemaURL is a placeholder location the feature schema is registered under.
+const schemaURL = "mem:///feature_schema.json"
+
+// compileSchema compiles the JSON Schema of banner content.
+// External references are not resolved to keep the server
+// filesystem and network out of reach of the schema authors.
+func compileSchema(schema json.RawMessage) (*jsonschema.Schema, error) {
+	c := jsonschema.NewCompiler()
+	c.LoadURL = func(s string) (io.ReadCloser, error) {
+		return nil, fmt.Errorf("external references are not supported: %s", s)
+	}
+
+	if err := c.AddResource(schemaURL, bytes.NewReader(schema)); err != nil {
+		return nil, &InvalidSchemaError{Err: err}
+	}
+
+	compiled, err := c.Compile(schemaURL)
+	if err != nil {
+		return nil, &InvalidSchemaError{Err: err}
+	}
+
+	return compiled, nil
+}
+
+// validateContent validates banner content against the given JSON Schema
+// and reports every violated field as InvalidContentError.
+func validateContent(schema json.RawMessage, content map[string]interface{}) error {
+	compiled, err := compileSchema(schema)
+	if err != nil {
+		return err
+	}
+
+	err = compiled.Validate(content)
+	if err == nil {
+		return nil
+	}
+
+	var ve *jsonschema.ValidationError
+	if !errors.As(err, &ve) {
+		return err
+	}
+
+	fields := make([]FieldError, 0, 1)
+	collectFieldErrors(ve, &fields)
+	sort.SliceStable(fields, func(i, j int) bool {
+		return fields[i].Field < fields[j].Field
+	})
+
+	return &InvalidContentError{Fields: fields}
+}
+
+// collectFieldErrors flattens the validation error tree
+// leaving only the leaf causes pointing to the exact fields.
+func collectFieldErrors(ve *jsonschema.ValidationError, fields *[]FieldError) {
+	if len(ve.Causes) == 0 {
+		field := ve.InstanceLocation
+		if field == "" {
+			field = "/"
+		}
+		*fields = append(*fields, FieldError{Field: field, Error: ve.Message})
+		return
+	}
+
+	for _, cause := range ve.Causes {
+		collectFieldErrors(cause, fields)
+	}
+}
@@ -13,7 +13,10 @@ import (
 
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
-	// Удаление баннеров по id
+	// Получение показов, кликов и CTR баннеров
+	// (GET /analytics)
+	GetAnalytics(w http.ResponseWriter, r *http.Request, params GetAnalyticsParams)
+	// Удаление баннеров по id, фиче или тегу
 	// (DELETE /banner)
 	DeleteBanner(w http.ResponseWriter, r *http.Request, params DeleteBannerParams)
 	// Получение всех баннеров c фильтрацией по фиче и/или тегу
@@ -22,12 +25,114 @@ type ServerInterface interface {
 	// Создание нового баннера
 	// (POST /banner)
 	PostBanner(w http.ResponseWriter, r *http.Request, params PostBannerParams)
+	// Состояние задачи асинхронного удаления баннеров
+	// (GET /banner/delete_jobs/{id})
+	GetBannerDeleteJobsId(w http.ResponseWriter, r *http.Request, id string, params GetBannerDeleteJobsIdParams)
+	// Выгрузка баннеров c фильтрацией по фиче и/или тегу
+	// (GET /banner/export)
+	GetBannerExport(w http.ResponseWriter, r *http.Request, params GetBannerExportParams)
+	// Загрузка баннеров из файла
+	// (POST /banner/import)
+	PostBannerImport(w http.ResponseWriter, r *http.Request, params PostBannerImportParams)
+	// Окончательное удаление давно удаленных баннеров и осиротевших тегов
+	// (POST /banner/purge)
+	PostBannerPurge(w http.ResponseWriter, r *http.Request, params PostBannerPurgeParams)
 	// Удаление баннера по идентификатору
 	// (DELETE /banner/{id})
 	DeleteBannerId(w http.ResponseWriter, r *http.Request, id int, params DeleteBannerIdParams)
 	// Обновление содержимого баннера
 	// (PATCH /banner/{id})
 	PatchBannerId(w http.ResponseWriter, r *http.Request, id int, params PatchBannerIdParams)
+	// Восстановление удалённого баннера
+	// (POST /banner/{id}/restore)
+	PostBannerIdRestore(w http.ResponseWriter, r *http.Request, id int, params PostBannerIdRestoreParams)
+	// Получение истории версий баннера
+	// (GET /banner/{id}/versions)
+	GetBannerIdVersions(w http.ResponseWriter, r *http.Request, id int, params GetBannerIdVersionsParams)
+	// Откат баннера к выбранной версии
+	// (POST /banner/{id}/versions/{version}/rollback)
+	PostBannerIdVersionsVersionRollback(w http.ResponseWriter, r *http.Request, id int, version int, params PostBannerIdVersionsVersionRollbackParams)
+	// Получение черновиков баннеров по состоянию
+	// (GET /draft)
+	GetDraft(w http.ResponseWriter, r *http.Request, params GetDraftParams)
+	// Получение черновика баннера
+	// (GET /draft/{id})
+	GetDraftId(w http.ResponseWriter, r *http.Request, id int, params GetDraftIdParams)
+	// Одобрение черновика и публикация баннера
+	// (POST /draft/{id}/approve)
+	PostDraftIdApprove(w http.ResponseWriter, r *http.Request, id int, params PostDraftIdApproveParams)
+	// Отклонение черновика
+	// (POST /draft/{id}/reject)
+	PostDraftIdReject(w http.ResponseWriter, r *http.Request, id int, params PostDraftIdRejectParams)
+	// Отправка черновика на проверку
+	// (POST /draft/{id}/submit)
+	PostDraftIdSubmit(w http.ResponseWriter, r *http.Request, id int, params PostDraftIdSubmitParams)
+	// Создание A/B эксперимента для фичи и тэга
+	// (POST /experiment)
+	PostExperiment(w http.ResponseWriter, r *http.Request, params PostExperimentParams)
+	// Получение A/B эксперимента
+	// (GET /experiment/{id})
+	GetExperimentId(w http.ResponseWriter, r *http.Request, id int, params GetExperimentIdParams)
+	// Изменение весов вариантов запущенного эксперимента
+	// (PATCH /experiment/{id})
+	PatchExperimentId(w http.ResponseWriter, r *http.Request, id int, params PatchExperimentIdParams)
+	// Завершение эксперимента с выбором победителя
+	// (POST /experiment/{id}/promote)
+	PostExperimentIdPromote(w http.ResponseWriter, r *http.Request, id int, params PostExperimentIdPromoteParams)
+	// Получение каталога фич
+	// (GET /feature)
+	GetFeature(w http.ResponseWriter, r *http.Request, params GetFeatureParams)
+	// Регистрация фичи
+	// (POST /feature)
+	PostFeature(w http.ResponseWriter, r *http.Request, params PostFeatureParams)
+	// Удаление фичи
+	// (DELETE /feature/{feature_id})
+	DeleteFeatureFeatureId(w http.ResponseWriter, r *http.Request, featureId int, params DeleteFeatureFeatureIdParams)
+	// Получение фичи
+	// (GET /feature/{feature_id})
+	GetFeatureFeatureId(w http.ResponseWriter, r *http.Request, featureId int, params GetFeatureFeatureIdParams)
+	// Изменение фичи
+	// (PATCH /feature/{feature_id})
+	PatchFeatureFeatureId(w http.ResponseWriter, r *http.Request, featureId int, params PatchFeatureFeatureIdParams)
+	// Снятие баннера фичи по умолчанию
+	// (DELETE /feature/{feature_id}/fallback)
+	DeleteFeatureFeatureIdFallback(w http.ResponseWriter, r *http.Request, featureId int, params DeleteFeatureFeatureIdFallbackParams)
+	// Получение баннера фичи по умолчанию
+	// (GET /feature/{feature_id}/fallback)
+	GetFeatureFeatureIdFallback(w http.ResponseWriter, r *http.Request, featureId int, params GetFeatureFeatureIdFallbackParams)
+	// Назначение баннера фичи по умолчанию
+	// (PUT /feature/{feature_id}/fallback)
+	PutFeatureFeatureIdFallback(w http.ResponseWriter, r *http.Request, featureId int, params PutFeatureFeatureIdFallbackParams)
+	// Получение JSON-схемы содержимого баннеров фичи
+	// (GET /feature/{feature_id}/schema)
+	GetFeatureFeatureIdSchema(w http.ResponseWriter, r *http.Request, featureId int, params GetFeatureFeatureIdSchemaParams)
+	// Установка JSON-схемы содержимого баннеров фичи
+	// (PUT /feature/{feature_id}/schema)
+	PutFeatureFeatureIdSchema(w http.ResponseWriter, r *http.Request, featureId int, params PutFeatureFeatureIdSchemaParams)
+	// Применение манифеста баннеров
+	// (POST /manifest/apply)
+	PostManifestApply(w http.ResponseWriter, r *http.Request, params PostManifestApplyParams)
+	// План применения манифеста баннеров
+	// (POST /manifest/plan)
+	PostManifestPlan(w http.ResponseWriter, r *http.Request, params PostManifestPlanParams)
+	// Переход по ссылке баннера
+	// (GET /r/{banner_id})
+	GetRBannerId(w http.ResponseWriter, r *http.Request, bannerId int, params GetRBannerIdParams)
+	// Получение каталога тэгов
+	// (GET /tag)
+	GetTag(w http.ResponseWriter, r *http.Request, params GetTagParams)
+	// Регистрация тэга
+	// (POST /tag)
+	PostTag(w http.ResponseWriter, r *http.Request, params PostTagParams)
+	// Удаление тэга
+	// (DELETE /tag/{tag_id})
+	DeleteTagTagId(w http.ResponseWriter, r *http.Request, tagId int, params DeleteTagTagIdParams)
+	// Получение тэга
+	// (GET /tag/{tag_id})
+	GetTagTagId(w http.ResponseWriter, r *http.Request, tagId int, params GetTagTagIdParams)
+	// Изменение тэга
+	// (PATCH /tag/{tag_id})
+	PatchTagTagId(w http.ResponseWriter, r *http.Request, tagId int, params PatchTagTagIdParams)
 	// Получение баннера для пользователя
 	// (GET /user_banner)
 	GetUserBanner(w http.ResponseWriter, r *http.Request, params GetUserBannerParams)
@@ -37,59 +142,2118 @@ type ServerInterface interface {
 
 type Unimplemented struct{}
 
-// Удаление баннеров по id
+// Получение показов, кликов и CTR баннеров
+// (GET /analytics)
+func (_ Unimplemented) GetAnalytics(w http.ResponseWriter, r *http.Request, params GetAnalyticsParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Удаление баннеров по id, фиче или тегу
 // (DELETE /banner)
 func (_ Unimplemented) DeleteBanner(w http.ResponseWriter, r *http.Request, params DeleteBannerParams) {
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
-// Получение всех баннеров c фильтрацией по фиче и/или тегу
-// (GET /banner)
-func (_ Unimplemented) GetBanner(w http.ResponseWriter, r *http.Request, params GetBannerParams) {
-	w.WriteHeader(http.StatusNotImplemented)
-}
+// Получение всех баннеров c фильтрацией по фиче и/или тегу
+// (GET /banner)
+func (_ Unimplemented) GetBanner(w http.ResponseWriter, r *http.Request, params GetBannerParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Создание нового баннера
+// (POST /banner)
+func (_ Unimplemented) PostBanner(w http.ResponseWriter, r *http.Request, params PostBannerParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Состояние задачи асинхронного удаления баннеров
+// (GET /banner/delete_jobs/{id})
+func (_ Unimplemented) GetBannerDeleteJobsId(w http.ResponseWriter, r *http.Request, id string, params GetBannerDeleteJobsIdParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Выгрузка баннеров c фильтрацией по фиче и/или тегу
+// (GET /banner/export)
+func (_ Unimplemented) GetBannerExport(w http.ResponseWriter, r *http.Request, params GetBannerExportParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Загрузка баннеров из файла
+// (POST /banner/import)
+func (_ Unimplemented) PostBannerImport(w http.ResponseWriter, r *http.Request, params PostBannerImportParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Окончательное удаление давно удаленных баннеров и осиротевших тегов
+// (POST /banner/purge)
+func (_ Unimplemented) PostBannerPurge(w http.ResponseWriter, r *http.Request, params PostBannerPurgeParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Удаление баннера по идентификатору
+// (DELETE /banner/{id})
+func (_ Unimplemented) DeleteBannerId(w http.ResponseWriter, r *http.Request, id int, params DeleteBannerIdParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Обновление содержимого баннера
+// (PATCH /banner/{id})
+func (_ Unimplemented) PatchBannerId(w http.ResponseWriter, r *http.Request, id int, params PatchBannerIdParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Восстановление удалённого баннера
+// (POST /banner/{id}/restore)
+func (_ Unimplemented) PostBannerIdRestore(w http.ResponseWriter, r *http.Request, id int, params PostBannerIdRestoreParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Получение истории версий баннера
+// (GET /banner/{id}/versions)
+func (_ Unimplemented) GetBannerIdVersions(w http.ResponseWriter, r *http.Request, id int, params GetBannerIdVersionsParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Откат баннера к выбранной версии
+// (POST /banner/{id}/versions/{version}/rollback)
+func (_ Unimplemented) PostBannerIdVersionsVersionRollback(w http.ResponseWriter, r *http.Request, id int, version int, params PostBannerIdVersionsVersionRollbackParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Получение черновиков баннеров по состоянию
+// (GET /draft)
+func (_ Unimplemented) GetDraft(w http.ResponseWriter, r *http.Request, params GetDraftParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Получение черновика баннера
+// (GET /draft/{id})
+func (_ Unimplemented) GetDraftId(w http.ResponseWriter, r *http.Request, id int, params GetDraftIdParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Одобрение черновика и публикация баннера
+// (POST /draft/{id}/approve)
+func (_ Unimplemented) PostDraftIdApprove(w http.ResponseWriter, r *http.Request, id int, params PostDraftIdApproveParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Отклонение черновика
+// (POST /draft/{id}/reject)
+func (_ Unimplemented) PostDraftIdReject(w http.ResponseWriter, r *http.Request, id int, params PostDraftIdRejectParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Отправка черновика на проверку
+// (POST /draft/{id}/submit)
+func (_ Unimplemented) PostDraftIdSubmit(w http.ResponseWriter, r *http.Request, id int, params PostDraftIdSubmitParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Создание A/B эксперимента для фичи и тэга
+// (POST /experiment)
+func (_ Unimplemented) PostExperiment(w http.ResponseWriter, r *http.Request, params PostExperimentParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Получение A/B эксперимента
+// (GET /experiment/{id})
+func (_ Unimplemented) GetExperimentId(w http.ResponseWriter, r *http.Request, id int, params GetExperimentIdParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Изменение весов вариантов запущенного эксперимента
+// (PATCH /experiment/{id})
+func (_ Unimplemented) PatchExperimentId(w http.ResponseWriter, r *http.Request, id int, params PatchExperimentIdParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Завершение эксперимента с выбором победителя
+// (POST /experiment/{id}/promote)
+func (_ Unimplemented) PostExperimentIdPromote(w http.ResponseWriter, r *http.Request, id int, params PostExperimentIdPromoteParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Получение каталога фич
+// (GET /feature)
+func (_ Unimplemented) GetFeature(w http.ResponseWriter, r *http.Request, params GetFeatureParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Регистрация фичи
+// (POST /feature)
+func (_ Unimplemented) PostFeature(w http.ResponseWriter, r *http.Request, params PostFeatureParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Удаление фичи
+// (DELETE /feature/{feature_id})
+func (_ Unimplemented) DeleteFeatureFeatureId(w http.ResponseWriter, r *http.Request, featureId int, params DeleteFeatureFeatureIdParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Получение фичи
+// (GET /feature/{feature_id})
+func (_ Unimplemented) GetFeatureFeatureId(w http.ResponseWriter, r *http.Request, featureId int, params GetFeatureFeatureIdParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Изменение фичи
+// (PATCH /feature/{feature_id})
+func (_ Unimplemented) PatchFeatureFeatureId(w http.ResponseWriter, r *http.Request, featureId int, params PatchFeatureFeatureIdParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Снятие баннера фичи по умолчанию
+// (DELETE /feature/{feature_id}/fallback)
+func (_ Unimplemented) DeleteFeatureFeatureIdFallback(w http.ResponseWriter, r *http.Request, featureId int, params DeleteFeatureFeatureIdFallbackParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Получение баннера фичи по умолчанию
+// (GET /feature/{feature_id}/fallback)
+func (_ Unimplemented) GetFeatureFeatureIdFallback(w http.ResponseWriter, r *http.Request, featureId int, params GetFeatureFeatureIdFallbackParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Назначение баннера фичи по умолчанию
+// (PUT /feature/{feature_id}/fallback)
+func (_ Unimplemented) PutFeatureFeatureIdFallback(w http.ResponseWriter, r *http.Request, featureId int, params PutFeatureFeatureIdFallbackParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Получение JSON-схемы содержимого баннеров фичи
+// (GET /feature/{feature_id}/schema)
+func (_ Unimplemented) GetFeatureFeatureIdSchema(w http.ResponseWriter, r *http.Request, featureId int, params GetFeatureFeatureIdSchemaParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Установка JSON-схемы содержимого баннеров фичи
+// (PUT /feature/{feature_id}/schema)
+func (_ Unimplemented) PutFeatureFeatureIdSchema(w http.ResponseWriter, r *http.Request, featureId int, params PutFeatureFeatureIdSchemaParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Применение манифеста баннеров
+// (POST /manifest/apply)
+func (_ Unimplemented) PostManifestApply(w http.ResponseWriter, r *http.Request, params PostManifestApplyParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// План применения манифеста баннеров
+// (POST /manifest/plan)
+func (_ Unimplemented) PostManifestPlan(w http.ResponseWriter, r *http.Request, params PostManifestPlanParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Переход по ссылке баннера
+// (GET /r/{banner_id})
+func (_ Unimplemented) GetRBannerId(w http.ResponseWriter, r *http.Request, bannerId int, params GetRBannerIdParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Получение каталога тэгов
+// (GET /tag)
+func (_ Unimplemented) GetTag(w http.ResponseWriter, r *http.Request, params GetTagParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Регистрация тэга
+// (POST /tag)
+func (_ Unimplemented) PostTag(w http.ResponseWriter, r *http.Request, params PostTagParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Удаление тэга
+// (DELETE /tag/{tag_id})
+func (_ Unimplemented) DeleteTagTagId(w http.ResponseWriter, r *http.Request, tagId int, params DeleteTagTagIdParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Получение тэга
+// (GET /tag/{tag_id})
+func (_ Unimplemented) GetTagTagId(w http.ResponseWriter, r *http.Request, tagId int, params GetTagTagIdParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Изменение тэга
+// (PATCH /tag/{tag_id})
+func (_ Unimplemented) PatchTagTagId(w http.ResponseWriter, r *http.Request, tagId int, params PatchTagTagIdParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Получение баннера для пользователя
+// (GET /user_banner)
+func (_ Unimplemented) GetUserBanner(w http.ResponseWriter, r *http.Request, params GetUserBannerParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// ServerInterfaceWrapper converts contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler            ServerInterface
+	HandlerMiddlewares []MiddlewareFunc
+	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+type MiddlewareFunc func(http.Handler) http.Handler
+
+// GetAnalytics operation middleware
+func (siw *ServerInterfaceWrapper) GetAnalytics(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetAnalyticsParams
+
+	// ------------- Optional query parameter "banner_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "banner_id", r.URL.Query(), &params.BannerId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "banner_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "feature_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "feature_id", r.URL.Query(), &params.FeatureId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "feature_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "tag_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "tag_id", r.URL.Query(), &params.TagId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "tag_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "from", r.URL.Query(), &params.From)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "from", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "until" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "until", r.URL.Query(), &params.Until)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "until", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "group_by" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "group_by", r.URL.Query(), &params.GroupBy)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "group_by", Err: err})
+		return
+	}
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
+		var Token string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "token", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "token", valueList[0], &Token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+			return
+		}
+
+		params.Token = &Token
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetAnalytics(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// DeleteBanner operation middleware
+func (siw *ServerInterfaceWrapper) DeleteBanner(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteBannerParams
+
+	// ------------- Optional query parameter "feature_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "feature_id", r.URL.Query(), &params.FeatureId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "feature_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "tag_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "tag_id", r.URL.Query(), &params.TagId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "tag_id", Err: err})
+		return
+	}
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
+		var Token string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "token", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "token", valueList[0], &Token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+			return
+		}
+
+		params.Token = &Token
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteBanner(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetBanner operation middleware
+func (siw *ServerInterfaceWrapper) GetBanner(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetBannerParams
+
+	// ------------- Optional query parameter "feature_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "feature_id", r.URL.Query(), &params.FeatureId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "feature_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "tag_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "tag_id", r.URL.Query(), &params.TagId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "tag_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "offset", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "include_deleted" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "include_deleted", r.URL.Query(), &params.IncludeDeleted)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "include_deleted", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "only_deleted" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "only_deleted", r.URL.Query(), &params.OnlyDeleted)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "only_deleted", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "q" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "q", r.URL.Query(), &params.Q)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "q", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "after" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "after", r.URL.Query(), &params.After)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "after", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", r.URL.Query(), &params.Sort)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "sort", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "order" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "order", r.URL.Query(), &params.Order)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "order", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "with_total" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "with_total", r.URL.Query(), &params.WithTotal)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "with_total", Err: err})
+		return
+	}
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
+		var Token string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "token", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "token", valueList[0], &Token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+			return
+		}
+
+		params.Token = &Token
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetBanner(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostBanner operation middleware
+func (siw *ServerInterfaceWrapper) PostBanner(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostBannerParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
+		var Token string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "token", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "token", valueList[0], &Token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+			return
+		}
+
+		params.Token = &Token
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostBanner(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetBannerDeleteJobsId operation middleware
+func (siw *ServerInterfaceWrapper) GetBannerDeleteJobsId(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetBannerDeleteJobsIdParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
+		var Token string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "token", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "token", valueList[0], &Token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+			return
+		}
+
+		params.Token = &Token
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetBannerDeleteJobsId(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetBannerExport operation middleware
+func (siw *ServerInterfaceWrapper) GetBannerExport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetBannerExportParams
+
+	// ------------- Optional query parameter "feature_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "feature_id", r.URL.Query(), &params.FeatureId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "feature_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "tag_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "tag_id", r.URL.Query(), &params.TagId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "tag_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "offset", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "include_deleted" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "include_deleted", r.URL.Query(), &params.IncludeDeleted)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "include_deleted", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "only_deleted" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "only_deleted", r.URL.Query(), &params.OnlyDeleted)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "only_deleted", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", r.URL.Query(), &params.Format)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "format", Err: err})
+		return
+	}
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
+		var Token string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "token", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "token", valueList[0], &Token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+			return
+		}
+
+		params.Token = &Token
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetBannerExport(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostBannerImport operation middleware
+func (siw *ServerInterfaceWrapper) PostBannerImport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostBannerImportParams
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", r.URL.Query(), &params.Format)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "format", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "dry_run" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "dry_run", r.URL.Query(), &params.DryRun)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "dry_run", Err: err})
+		return
+	}
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
+		var Token string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "token", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "token", valueList[0], &Token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+			return
+		}
+
+		params.Token = &Token
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostBannerImport(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostBannerPurge operation middleware
+func (siw *ServerInterfaceWrapper) PostBannerPurge(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostBannerPurgeParams
+
+	// ------------- Optional query parameter "dry_run" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "dry_run", r.URL.Query(), &params.DryRun)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "dry_run", Err: err})
+		return
+	}
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
+		var Token string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "token", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "token", valueList[0], &Token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+			return
+		}
+
+		params.Token = &Token
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostBannerPurge(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// DeleteBannerId operation middleware
+func (siw *ServerInterfaceWrapper) DeleteBannerId(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteBannerIdParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
+		var Token string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "token", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "token", valueList[0], &Token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+			return
+		}
+
+		params.Token = &Token
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteBannerId(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PatchBannerId operation middleware
+func (siw *ServerInterfaceWrapper) PatchBannerId(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PatchBannerIdParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
+		var Token string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "token", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "token", valueList[0], &Token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+			return
+		}
+
+		params.Token = &Token
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PatchBannerId(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostBannerIdRestore operation middleware
+func (siw *ServerInterfaceWrapper) PostBannerIdRestore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostBannerIdRestoreParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
+		var Token string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "token", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "token", valueList[0], &Token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+			return
+		}
+
+		params.Token = &Token
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostBannerIdRestore(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetBannerIdVersions operation middleware
+func (siw *ServerInterfaceWrapper) GetBannerIdVersions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetBannerIdVersionsParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
+		var Token string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "token", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "token", valueList[0], &Token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+			return
+		}
+
+		params.Token = &Token
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetBannerIdVersions(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostBannerIdVersionsVersionRollback operation middleware
+func (siw *ServerInterfaceWrapper) PostBannerIdVersionsVersionRollback(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "version" -------------
+	var version int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "version", chi.URLParam(r, "version"), &version, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "version", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostBannerIdVersionsVersionRollbackParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
+		var Token string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "token", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "token", valueList[0], &Token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+			return
+		}
+
+		params.Token = &Token
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostBannerIdVersionsVersionRollback(w, r, id, version, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetDraft operation middleware
+func (siw *ServerInterfaceWrapper) GetDraft(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetDraftParams
+
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", r.URL.Query(), &params.Status)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "status", Err: err})
+		return
+	}
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
+		var Token string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "token", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "token", valueList[0], &Token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+			return
+		}
+
+		params.Token = &Token
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetDraft(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetDraftId operation middleware
+func (siw *ServerInterfaceWrapper) GetDraftId(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetDraftIdParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
+		var Token string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "token", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "token", valueList[0], &Token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+			return
+		}
+
+		params.Token = &Token
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetDraftId(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostDraftIdApprove operation middleware
+func (siw *ServerInterfaceWrapper) PostDraftIdApprove(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostDraftIdApproveParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
+		var Token string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "token", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "token", valueList[0], &Token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+			return
+		}
+
+		params.Token = &Token
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostDraftIdApprove(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostDraftIdReject operation middleware
+func (siw *ServerInterfaceWrapper) PostDraftIdReject(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostDraftIdRejectParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
+		var Token string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "token", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "token", valueList[0], &Token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+			return
+		}
+
+		params.Token = &Token
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostDraftIdReject(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostDraftIdSubmit operation middleware
+func (siw *ServerInterfaceWrapper) PostDraftIdSubmit(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostDraftIdSubmitParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
+		var Token string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "token", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "token", valueList[0], &Token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+			return
+		}
+
+		params.Token = &Token
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostDraftIdSubmit(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostExperiment operation middleware
+func (siw *ServerInterfaceWrapper) PostExperiment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostExperimentParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
+		var Token string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "token", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "token", valueList[0], &Token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+			return
+		}
+
+		params.Token = &Token
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostExperiment(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetExperimentId operation middleware
+func (siw *ServerInterfaceWrapper) GetExperimentId(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetExperimentIdParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
+		var Token string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "token", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "token", valueList[0], &Token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+			return
+		}
+
+		params.Token = &Token
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetExperimentId(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PatchExperimentId operation middleware
+func (siw *ServerInterfaceWrapper) PatchExperimentId(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PatchExperimentIdParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
+		var Token string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "token", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "token", valueList[0], &Token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+			return
+		}
+
+		params.Token = &Token
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PatchExperimentId(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostExperimentIdPromote operation middleware
+func (siw *ServerInterfaceWrapper) PostExperimentIdPromote(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostExperimentIdPromoteParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
+		var Token string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "token", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "token", valueList[0], &Token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+			return
+		}
+
+		params.Token = &Token
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostExperimentIdPromote(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetFeature operation middleware
+func (siw *ServerInterfaceWrapper) GetFeature(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetFeatureParams
+
+	// ------------- Optional query parameter "include_archived" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "include_archived", r.URL.Query(), &params.IncludeArchived)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "include_archived", Err: err})
+		return
+	}
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
+		var Token string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "token", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "token", valueList[0], &Token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+			return
+		}
+
+		params.Token = &Token
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetFeature(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostFeature operation middleware
+func (siw *ServerInterfaceWrapper) PostFeature(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostFeatureParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
+		var Token string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "token", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "token", valueList[0], &Token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+			return
+		}
+
+		params.Token = &Token
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostFeature(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// DeleteFeatureFeatureId operation middleware
+func (siw *ServerInterfaceWrapper) DeleteFeatureFeatureId(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "feature_id" -------------
+	var featureId int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "feature_id", chi.URLParam(r, "feature_id"), &featureId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "feature_id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteFeatureFeatureIdParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
+		var Token string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "token", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "token", valueList[0], &Token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+			return
+		}
+
+		params.Token = &Token
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteFeatureFeatureId(w, r, featureId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetFeatureFeatureId operation middleware
+func (siw *ServerInterfaceWrapper) GetFeatureFeatureId(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "feature_id" -------------
+	var featureId int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "feature_id", chi.URLParam(r, "feature_id"), &featureId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "feature_id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetFeatureFeatureIdParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
+		var Token string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "token", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "token", valueList[0], &Token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+			return
+		}
+
+		params.Token = &Token
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetFeatureFeatureId(w, r, featureId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PatchFeatureFeatureId operation middleware
+func (siw *ServerInterfaceWrapper) PatchFeatureFeatureId(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "feature_id" -------------
+	var featureId int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "feature_id", chi.URLParam(r, "feature_id"), &featureId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "feature_id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PatchFeatureFeatureIdParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
+		var Token string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "token", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "token", valueList[0], &Token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+			return
+		}
+
+		params.Token = &Token
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PatchFeatureFeatureId(w, r, featureId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// DeleteFeatureFeatureIdFallback operation middleware
+func (siw *ServerInterfaceWrapper) DeleteFeatureFeatureIdFallback(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "feature_id" -------------
+	var featureId int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "feature_id", chi.URLParam(r, "feature_id"), &featureId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "feature_id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteFeatureFeatureIdFallbackParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
+		var Token string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "token", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "token", valueList[0], &Token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+			return
+		}
+
+		params.Token = &Token
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteFeatureFeatureIdFallback(w, r, featureId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetFeatureFeatureIdFallback operation middleware
+func (siw *ServerInterfaceWrapper) GetFeatureFeatureIdFallback(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "feature_id" -------------
+	var featureId int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "feature_id", chi.URLParam(r, "feature_id"), &featureId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "feature_id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetFeatureFeatureIdFallbackParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
+		var Token string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "token", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "token", valueList[0], &Token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+			return
+		}
+
+		params.Token = &Token
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetFeatureFeatureIdFallback(w, r, featureId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PutFeatureFeatureIdFallback operation middleware
+func (siw *ServerInterfaceWrapper) PutFeatureFeatureIdFallback(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "feature_id" -------------
+	var featureId int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "feature_id", chi.URLParam(r, "feature_id"), &featureId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "feature_id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PutFeatureFeatureIdFallbackParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
+		var Token string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "token", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "token", valueList[0], &Token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+			return
+		}
+
+		params.Token = &Token
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PutFeatureFeatureIdFallback(w, r, featureId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetFeatureFeatureIdSchema operation middleware
+func (siw *ServerInterfaceWrapper) GetFeatureFeatureIdSchema(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "feature_id" -------------
+	var featureId int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "feature_id", chi.URLParam(r, "feature_id"), &featureId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "feature_id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetFeatureFeatureIdSchemaParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
+		var Token string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "token", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "token", valueList[0], &Token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+			return
+		}
+
+		params.Token = &Token
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetFeatureFeatureIdSchema(w, r, featureId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PutFeatureFeatureIdSchema operation middleware
+func (siw *ServerInterfaceWrapper) PutFeatureFeatureIdSchema(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "feature_id" -------------
+	var featureId int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "feature_id", chi.URLParam(r, "feature_id"), &featureId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "feature_id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PutFeatureFeatureIdSchemaParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
+		var Token string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "token", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "token", valueList[0], &Token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+			return
+		}
+
+		params.Token = &Token
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PutFeatureFeatureIdSchema(w, r, featureId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostManifestApply operation middleware
+func (siw *ServerInterfaceWrapper) PostManifestApply(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostManifestApplyParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
+		var Token string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "token", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "token", valueList[0], &Token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+			return
+		}
+
+		params.Token = &Token
 
-// Создание нового баннера
-// (POST /banner)
-func (_ Unimplemented) PostBanner(w http.ResponseWriter, r *http.Request, params PostBannerParams) {
-	w.WriteHeader(http.StatusNotImplemented)
-}
+	}
 
-// Удаление баннера по идентификатору
-// (DELETE /banner/{id})
-func (_ Unimplemented) DeleteBannerId(w http.ResponseWriter, r *http.Request, id int, params DeleteBannerIdParams) {
-	w.WriteHeader(http.StatusNotImplemented)
-}
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostManifestApply(w, r, params)
+	}))
 
-// Обновление содержимого баннера
-// (PATCH /banner/{id})
-func (_ Unimplemented) PatchBannerId(w http.ResponseWriter, r *http.Request, id int, params PatchBannerIdParams) {
-	w.WriteHeader(http.StatusNotImplemented)
-}
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-// Получение баннера для пользователя
-// (GET /user_banner)
-func (_ Unimplemented) GetUserBanner(w http.ResponseWriter, r *http.Request, params GetUserBannerParams) {
-	w.WriteHeader(http.StatusNotImplemented)
+	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// ServerInterfaceWrapper converts contexts to parameters.
-type ServerInterfaceWrapper struct {
-	Handler            ServerInterface
-	HandlerMiddlewares []MiddlewareFunc
-	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
-}
+// PostManifestPlan operation middleware
+func (siw *ServerInterfaceWrapper) PostManifestPlan(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 
-type MiddlewareFunc func(http.Handler) http.Handler
+	var err error
 
-// DeleteBanner operation middleware
-func (siw *ServerInterfaceWrapper) DeleteBanner(w http.ResponseWriter, r *http.Request) {
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostManifestPlanParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
+		var Token string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "token", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "token", valueList[0], &Token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+			return
+		}
+
+		params.Token = &Token
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostManifestPlan(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// GetRBannerId operation middleware
+func (siw *ServerInterfaceWrapper) GetRBannerId(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
 
+	// ------------- Path parameter "banner_id" -------------
+	var bannerId int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "banner_id", chi.URLParam(r, "banner_id"), &bannerId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "banner_id", Err: err})
+		return
+	}
+
 	// Parameter object where we will unmarshal all parameters from the context
-	var params DeleteBannerParams
+	var params GetRBannerIdParams
 
 	headers := r.Header
 
@@ -113,7 +2277,7 @@ func (siw *ServerInterfaceWrapper) DeleteBanner(w http.ResponseWriter, r *http.R
 	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.DeleteBanner(w, r, params)
+		siw.Handler.GetRBannerId(w, r, bannerId, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -123,47 +2287,64 @@ func (siw *ServerInterfaceWrapper) DeleteBanner(w http.ResponseWriter, r *http.R
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// GetBanner operation middleware
-func (siw *ServerInterfaceWrapper) GetBanner(w http.ResponseWriter, r *http.Request) {
+// GetTag operation middleware
+func (siw *ServerInterfaceWrapper) GetTag(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
 
 	// Parameter object where we will unmarshal all parameters from the context
-	var params GetBannerParams
+	var params GetTagParams
 
-	// ------------- Optional query parameter "feature_id" -------------
+	// ------------- Optional query parameter "include_archived" -------------
 
-	err = runtime.BindQueryParameter("form", true, false, "feature_id", r.URL.Query(), &params.FeatureId)
+	err = runtime.BindQueryParameter("form", true, false, "include_archived", r.URL.Query(), &params.IncludeArchived)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "feature_id", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "include_archived", Err: err})
 		return
 	}
 
-	// ------------- Optional query parameter "tag_id" -------------
+	headers := r.Header
 
-	err = runtime.BindQueryParameter("form", true, false, "tag_id", r.URL.Query(), &params.TagId)
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "tag_id", Err: err})
-		return
-	}
+	// ------------- Optional header parameter "token" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
+		var Token string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "token", Count: n})
+			return
+		}
 
-	// ------------- Optional query parameter "limit" -------------
+		err = runtime.BindStyledParameterWithOptions("simple", "token", valueList[0], &Token, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "token", Err: err})
+			return
+		}
+
+		params.Token = &Token
 
-	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
-		return
 	}
 
-	// ------------- Optional query parameter "offset" -------------
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetTag(w, r, params)
+	}))
 
-	err = runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset)
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "offset", Err: err})
-		return
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
 	}
 
+	handler.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// PostTag operation middleware
+func (siw *ServerInterfaceWrapper) PostTag(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostTagParams
+
 	headers := r.Header
 
 	// ------------- Optional header parameter "token" -------------
@@ -186,7 +2367,7 @@ func (siw *ServerInterfaceWrapper) GetBanner(w http.ResponseWriter, r *http.Requ
 	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetBanner(w, r, params)
+		siw.Handler.PostTag(w, r, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -196,14 +2377,23 @@ func (siw *ServerInterfaceWrapper) GetBanner(w http.ResponseWriter, r *http.Requ
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// PostBanner operation middleware
-func (siw *ServerInterfaceWrapper) PostBanner(w http.ResponseWriter, r *http.Request) {
+// DeleteTagTagId operation middleware
+func (siw *ServerInterfaceWrapper) DeleteTagTagId(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
 
+	// ------------- Path parameter "tag_id" -------------
+	var tagId int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "tag_id", chi.URLParam(r, "tag_id"), &tagId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "tag_id", Err: err})
+		return
+	}
+
 	// Parameter object where we will unmarshal all parameters from the context
-	var params PostBannerParams
+	var params DeleteTagTagIdParams
 
 	headers := r.Header
 
@@ -227,7 +2417,7 @@ func (siw *ServerInterfaceWrapper) PostBanner(w http.ResponseWriter, r *http.Req
 	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostBanner(w, r, params)
+		siw.Handler.DeleteTagTagId(w, r, tagId, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -237,23 +2427,23 @@ func (siw *ServerInterfaceWrapper) PostBanner(w http.ResponseWriter, r *http.Req
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// DeleteBannerId operation middleware
-func (siw *ServerInterfaceWrapper) DeleteBannerId(w http.ResponseWriter, r *http.Request) {
+// GetTagTagId operation middleware
+func (siw *ServerInterfaceWrapper) GetTagTagId(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
 
-	// ------------- Path parameter "id" -------------
-	var id int
+	// ------------- Path parameter "tag_id" -------------
+	var tagId int
 
-	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	err = runtime.BindStyledParameterWithOptions("simple", "tag_id", chi.URLParam(r, "tag_id"), &tagId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "tag_id", Err: err})
 		return
 	}
 
 	// Parameter object where we will unmarshal all parameters from the context
-	var params DeleteBannerIdParams
+	var params GetTagTagIdParams
 
 	headers := r.Header
 
@@ -277,7 +2467,7 @@ func (siw *ServerInterfaceWrapper) DeleteBannerId(w http.ResponseWriter, r *http
 	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.DeleteBannerId(w, r, id, params)
+		siw.Handler.GetTagTagId(w, r, tagId, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -287,23 +2477,23 @@ func (siw *ServerInterfaceWrapper) DeleteBannerId(w http.ResponseWriter, r *http
 	handler.ServeHTTP(w, r.WithContext(ctx))
 }
 
-// PatchBannerId operation middleware
-func (siw *ServerInterfaceWrapper) PatchBannerId(w http.ResponseWriter, r *http.Request) {
+// PatchTagTagId operation middleware
+func (siw *ServerInterfaceWrapper) PatchTagTagId(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	var err error
 
-	// ------------- Path parameter "id" -------------
-	var id int
+	// ------------- Path parameter "tag_id" -------------
+	var tagId int
 
-	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	err = runtime.BindStyledParameterWithOptions("simple", "tag_id", chi.URLParam(r, "tag_id"), &tagId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "tag_id", Err: err})
 		return
 	}
 
 	// Parameter object where we will unmarshal all parameters from the context
-	var params PatchBannerIdParams
+	var params PatchTagTagIdParams
 
 	headers := r.Header
 
@@ -327,7 +2517,7 @@ func (siw *ServerInterfaceWrapper) PatchBannerId(w http.ResponseWriter, r *http.
 	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PatchBannerId(w, r, id, params)
+		siw.Handler.PatchTagTagId(w, r, tagId, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -384,8 +2574,43 @@ func (siw *ServerInterfaceWrapper) GetUserBanner(w http.ResponseWriter, r *http.
 		return
 	}
 
+	// ------------- Optional query parameter "locale" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "locale", r.URL.Query(), &params.Locale)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "locale", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "track_impression" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "track_impression", r.URL.Query(), &params.TrackImpression)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "track_impression", Err: err})
+		return
+	}
+
 	headers := r.Header
 
+	// ------------- Optional header parameter "Accept-Language" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Accept-Language")]; found {
+		var AcceptLanguage string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Accept-Language", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Accept-Language", valueList[0], &AcceptLanguage, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Accept-Language", Err: err})
+			return
+		}
+
+		params.AcceptLanguage = &AcceptLanguage
+
+	}
+
 	// ------------- Optional header parameter "token" -------------
 	if valueList, found := headers[http.CanonicalHeaderKey("token")]; found {
 		var Token string
@@ -529,6 +2754,9 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 		ErrorHandlerFunc:   options.ErrorHandlerFunc,
 	}
 
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/analytics", wrapper.GetAnalytics)
+	})
 	r.Group(func(r chi.Router) {
 		r.Delete(options.BaseURL+"/banner", wrapper.DeleteBanner)
 	})
@@ -538,12 +2766,114 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/banner", wrapper.PostBanner)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/banner/delete_jobs/{id}", wrapper.GetBannerDeleteJobsId)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/banner/export", wrapper.GetBannerExport)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/banner/import", wrapper.PostBannerImport)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/banner/purge", wrapper.PostBannerPurge)
+	})
 	r.Group(func(r chi.Router) {
 		r.Delete(options.BaseURL+"/banner/{id}", wrapper.DeleteBannerId)
 	})
 	r.Group(func(r chi.Router) {
 		r.Patch(options.BaseURL+"/banner/{id}", wrapper.PatchBannerId)
 	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/banner/{id}/restore", wrapper.PostBannerIdRestore)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/banner/{id}/versions", wrapper.GetBannerIdVersions)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/banner/{id}/versions/{version}/rollback", wrapper.PostBannerIdVersionsVersionRollback)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/draft", wrapper.GetDraft)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/draft/{id}", wrapper.GetDraftId)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/draft/{id}/approve", wrapper.PostDraftIdApprove)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/draft/{id}/reject", wrapper.PostDraftIdReject)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/draft/{id}/submit", wrapper.PostDraftIdSubmit)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/experiment", wrapper.PostExperiment)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/experiment/{id}", wrapper.GetExperimentId)
+	})
+	r.Group(func(r chi.Router) {
+		r.Patch(options.BaseURL+"/experiment/{id}", wrapper.PatchExperimentId)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/experiment/{id}/promote", wrapper.PostExperimentIdPromote)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/feature", wrapper.GetFeature)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/feature", wrapper.PostFeature)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/feature/{feature_id}", wrapper.DeleteFeatureFeatureId)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/feature/{feature_id}", wrapper.GetFeatureFeatureId)
+	})
+	r.Group(func(r chi.Router) {
+		r.Patch(options.BaseURL+"/feature/{feature_id}", wrapper.PatchFeatureFeatureId)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/feature/{feature_id}/fallback", wrapper.DeleteFeatureFeatureIdFallback)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/feature/{feature_id}/fallback", wrapper.GetFeatureFeatureIdFallback)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/feature/{feature_id}/fallback", wrapper.PutFeatureFeatureIdFallback)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/feature/{feature_id}/schema", wrapper.GetFeatureFeatureIdSchema)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/feature/{feature_id}/schema", wrapper.PutFeatureFeatureIdSchema)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/manifest/apply", wrapper.PostManifestApply)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/manifest/plan", wrapper.PostManifestPlan)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/r/{banner_id}", wrapper.GetRBannerId)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/tag", wrapper.GetTag)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/tag", wrapper.PostTag)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/tag/{tag_id}", wrapper.DeleteTagTagId)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/tag/{tag_id}", wrapper.GetTagTagId)
+	})
+	r.Group(func(r chi.Router) {
+		r.Patch(options.BaseURL+"/tag/{tag_id}", wrapper.PatchTagTagId)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/user_banner", wrapper.GetUserBanner)
 	})
@@ -5,24 +5,33 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"math"
 	"net/http"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/KretovDmitry/avito-tech/internal/config"
 	"github.com/KretovDmitry/avito-tech/internal/user"
 	"github.com/KretovDmitry/avito-tech/pkg/log"
+	"github.com/google/uuid"
 )
 
 // Banner service implementation.
 type BannerService struct {
 	repo       Repository
 	logger     log.Logger
-	deleteChan chan int
+	deleteChan chan struct{}
+	eventChan  chan BannerEvent
 	wg         *sync.WaitGroup
 	done       chan struct{}
 	config     *config.Config
+
+	// droppedEvents counts the events dropped since it was last logged
+	droppedEvents atomic.Int64
 }
 
 // Make sure we conform to ServerInterface
@@ -38,17 +47,34 @@ func NewService(repo Repository, logger log.Logger, config *config.Config) (*Ban
 	service := &BannerService{
 		repo:       repo,
 		logger:     logger,
-		deleteChan: make(chan int, config.BannerBufferLength),
+		deleteChan: make(chan struct{}, 1),
+		eventChan:  make(chan BannerEvent, config.EventBufferLength),
 		wg:         &sync.WaitGroup{},
 		done:       make(chan struct{}),
 		config:     config,
 	}
 
-	service.wg.Add(1)
+	service.wg.Add(5)
 	go func() {
 		defer service.wg.Done()
 		service.flushDelete()
 	}()
+	go func() {
+		defer service.wg.Done()
+		service.flushEvents()
+	}()
+	go func() {
+		defer service.wg.Done()
+		service.purgeDeleted()
+	}()
+	go func() {
+		defer service.wg.Done()
+		service.listenInvalidations()
+	}()
+	go func() {
+		defer service.wg.Done()
+		service.flushInvalidations()
+	}()
 
 	return service, nil
 }
@@ -56,13 +82,43 @@ func NewService(repo Repository, logger log.Logger, config *config.Config) (*Ban
 // ErrorHandlerFunc wraps sending of an error in the Error format, and
 // handling the failure to marshal that.
 func ErrorHandlerFunc(w http.ResponseWriter, r *http.Request, err error) {
-	bannerError := Error{Error: err.Error()}
+	var bannerError interface{} = Error{Error: err.Error()}
 	var code int
-	switch err.(type) {
+	switch e := err.(type) {
+	case *InvalidContentError:
+		bannerError = ValidationError{Error: e.Error(), Fields: e.Fields}
+		code = http.StatusBadRequest
+	case *ConflictingBannersError:
+		bannerError = ConflictError{Error: e.Error(), BannerIds: e.BannerIDs}
+		code = http.StatusConflict
+	case *InvalidRowsError:
+		bannerError = ImportError{Error: e.Error(), Rows: e.Rows}
+		code = http.StatusBadRequest
+	case *ConflictingRowsError:
+		bannerError = ImportError{Error: e.Error(), Rows: e.Rows}
+		code = http.StatusConflict
+	case *RunningExperimentError, *NotDeletedError, *AlreadyExistsError, *InUseError,
+		*StaleManifestPlanError:
+		code = http.StatusConflict
 	case *RequiredParamError, *RequiredHeaderError,
 		*InvalidParamFormatError, *TooManyValuesForParamError,
-		*InvalidTypeError:
+		*InvalidTypeError, *InvalidSchemaError, *InvalidScheduleError,
+		*InvalidExperimentError, *InvalidFallbackError, *InvalidLocaleError,
+		*InvalidFrequencyCapError, *InvalidFileError, *InvalidColumnError,
+		*MissingFieldError, *InvalidFeatureError, *InvalidTagError:
 		code = http.StatusBadRequest
+	case *SelfReviewError, *ManifestApplyDisabledError:
+		code = http.StatusForbidden
+	case *ImportTooLargeError:
+		code = http.StatusRequestEntityTooLarge
+	case *DraftStateError:
+		code = http.StatusConflict
+	case *TooManyBannersError:
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(e.RetryAfter.Seconds()))))
+		code = http.StatusTooManyRequests
+	case *DeleteQueueFullError:
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(e.RetryAfter.Seconds()))))
+		code = http.StatusServiceUnavailable
 	default:
 		code = http.StatusInternalServerError
 	}
@@ -95,56 +151,22 @@ func (s *BannerService) Stop() {
 	}
 }
 
-func (s *BannerService) flushDelete() {
-	ticker := time.NewTicker(10 * time.Second)
-	ids := make([]int, 0, s.config.BannerBufferLength)
-
-	for {
-		select {
-		case id := <-s.deleteChan:
-			ids = append(ids, id)
-
-		case <-s.done:
-			if len(ids) == 0 {
-				return
-			}
-			_ = s.flush(ids...)
-			return
-
-		case <-ticker.C:
-			if len(ids) == 0 {
-				continue
-			}
-			if err := s.flush(ids...); err != nil {
-				continue
-			}
-			// reset buffer only when flush succeeded
-			ids = ids[:0:s.config.BannerBufferLength]
-		}
-	}
-}
-
-func (s *BannerService) flush(bannerIDs ...int) error {
-	if len(bannerIDs) == 0 {
-		return nil
-	}
-
-	err := s.repo.DeleteBannersByID(context.TODO(), bannerIDs...)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
 type GetBannerResponse struct {
-	BannerID  int       `json:"banner_id"`
-	FeatureID int       `json:"feature_id"`
-	TagIDs    []int     `json:"tag_ids"`
-	Content   Banner    `json:"content"`
-	IsActive  bool      `json:"is_active"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	BannerID         int             `json:"banner_id"`
+	FeatureID        int             `json:"feature_id"`
+	FeatureName      string          `json:"feature_name"`
+	TagIDs           []int           `json:"tag_ids"`
+	TagNames         []string        `json:"tag_names"`
+	Content          json.RawMessage `json:"content"`
+	LocalizedContent json.RawMessage `json:"localized_content"`
+	IsActive         bool            `json:"is_active"`
+	IsDeleted        bool            `json:"is_deleted"`
+	Priority         int             `json:"priority"`
+	FrequencyCap     *int            `json:"frequency_cap"`
+	ActiveFrom       *time.Time      `json:"active_from"`
+	ActiveUntil      *time.Time      `json:"active_until"`
+	CreatedAt        time.Time       `json:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at"`
 }
 
 // Получение всех баннеров c фильтрацией по фиче и/или тегу
@@ -161,182 +183,90 @@ func (s *BannerService) GetBanner(w http.ResponseWriter, r *http.Request, params
 		return
 	}
 
-	response := make([]GetBannerResponse, 0)
-
-	// Everywhere where no results are returned from the database,
-	// we return empty slice with status OK.
+	// Where no banners are found, we return empty slice with status OK.
 	// The status not found is not required by the specification.
-	switch {
-	// nothing is provided, return empty slice
-	case params.FeatureId == nil && params.TagId == nil:
-		break
-
-	// only feature id is provided
-	case params.FeatureId != nil && params.TagId == nil &&
-		params.Limit == nil && params.Offset == nil:
-		res, err := s.repo.GetBannersByFeature(r.Context(), params)
-		if err != nil {
-			if err == sql.ErrNoRows {
-				break
-			}
-			ErrorHandlerFunc(w, r, err)
-			return
-		}
-		response = res
+	response, next, err := s.listBanners(r.Context(), params)
+	if err != nil {
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
 
-	// feature id + limit
-	case params.FeatureId != nil && params.Limit != nil &&
-		params.TagId == nil && params.Offset == nil:
-		res, err := s.repo.GetBannersByFeatureWithLimit(r.Context(), params)
-		if err != nil {
-			if err == sql.ErrNoRows {
-				break
-			}
-			ErrorHandlerFunc(w, r, err)
-			return
-		}
-		response = res
+	if err = s.nameBanners(r.Context(), response); err != nil {
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
 
-	// feature id + offset
-	case params.FeatureId != nil && params.Offset != nil &&
-		params.TagId == nil && params.Limit == nil:
-		res, err := s.repo.GetBannersByFeatureWithOffset(r.Context(), params)
+	if params.WithTotal != nil && *params.WithTotal {
+		total, err := s.countBanners(r.Context(), params)
 		if err != nil {
-			if err == sql.ErrNoRows {
-				break
-			}
 			ErrorHandlerFunc(w, r, err)
 			return
 		}
-		response = res
+		w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	}
 
-	// feature id + limit + offset
-	case params.FeatureId != nil && params.Limit != nil &&
-		params.Offset != nil && params.TagId == nil:
-		res, err := s.repo.GetBannersByFeatureWithLimitOffset(r.Context(), params)
-		if err != nil {
-			if err == sql.ErrNoRows {
-				break
-			}
-			ErrorHandlerFunc(w, r, err)
-			return
-		}
-		response = res
+	if next != "" {
+		w.Header().Set("X-Next-Cursor", next)
+	}
 
-	// only tag id is provided
-	case params.TagId != nil && params.FeatureId == nil &&
-		params.Limit == nil && params.Offset == nil:
-		res, err := s.repo.GetBannersByTag(r.Context(), params)
-		if err != nil {
-			if err == sql.ErrNoRows {
-				break
-			}
-			ErrorHandlerFunc(w, r, err)
-			return
-		}
-		response = res
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		ErrorHandlerFunc(w, r, err)
+	}
+}
 
-	// tag id + limit
-	case params.TagId != nil && params.FeatureId == nil &&
-		params.Limit != nil && params.Offset == nil:
-		res, err := s.repo.GetBannersByTagWithLimit(r.Context(), params)
-		if err != nil {
-			if err == sql.ErrNoRows {
-				break
-			}
-			ErrorHandlerFunc(w, r, err)
-			return
-		}
-		response = res
+type PostBannerResponse struct {
+	BannerID int `json:"banner_id"`
+}
 
-	// tag id + offset
-	case params.TagId != nil && params.FeatureId == nil &&
-		params.Limit == nil && params.Offset != nil:
-		res, err := s.repo.GetBannersByTagWithOffset(r.Context(), params)
-		if err != nil {
-			if err == sql.ErrNoRows {
-				break
-			}
-			ErrorHandlerFunc(w, r, err)
-			return
-		}
-		response = res
+type PostDraftResponse struct {
+	DraftID int `json:"draft_id"`
+}
 
-	// tag id + limit + offset
-	case params.TagId != nil && params.FeatureId == nil &&
-		params.Limit != nil && params.Offset != nil:
-		res, err := s.repo.GetBannersByTagWithLimitOffset(r.Context(), params)
-		if err != nil {
-			if err == sql.ErrNoRows {
-				break
-			}
-			ErrorHandlerFunc(w, r, err)
-			return
-		}
-		response = res
+// Выгрузка баннеров c фильтрацией по фиче и/или тегу
+// (GET /banner/export)
+func (s *BannerService) GetBannerExport(w http.ResponseWriter, r *http.Request, params GetBannerExportParams) {
+	u, found := user.FromContext(r.Context())
+	if !found {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
 
-	// feature + tag
-	case params.FeatureId != nil && params.TagId != nil &&
-		params.Limit == nil && params.Offset == nil:
-		res, err := s.repo.GetBannersByFeatureTag(r.Context(), params)
-		if err != nil {
-			if err == sql.ErrNoRows {
-				break
-			}
-			ErrorHandlerFunc(w, r, err)
-			return
-		}
-		response = res
+	if u.Role != "ADMIN" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
 
-	// feature + tag + limit
-	case params.FeatureId != nil && params.TagId != nil &&
-		params.Limit != nil && params.Offset == nil:
-		res, err := s.repo.GetBannersByFeatureTagWithLimit(r.Context(), params)
-		if err != nil {
-			if err == sql.ErrNoRows {
-				break
-			}
-			ErrorHandlerFunc(w, r, err)
-			return
-		}
-		response = res
+	format := GetBannerExportParamsFormatNdjson
+	if params.Format != nil {
+		format = *params.Format
+	}
 
-	// feature + tag + offset
-	case params.FeatureId != nil && params.TagId != nil &&
-		params.Limit == nil && params.Offset != nil:
-		res, err := s.repo.GetBannersByFeatureTagWithOffset(r.Context(), params)
-		if err != nil {
-			if err == sql.ErrNoRows {
-				break
-			}
-			ErrorHandlerFunc(w, r, err)
-			return
+	export, err := newExportWriter(w, format)
+	if err != nil {
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	write := func(page []GetBannerResponse) error {
+		if err := s.nameBanners(r.Context(), page); err != nil {
+			return err
 		}
-		response = res
+		return export.write(page)
+	}
 
-	// feature + tag + limit + offset
-	case params.FeatureId != nil && params.TagId != nil &&
-		params.Limit != nil && params.Offset != nil:
-		res, err := s.repo.GetBannersByFeatureTagWithLimitOffset(r.Context(), params)
-		if err != nil {
-			if err == sql.ErrNoRows {
-				break
-			}
+	if err = s.repo.ExportBanners(r.Context(), params, write); err == nil {
+		err = export.finish()
+	}
+	if err != nil {
+		// the status is sent along with the first page
+		if !export.started {
 			ErrorHandlerFunc(w, r, err)
 			return
 		}
-		response = res
-	}
-
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		ErrorHandlerFunc(w, r, err)
+		s.logger.Errorf("banner export interrupted: %v", err)
 	}
 }
 
-type PostBannerResponse struct {
-	BannerID int `json:"banner_id"`
-}
-
 // Создание нового баннера
 // (POST /banner)
 func (s *BannerService) PostBanner(w http.ResponseWriter, r *http.Request, params PostBannerParams) {
@@ -351,88 +281,89 @@ func (s *BannerService) PostBanner(w http.ResponseWriter, r *http.Request, param
 		return
 	}
 
-	data := new(PostBannerJSONBody)
+	var body json.RawMessage
 	defer r.Body.Close()
-	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		ErrorHandlerFunc(w, r, err)
 		return
 	}
 
-	if data.Content == nil || data.FeatureId == nil ||
-		data.IsActive == nil || data.TagIds == nil {
-		w.WriteHeader(http.StatusBadRequest)
+	if _, err := s.prepareBanner(r.Context(), body); err != nil {
+		ErrorHandlerFunc(w, r, err)
 		return
 	}
 
-	response, err := s.repo.CreateBanner(r.Context(), *data)
+	// the banner is created once another admin approves the draft
+	id, err := s.repo.CreateDraft(r.Context(), nil, body, u.ID)
 	if err != nil {
 		ErrorHandlerFunc(w, r, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusCreated)
-	if err = json.NewEncoder(w).Encode(response); err != nil {
+	if err = json.NewEncoder(w).Encode(PostDraftResponse{DraftID: id}); err != nil {
 		ErrorHandlerFunc(w, r, err)
 	}
 }
 
-// Удаление баннеров по id
-// (DELETE /banner)
-func (s *BannerService) DeleteBanner(w http.ResponseWriter, r *http.Request, params DeleteBannerParams) {
-	u, found := user.FromContext(r.Context())
-	if !found {
-		w.WriteHeader(http.StatusUnauthorized)
-		return
+// prepareBanner decodes and validates a new banner.
+func (s *BannerService) prepareBanner(ctx context.Context, body json.RawMessage) (*PostBannerJSONBody, error) {
+	data := new(PostBannerJSONBody)
+	if err := json.Unmarshal(body, data); err != nil {
+		return nil, err
 	}
 
-	if u.Role != "ADMIN" {
-		w.WriteHeader(http.StatusForbidden)
-		return
+	switch {
+	case data.Content == nil:
+		return nil, &MissingFieldError{Field: "content"}
+	case data.FeatureId == nil:
+		return nil, &MissingFieldError{Field: "feature_id"}
+	case data.IsActive == nil:
+		return nil, &MissingFieldError{Field: "is_active"}
+	case data.TagIds == nil:
+		return nil, &MissingFieldError{Field: "tag_ids"}
 	}
 
-	ids := make(DeleteBannerJSONBody, 0)
-	if err := json.NewDecoder(r.Body).Decode(&ids); err != nil {
-		ErrorHandlerFunc(w, r, err)
-		return
+	if err := checkSchedule(data.ActiveFrom, data.ActiveUntil); err != nil {
+		return nil, err
 	}
 
-	for _, id := range ids {
-		s.deleteChan <- id
+	if err := checkFrequencyCap(data.FrequencyCap); err != nil {
+		return nil, err
 	}
 
-	w.WriteHeader(http.StatusAccepted)
-}
+	if err := s.checkFeature(ctx, *data.FeatureId); err != nil {
+		return nil, err
+	}
 
-// Удаление баннера по идентификатору
-// (DELETE /banner/{id})
-func (s *BannerService) DeleteBannerId(w http.ResponseWriter, r *http.Request, id int, params DeleteBannerIdParams) {
-	u, found := user.FromContext(r.Context())
-	if !found {
-		w.WriteHeader(http.StatusUnauthorized)
-		return
+	if err := s.checkTags(ctx, *data.TagIds); err != nil {
+		return nil, err
 	}
 
-	if u.Role != "ADMIN" {
-		w.WriteHeader(http.StatusForbidden)
-		return
+	if err := s.checkContent(ctx, *data.FeatureId, *data.Content); err != nil {
+		return nil, err
 	}
 
-	err := s.repo.DeleteBannerByID(r.Context(), id)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			w.WriteHeader(http.StatusNotFound)
-			return
+	if data.LocalizedContent != nil {
+		localized, err := s.checkLocalizedContent(ctx, *data.FeatureId, *data.LocalizedContent)
+		if err != nil {
+			return nil, err
 		}
-		ErrorHandlerFunc(w, r, err)
-		return
+		data.LocalizedContent = &localized
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	return data, nil
 }
 
-// Обновление содержимого баннера
-// (PATCH /banner/{id})
-func (s *BannerService) PatchBannerId(w http.ResponseWriter, r *http.Request, id int, params PatchBannerIdParams) {
+type PostBannerImportResponse struct {
+	DraftIDs []int `json:"draft_ids"`
+	Rows     int   `json:"rows"`
+	DryRun   bool  `json:"dry_run"`
+}
+
+// Загрузка баннеров из файла
+// (POST /banner/import)
+func (s *BannerService) PostBannerImport(w http.ResponseWriter, r *http.Request, params PostBannerImportParams) {
 	u, found := user.FromContext(r.Context())
 	if !found {
 		w.WriteHeader(http.StatusUnauthorized)
@@ -444,64 +375,1802 @@ func (s *BannerService) PatchBannerId(w http.ResponseWriter, r *http.Request, id
 		return
 	}
 
-	data := new(PatchBannerIdJSONBody)
+	format := PostBannerImportParamsFormatNdjson
+	if params.Format != nil {
+		format = *params.Format
+	}
+
+	// the whole file is held in memory until the drafts are created
+	r.Body = http.MaxBytesReader(w, r.Body, s.config.ImportMaxBytes)
 	defer r.Body.Close()
-	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+	rows, err := newRowReader(format, r.Body)
+	if err != nil {
 		ErrorHandlerFunc(w, r, err)
 		return
 	}
 
-	if data.Content != nil {
-		if err := s.repo.UpdateBannerByID(r.Context(), id, data.Content); err != nil {
-			ErrorHandlerFunc(w, r, err)
-			return
+	// every row is checked, so that all the mistakes are reported at once
+	banners := make([]PostBannerJSONBody, 0)
+	invalid := make([]RowError, 0)
+	for n := 1; ; n++ {
+		body, err := rows.next()
+		if err == io.EOF {
+			break
 		}
-	}
 
-	if data.IsActive != nil {
-		if err := s.repo.UpdateIsActiveByID(r.Context(), id, *data.IsActive); err != nil {
-			ErrorHandlerFunc(w, r, err)
+		if n > s.config.ImportMaxRows {
+			ErrorHandlerFunc(w, r, &ImportTooLargeError{
+				Reason: fmt.Sprintf("more than %d rows", s.config.ImportMaxRows),
+			})
 			return
 		}
-	}
 
-	if data.FeatureId != nil {
-		if err := s.repo.UpdateFeatureByID(r.Context(), id, *data.FeatureId); err != nil {
-			ErrorHandlerFunc(w, r, err)
-			return
+		if err == nil {
+			var data *PostBannerJSONBody
+			if data, err = s.prepareBanner(r.Context(), body); err == nil {
+				banners = append(banners, *data)
+				continue
+			}
 		}
-	}
 
-	if data.TagIds != nil {
-		if err := s.repo.UpdateBannerTagByID(r.Context(), id, data.TagIds); err != nil {
+		if !isRowError(err) {
 			ErrorHandlerFunc(w, r, err)
 			return
 		}
+		invalid = append(invalid, newRowError(n, err))
 	}
 
-	w.WriteHeader(http.StatusOK)
+	if len(invalid) > 0 {
+		ErrorHandlerFunc(w, r, &InvalidRowsError{Rows: invalid})
+		return
+	}
+
+	if len(banners) == 0 {
+		ErrorHandlerFunc(w, r, &InvalidFileError{Reason: "no rows"})
+		return
+	}
+
+	dryRun := params.DryRun != nil && *params.DryRun
+
+	// the banners are created once other admins approve the drafts
+	ids, err := s.repo.ImportBanners(r.Context(), banners, u.ID, dryRun)
+	if err != nil {
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	response := PostBannerImportResponse{
+		DraftIDs: ids,
+		Rows:     len(banners),
+		DryRun:   dryRun,
+	}
+	if err = json.NewEncoder(w).Encode(response); err != nil {
+		ErrorHandlerFunc(w, r, err)
+	}
 }
 
-// Получение баннера для пользователя
-// (GET /user_banner)
-func (s *BannerService) GetUserBanner(w http.ResponseWriter, r *http.Request, params GetUserBannerParams) {
-	_, found := user.FromContext(r.Context())
+type PostBannerPurgeResponse struct {
+	PurgeSummary
+	DryRun bool `json:"dry_run"`
+}
+
+// Окончательное удаление давно удаленных баннеров и осиротевших тегов
+// (POST /banner/purge)
+func (s *BannerService) PostBannerPurge(w http.ResponseWriter, r *http.Request, params PostBannerPurgeParams) {
+	u, found := user.FromContext(r.Context())
 	if !found {
 		w.WriteHeader(http.StatusUnauthorized)
 		return
 	}
 
-	banner, err := s.repo.GetActiveBannerByFeatureTag(r.Context(), params)
+	if u.Role != "ADMIN" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	dryRun := params.DryRun != nil && *params.DryRun
+
+	start := time.Now()
+	summary, err := s.purge(r.Context(), dryRun)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			w.WriteHeader(http.StatusNotFound)
-			return
-		}
 		ErrorHandlerFunc(w, r, err)
 		return
 	}
 
-	if err = json.NewEncoder(w).Encode(banner); err != nil {
+	if !dryRun {
+		s.logger.Infof("purge by user %d: %d banners, %d tags, %d revisions deleted in %s",
+			u.ID, summary.Banners, summary.Tags, summary.Revisions, time.Since(start))
+	}
+
+	response := PostBannerPurgeResponse{
+		PurgeSummary: summary,
+		DryRun:       dryRun,
+	}
+	if err = json.NewEncoder(w).Encode(response); err != nil {
+		ErrorHandlerFunc(w, r, err)
+	}
+}
+
+// Удаление баннеров по id, фиче или тегу
+// (DELETE /banner)
+func (s *BannerService) DeleteBanner(w http.ResponseWriter, r *http.Request, params DeleteBannerParams) {
+	u, found := user.FromContext(r.Context())
+	if !found {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if u.Role != "ADMIN" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	// banners are deleted either by id or by the feature and/or the tag
+	filtered := params.FeatureId != nil || params.TagId != nil
+
+	ids := make(DeleteBannerJSONBody, 0)
+	err := json.NewDecoder(r.Body).Decode(&ids)
+	if err != nil && !(filtered && err == io.EOF) {
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+	// the batch is accepted or rejected as a whole
+	if len(ids) > s.config.DeleteMaxBatchSize {
+		ErrorHandlerFunc(w, r, &TooManyBannersError{
+			Limit:      s.config.DeleteMaxBatchSize,
+			RetryAfter: s.config.DeleteFlushInterval,
+		})
+		return
+	}
+	if filtered && len(ids) > 0 {
+		ErrorHandlerFunc(w, r, &InvalidParamFormatError{
+			ParamName: "body",
+			Err:       errors.New("banner ids can't be combined with feature_id or tag_id"),
+		})
+		return
+	}
+
+	// the job is stored before it is acknowledged
+	job, err := s.repo.CreateDeleteJob(r.Context(), ids, params.FeatureId, params.TagId)
+	if err != nil {
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	// wake the worker up unless it is already woken
+	select {
+	case s.deleteChan <- struct{}{}:
+	default:
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(DeleteBannerResponse{JobID: job.JobId}); err != nil {
+		ErrorHandlerFunc(w, r, err)
+	}
+}
+
+type DeleteBannerResponse struct {
+	JobID string `json:"job_id"`
+}
+
+// Состояние задачи асинхронного удаления баннеров
+// (GET /banner/delete_jobs/{id})
+func (s *BannerService) GetBannerDeleteJobsId(w http.ResponseWriter, r *http.Request, id string, params GetBannerDeleteJobsIdParams) {
+	u, found := user.FromContext(r.Context())
+	if !found {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if u.Role != "ADMIN" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	jobID, err := uuid.Parse(id)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	job, err := s.repo.GetDeleteJob(r.Context(), jobID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(job); err != nil {
+		ErrorHandlerFunc(w, r, err)
+	}
+}
+
+// Удаление баннера по идентификатору
+// (DELETE /banner/{id})
+func (s *BannerService) DeleteBannerId(w http.ResponseWriter, r *http.Request, id int, params DeleteBannerIdParams) {
+	u, found := user.FromContext(r.Context())
+	if !found {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if u.Role != "ADMIN" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	err := s.repo.DeleteBannerByID(r.Context(), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Обновление содержимого баннера
+// (PATCH /banner/{id})
+func (s *BannerService) PatchBannerId(w http.ResponseWriter, r *http.Request, id int, params PatchBannerIdParams) {
+	u, found := user.FromContext(r.Context())
+	if !found {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if u.Role != "ADMIN" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	var body json.RawMessage
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	if _, err := s.preparePatch(r.Context(), id, body); err != nil {
+		if err == sql.ErrNoRows {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	// the change is applied once another admin approves the draft
+	draftID, err := s.repo.CreateDraft(r.Context(), &id, body, u.ID)
+	if err != nil {
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	if err = json.NewEncoder(w).Encode(PostDraftResponse{DraftID: draftID}); err != nil {
+		ErrorHandlerFunc(w, r, err)
+	}
+}
+
+// BannerPatch is a validated change of the banner.
+type BannerPatch struct {
+	data *PatchBannerIdJSONBody
+
+	scheduled   bool
+	activeFrom  *time.Time
+	activeUntil *time.Time
+
+	localizedSet bool
+	localized    map[string]map[string]interface{}
+
+	frequencyCapSet bool
+}
+
+// preparePatch decodes the change of the banner and validates it
+// against the current state of the banner.
+func (s *BannerService) preparePatch(ctx context.Context, id int, body json.RawMessage) (*BannerPatch, error) {
+	data := new(PatchBannerIdJSONBody)
+	if err := json.Unmarshal(body, data); err != nil {
+		return nil, err
+	}
+
+	// explicit null clears the schedule, so presence of the fields matters
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, err
+	}
+	_, fromSet := fields["active_from"]
+	_, untilSet := fields["active_until"]
+	// explicit null drops every localized content
+	_, localizedSet := fields["localized_content"]
+	// explicit null lifts the frequency cap
+	_, frequencyCapSet := fields["frequency_cap"]
+
+	if err := checkFrequencyCap(data.FrequencyCap); err != nil {
+		return nil, err
+	}
+
+	p := &BannerPatch{
+		data:            data,
+		scheduled:       fromSet || untilSet,
+		localizedSet:    localizedSet,
+		frequencyCapSet: frequencyCapSet,
+	}
+
+	banner, err := s.repo.GetBannerByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.scheduled {
+		p.activeFrom, p.activeUntil = timePtr(banner.ActiveFrom), timePtr(banner.ActiveUntil)
+		if fromSet {
+			p.activeFrom = data.ActiveFrom
+		}
+		if untilSet {
+			p.activeUntil = data.ActiveUntil
+		}
+
+		if err := checkSchedule(p.activeFrom, p.activeUntil); err != nil {
+			return nil, err
+		}
+	}
+
+	featureID := banner.FeatureID
+	if data.FeatureId != nil {
+		featureID = *data.FeatureId
+
+		if err := s.checkFeature(ctx, featureID); err != nil {
+			return nil, err
+		}
+	}
+
+	if data.TagIds != nil {
+		if err := s.checkTags(ctx, *data.TagIds); err != nil {
+			return nil, err
+		}
+	}
+
+	// content has to match the schema of the feature it ends up in
+	if data.Content != nil || data.FeatureId != nil {
+		content := data.Content
+		if content == nil {
+			content = new(map[string]interface{})
+			if err := json.Unmarshal(banner.Content, content); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := s.checkContent(ctx, featureID, *content); err != nil {
+			return nil, err
+		}
+	}
+
+	// so does every localized content
+	if localizedSet || data.FeatureId != nil {
+		if localizedSet {
+			if data.LocalizedContent != nil {
+				p.localized = *data.LocalizedContent
+			}
+		} else if err := json.Unmarshal(banner.LocalizedContent, &p.localized); err != nil {
+			return nil, err
+		}
+
+		p.localized, err = s.checkLocalizedContent(ctx, featureID, p.localized)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return p, nil
+}
+
+type GetBannerVersionResponse struct {
+	Version          int             `json:"version"`
+	FeatureID        int             `json:"feature_id"`
+	TagIDs           []int           `json:"tag_ids"`
+	Content          json.RawMessage `json:"content"`
+	LocalizedContent json.RawMessage `json:"localized_content"`
+	IsActive         bool            `json:"is_active"`
+	Priority         int             `json:"priority"`
+	FrequencyCap     *int            `json:"frequency_cap"`
+	ActiveFrom       *time.Time      `json:"active_from"`
+	ActiveUntil      *time.Time      `json:"active_until"`
+	CreatedAt        time.Time       `json:"created_at"`
+}
+
+// Восстановление удалённого баннера
+// (POST /banner/{id}/restore)
+func (s *BannerService) PostBannerIdRestore(w http.ResponseWriter, r *http.Request, id int, params PostBannerIdRestoreParams) {
+	u, found := user.FromContext(r.Context())
+	if !found {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if u.Role != "ADMIN" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	err := s.repo.RestoreBanner(r.Context(), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// Получение истории версий баннера
+// (GET /banner/{id}/versions)
+func (s *BannerService) GetBannerIdVersions(w http.ResponseWriter, r *http.Request, id int, params GetBannerIdVersionsParams) {
+	u, found := user.FromContext(r.Context())
+	if !found {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if u.Role != "ADMIN" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	response, err := s.repo.GetBannerRevisions(r.Context(), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	if err = json.NewEncoder(w).Encode(response); err != nil {
+		ErrorHandlerFunc(w, r, err)
+	}
+}
+
+// Откат баннера к выбранной версии
+// (POST /banner/{id}/versions/{version}/rollback)
+func (s *BannerService) PostBannerIdVersionsVersionRollback(w http.ResponseWriter, r *http.Request, id int, version int, params PostBannerIdVersionsVersionRollbackParams) {
+	u, found := user.FromContext(r.Context())
+	if !found {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if u.Role != "ADMIN" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	err := s.checkRevision(r.Context(), id, version)
+	if err == nil {
+		err = s.repo.RollbackBanner(r.Context(), id, version)
+	}
+	if err != nil {
+		if err == sql.ErrNoRows {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// checkRevision validates the content of the revision the banner is rolled
+// back to against the current schema of its feature, which may have changed
+// since the revision was made.
+func (s *BannerService) checkRevision(ctx context.Context, id int, version int) error {
+	revisions, err := s.repo.GetBannerRevisions(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	for _, rev := range revisions {
+		if rev.Version != version {
+			continue
+		}
+
+		content := make(map[string]interface{})
+		if err := json.Unmarshal(rev.Content, &content); err != nil {
+			return err
+		}
+		if err := s.checkContent(ctx, rev.FeatureID, content); err != nil {
+			return err
+		}
+
+		localized := make(map[string]map[string]interface{})
+		if len(rev.LocalizedContent) > 0 {
+			if err := json.Unmarshal(rev.LocalizedContent, &localized); err != nil {
+				return err
+			}
+		}
+		_, err := s.checkLocalizedContent(ctx, rev.FeatureID, localized)
+		return err
+	}
+
+	return sql.ErrNoRows
+}
+
+// checkContent validates banner content against the feature schema, if any.
+func (s *BannerService) checkContent(ctx context.Context, featureID int, content map[string]interface{}) error {
+	schema, err := s.repo.GetFeatureSchema(ctx, featureID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+
+	return validateContent(schema, content)
+}
+
+// checkFeature makes sure the feature is registered and open for new banners.
+func (s *BannerService) checkFeature(ctx context.Context, featureID int) error {
+	features, err := s.repo.GetFeaturesByID(ctx, featureID)
+	if err != nil {
+		return err
+	}
+
+	feature, ok := features[featureID]
+	if !ok {
+		return &InvalidFeatureError{FeatureID: featureID, Reason: "not registered"}
+	}
+	if feature.IsArchived {
+		return &InvalidFeatureError{FeatureID: featureID, Reason: "archived"}
+	}
+
+	return nil
+}
+
+// checkTags makes sure every tag is registered and open for new banners.
+func (s *BannerService) checkTags(ctx context.Context, tagIDs []int) error {
+	tags, err := s.repo.GetTagDefinitionsByID(ctx, tagIDs...)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range tagIDs {
+		tag, ok := tags[id]
+		if !ok {
+			return &InvalidTagError{TagID: id, Reason: "not registered"}
+		}
+		if tag.IsArchived {
+			return &InvalidTagError{TagID: id, Reason: "archived"}
+		}
+	}
+
+	return nil
+}
+
+// nameBanners fills in the names of the features and tags of the banners.
+// Mock deletion tags are never registered and stay unnamed.
+func (s *BannerService) nameBanners(ctx context.Context, banners []GetBannerResponse) error {
+	if len(banners) == 0 {
+		return nil
+	}
+
+	featureIDs := make([]int, 0, len(banners))
+	tagIDs := make([]int, 0, len(banners))
+	for _, b := range banners {
+		featureIDs = append(featureIDs, b.FeatureID)
+		tagIDs = append(tagIDs, b.TagIDs...)
+	}
+
+	features, err := s.repo.GetFeaturesByID(ctx, uniqueTags(featureIDs)...)
+	if err != nil {
+		return err
+	}
+
+	tags, err := s.repo.GetTagDefinitionsByID(ctx, uniqueTags(tagIDs)...)
+	if err != nil {
+		return err
+	}
+
+	for i := range banners {
+		banners[i].FeatureName = features[banners[i].FeatureID].Name
+		banners[i].TagNames = make([]string, 0, len(banners[i].TagIDs))
+		for _, id := range banners[i].TagIDs {
+			banners[i].TagNames = append(banners[i].TagNames, tags[id].Name)
+		}
+	}
+
+	return nil
+}
+
+// checkLocalizedContent validates content of every locale against the feature
+// schema and returns it keyed by canonical locales.
+func (s *BannerService) checkLocalizedContent(ctx context.Context, featureID int, content map[string]map[string]interface{}) (map[string]map[string]interface{}, error) {
+	localized, err := normalizeLocales(content)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range localized {
+		if err := s.checkContent(ctx, featureID, c); err != nil {
+			return nil, err
+		}
+	}
+
+	return localized, nil
+}
+
+// checkSchedule makes sure the banner is shown for a non-empty period of time.
+func checkSchedule(activeFrom, activeUntil *time.Time) error {
+	if activeFrom != nil && activeUntil != nil && !activeFrom.Before(*activeUntil) {
+		return &InvalidScheduleError{}
+	}
+
+	return nil
+}
+
+// checkFrequencyCap makes sure the cap, if any, lets the banner be shown at all.
+func checkFrequencyCap(frequencyCap *int) error {
+	if frequencyCap != nil && *frequencyCap < 1 {
+		return &InvalidFrequencyCapError{FrequencyCap: *frequencyCap}
+	}
+
+	return nil
+}
+
+type GetFeatureResponse struct {
+	FeatureID   int       `json:"feature_id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	IsArchived  bool      `json:"is_archived"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Получение каталога фич
+// (GET /feature)
+func (s *BannerService) GetFeature(w http.ResponseWriter, r *http.Request, params GetFeatureParams) {
+	u, found := user.FromContext(r.Context())
+	if !found {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if u.Role != "ADMIN" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	includeArchived := params.IncludeArchived != nil && *params.IncludeArchived
+
+	response, err := s.repo.GetFeatures(r.Context(), includeArchived)
+	if err != nil {
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	if err = json.NewEncoder(w).Encode(response); err != nil {
+		ErrorHandlerFunc(w, r, err)
+	}
+}
+
+// Регистрация фичи
+// (POST /feature)
+func (s *BannerService) PostFeature(w http.ResponseWriter, r *http.Request, params PostFeatureParams) {
+	u, found := user.FromContext(r.Context())
+	if !found {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if u.Role != "ADMIN" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	data := new(PostFeatureJSONBody)
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(data); err != nil {
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	switch {
+	case data.FeatureId == nil:
+		ErrorHandlerFunc(w, r, &MissingFieldError{Field: "feature_id"})
+		return
+	case data.Name == nil || *data.Name == "":
+		ErrorHandlerFunc(w, r, &MissingFieldError{Field: "name"})
+		return
+	}
+
+	response, err := s.repo.CreateFeature(r.Context(), *data)
+	if err != nil {
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	if err = json.NewEncoder(w).Encode(response); err != nil {
+		ErrorHandlerFunc(w, r, err)
+	}
+}
+
+// Получение фичи
+// (GET /feature/{feature_id})
+func (s *BannerService) GetFeatureFeatureId(w http.ResponseWriter, r *http.Request, featureId int, params GetFeatureFeatureIdParams) {
+	u, found := user.FromContext(r.Context())
+	if !found {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if u.Role != "ADMIN" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	response, err := s.repo.GetFeature(r.Context(), featureId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	if err = json.NewEncoder(w).Encode(response); err != nil {
+		ErrorHandlerFunc(w, r, err)
+	}
+}
+
+// Изменение фичи
+// (PATCH /feature/{feature_id})
+func (s *BannerService) PatchFeatureFeatureId(w http.ResponseWriter, r *http.Request, featureId int, params PatchFeatureFeatureIdParams) {
+	u, found := user.FromContext(r.Context())
+	if !found {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if u.Role != "ADMIN" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	data := new(PatchFeatureFeatureIdJSONBody)
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(data); err != nil {
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	if data.Name != nil && *data.Name == "" {
+		ErrorHandlerFunc(w, r, &MissingFieldError{Field: "name"})
+		return
+	}
+
+	response, err := s.repo.UpdateFeature(r.Context(), featureId, *data)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	if err = json.NewEncoder(w).Encode(response); err != nil {
+		ErrorHandlerFunc(w, r, err)
+	}
+}
+
+// Удаление фичи
+// (DELETE /feature/{feature_id})
+func (s *BannerService) DeleteFeatureFeatureId(w http.ResponseWriter, r *http.Request, featureId int, params DeleteFeatureFeatureIdParams) {
+	u, found := user.FromContext(r.Context())
+	if !found {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if u.Role != "ADMIN" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	err := s.repo.DeleteFeature(r.Context(), featureId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type GetTagResponse struct {
+	TagID       int       `json:"tag_id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	IsArchived  bool      `json:"is_archived"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Получение каталога тэгов
+// (GET /tag)
+func (s *BannerService) GetTag(w http.ResponseWriter, r *http.Request, params GetTagParams) {
+	u, found := user.FromContext(r.Context())
+	if !found {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if u.Role != "ADMIN" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	includeArchived := params.IncludeArchived != nil && *params.IncludeArchived
+
+	response, err := s.repo.GetTagDefinitions(r.Context(), includeArchived)
+	if err != nil {
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	if err = json.NewEncoder(w).Encode(response); err != nil {
+		ErrorHandlerFunc(w, r, err)
+	}
+}
+
+// Регистрация тэга
+// (POST /tag)
+func (s *BannerService) PostTag(w http.ResponseWriter, r *http.Request, params PostTagParams) {
+	u, found := user.FromContext(r.Context())
+	if !found {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if u.Role != "ADMIN" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	data := new(PostTagJSONBody)
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(data); err != nil {
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	switch {
+	case data.TagId == nil:
+		ErrorHandlerFunc(w, r, &MissingFieldError{Field: "tag_id"})
+		return
+	case *data.TagId == -1:
+		ErrorHandlerFunc(w, r, &InvalidTagError{TagID: -1, Reason: "reserved for mock deletion"})
+		return
+	case data.Name == nil || *data.Name == "":
+		ErrorHandlerFunc(w, r, &MissingFieldError{Field: "name"})
+		return
+	}
+
+	response, err := s.repo.CreateTagDefinition(r.Context(), *data)
+	if err != nil {
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	if err = json.NewEncoder(w).Encode(response); err != nil {
+		ErrorHandlerFunc(w, r, err)
+	}
+}
+
+// Получение тэга
+// (GET /tag/{tag_id})
+func (s *BannerService) GetTagTagId(w http.ResponseWriter, r *http.Request, tagId int, params GetTagTagIdParams) {
+	u, found := user.FromContext(r.Context())
+	if !found {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if u.Role != "ADMIN" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	response, err := s.repo.GetTagDefinition(r.Context(), tagId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	if err = json.NewEncoder(w).Encode(response); err != nil {
+		ErrorHandlerFunc(w, r, err)
+	}
+}
+
+// Изменение тэга
+// (PATCH /tag/{tag_id})
+func (s *BannerService) PatchTagTagId(w http.ResponseWriter, r *http.Request, tagId int, params PatchTagTagIdParams) {
+	u, found := user.FromContext(r.Context())
+	if !found {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if u.Role != "ADMIN" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	data := new(PatchTagTagIdJSONBody)
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(data); err != nil {
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	if data.Name != nil && *data.Name == "" {
+		ErrorHandlerFunc(w, r, &MissingFieldError{Field: "name"})
+		return
+	}
+
+	response, err := s.repo.UpdateTagDefinition(r.Context(), tagId, *data)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	if err = json.NewEncoder(w).Encode(response); err != nil {
+		ErrorHandlerFunc(w, r, err)
+	}
+}
+
+// Удаление тэга
+// (DELETE /tag/{tag_id})
+func (s *BannerService) DeleteTagTagId(w http.ResponseWriter, r *http.Request, tagId int, params DeleteTagTagIdParams) {
+	u, found := user.FromContext(r.Context())
+	if !found {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if u.Role != "ADMIN" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	err := s.repo.DeleteTagDefinition(r.Context(), tagId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Получение JSON-схемы содержимого баннеров фичи
+// (GET /feature/{feature_id}/schema)
+func (s *BannerService) GetFeatureFeatureIdSchema(w http.ResponseWriter, r *http.Request, featureId int, params GetFeatureFeatureIdSchemaParams) {
+	u, found := user.FromContext(r.Context())
+	if !found {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if u.Role != "ADMIN" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	schema, err := s.repo.GetFeatureSchema(r.Context(), featureId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	if err = json.NewEncoder(w).Encode(schema); err != nil {
+		ErrorHandlerFunc(w, r, err)
+	}
+}
+
+// Установка JSON-схемы содержимого баннеров фичи
+// (PUT /feature/{feature_id}/schema)
+func (s *BannerService) PutFeatureFeatureIdSchema(w http.ResponseWriter, r *http.Request, featureId int, params PutFeatureFeatureIdSchemaParams) {
+	u, found := user.FromContext(r.Context())
+	if !found {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if u.Role != "ADMIN" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	data := make(PutFeatureFeatureIdSchemaJSONBody)
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	schema, err := json.Marshal(data)
+	if err != nil {
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	// reject malformed schemas before they are applied to banners
+	if _, err = compileSchema(schema); err != nil {
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	if err = s.repo.SetFeatureSchema(r.Context(), featureId, schema); err != nil {
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+type GetFeatureFallbackResponse struct {
+	BannerID int `json:"banner_id"`
+}
+
+// Получение баннера фичи по умолчанию
+// (GET /feature/{feature_id}/fallback)
+func (s *BannerService) GetFeatureFeatureIdFallback(w http.ResponseWriter, r *http.Request, featureId int, params GetFeatureFeatureIdFallbackParams) {
+	u, found := user.FromContext(r.Context())
+	if !found {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if u.Role != "ADMIN" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	bannerID, err := s.repo.GetFeatureFallback(r.Context(), featureId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	if err = json.NewEncoder(w).Encode(GetFeatureFallbackResponse{BannerID: bannerID}); err != nil {
+		ErrorHandlerFunc(w, r, err)
+	}
+}
+
+// Назначение баннера фичи по умолчанию
+// (PUT /feature/{feature_id}/fallback)
+func (s *BannerService) PutFeatureFeatureIdFallback(w http.ResponseWriter, r *http.Request, featureId int, params PutFeatureFeatureIdFallbackParams) {
+	u, found := user.FromContext(r.Context())
+	if !found {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if u.Role != "ADMIN" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	data := new(PutFeatureFeatureIdFallbackJSONBody)
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	if err := s.repo.SetFeatureFallback(r.Context(), featureId, data.BannerId); err != nil {
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// Снятие баннера фичи по умолчанию
+// (DELETE /feature/{feature_id}/fallback)
+func (s *BannerService) DeleteFeatureFeatureIdFallback(w http.ResponseWriter, r *http.Request, featureId int, params DeleteFeatureFeatureIdFallbackParams) {
+	u, found := user.FromContext(r.Context())
+	if !found {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if u.Role != "ADMIN" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	err := s.repo.DeleteFeatureFallback(r.Context(), featureId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UserBanner is the banner resolved for the user tags.
+type UserBanner struct {
+	Banner
+	// Fallback is set when no banner matched the tags
+	// and the default banner of the feature is shown instead.
+	Fallback bool `json:"fallback"`
+	// Locale of the served content, empty if the banner content
+	// is shown as is.
+	Locale string `json:"locale"`
+}
+
+// Получение баннера для пользователя
+// (GET /user_banner)
+func (s *BannerService) GetUserBanner(w http.ResponseWriter, r *http.Request, params GetUserBannerParams) {
+	u, found := user.FromContext(r.Context())
+	if !found {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	locales, err := requestedLocales(params)
+	if err != nil {
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	variants, err := s.repo.GetExperimentVariants(r.Context(), params)
+	if err != nil {
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	var banner *UserBanner
+	if len(variants) > 0 {
+		variant := pickVariant(variants, u.ID)
+		banner, err = s.repo.GetVariantBanner(r.Context(), params, variant.BannerID)
+		if err != nil && err != sql.ErrNoRows {
+			ErrorHandlerFunc(w, r, err)
+			return
+		}
+
+		if banner != nil && s.frequencyCapped(r.Context(), banner.Banner, u.ID) {
+			banner = nil
+		}
+	}
+
+	// deleted, unscheduled or capped variants give way to the regular banner
+	if banner != nil {
+		w.Header().Set("X-Banner-Variant", strconv.Itoa(banner.ID))
+	} else {
+		// banners the user has seen enough times today
+		// are skipped in favour of the next eligible one
+		excluded := make([]int, 0)
+		for {
+			banner, err = s.repo.GetActiveBannerByFeatureTag(r.Context(), params, excluded)
+			if err != nil {
+				if err == sql.ErrNoRows {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				ErrorHandlerFunc(w, r, err)
+				return
+			}
+
+			if !s.frequencyCapped(r.Context(), banner.Banner, u.ID) {
+				break
+			}
+
+			excluded = append(excluded, banner.ID)
+		}
+
+		if banner.Fallback {
+			w.Header().Set("X-Banner-Fallback", "true")
+		}
+	}
+
+	// the content is localized once the banner is resolved, cached or not,
+	// so that the first requested locale the banner has is served
+	if err = localize(banner, localeChain(locales, s.config.LocaleFallback)); err != nil {
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+	if banner.Locale != "" {
+		w.Header().Set("Content-Language", banner.Locale)
+	}
+
+	// only the impression of the banner actually served counts against its cap
+	if params.TrackImpression != nil && *params.TrackImpression {
+		s.track(eventImpression, banner.Banner, u.ID)
+		if err = s.repo.CountImpression(r.Context(), banner.Banner, u.ID); err != nil {
+			s.logger.Errorf("count impression: %v", err)
+		}
+	}
+
+	if err = json.NewEncoder(w).Encode(banner.Content); err != nil {
+		ErrorHandlerFunc(w, r, err)
+	}
+}
+
+// frequencyCapped reports whether the user has seen the banner enough times today.
+// The cap fails open: the banner is served when the counter can not be read.
+func (s *BannerService) frequencyCapped(ctx context.Context, banner Banner, userID int) bool {
+	capped, err := s.repo.FrequencyCapped(ctx, banner, userID)
+	if err != nil {
+		s.logger.Errorf("frequency cap: %v", err)
+		return false
+	}
+
+	return capped
+}
+
+type PostExperimentResponse struct {
+	ExperimentID int `json:"experiment_id"`
+}
+
+// Создание A/B эксперимента для фичи и тэга
+// (POST /experiment)
+func (s *BannerService) PostExperiment(w http.ResponseWriter, r *http.Request, params PostExperimentParams) {
+	u, found := user.FromContext(r.Context())
+	if !found {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if u.Role != "ADMIN" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	data := new(PostExperimentJSONBody)
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	if err := checkVariants(data.Variants); err != nil {
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	if len(data.Variants) < 2 {
+		ErrorHandlerFunc(w, r, &InvalidExperimentError{Reason: "at least two variants required"})
+		return
+	}
+
+	id, err := s.repo.CreateExperiment(r.Context(), *data)
+	if err != nil {
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	if err = json.NewEncoder(w).Encode(PostExperimentResponse{ExperimentID: id}); err != nil {
+		ErrorHandlerFunc(w, r, err)
+	}
+}
+
+type GetExperimentResponse struct {
+	ExperimentID int        `json:"experiment_id"`
+	FeatureID    int        `json:"feature_id"`
+	TagID        int        `json:"tag_id"`
+	Variants     []Variant  `json:"variants"`
+	WinnerID     *int       `json:"winner_id"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	EndedAt      *time.Time `json:"ended_at"`
+}
+
+// Получение A/B эксперимента
+// (GET /experiment/{id})
+func (s *BannerService) GetExperimentId(w http.ResponseWriter, r *http.Request, id int, params GetExperimentIdParams) {
+	u, found := user.FromContext(r.Context())
+	if !found {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if u.Role != "ADMIN" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	response, err := s.repo.GetExperiment(r.Context(), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	if err = json.NewEncoder(w).Encode(response); err != nil {
+		ErrorHandlerFunc(w, r, err)
+	}
+}
+
+// Изменение весов вариантов запущенного эксперимента
+// (PATCH /experiment/{id})
+func (s *BannerService) PatchExperimentId(w http.ResponseWriter, r *http.Request, id int, params PatchExperimentIdParams) {
+	u, found := user.FromContext(r.Context())
+	if !found {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if u.Role != "ADMIN" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	data := new(PatchExperimentIdJSONBody)
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	if err := checkVariants(data.Variants); err != nil {
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	err := s.repo.UpdateExperimentWeights(r.Context(), id, data.Variants)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// Завершение эксперимента с выбором победителя
+// (POST /experiment/{id}/promote)
+func (s *BannerService) PostExperimentIdPromote(w http.ResponseWriter, r *http.Request, id int, params PostExperimentIdPromoteParams) {
+	u, found := user.FromContext(r.Context())
+	if !found {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if u.Role != "ADMIN" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	data := new(PostExperimentIdPromoteJSONBody)
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	err := s.repo.PromoteExperimentWinner(r.Context(), id, data.BannerId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// Получение черновиков баннеров по состоянию
+// (GET /draft)
+func (s *BannerService) GetDraft(w http.ResponseWriter, r *http.Request, params GetDraftParams) {
+	u, found := user.FromContext(r.Context())
+	if !found {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if u.Role != "ADMIN" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	status := DraftStatusPending
+	if params.Status != nil {
+		status = DraftStatus(*params.Status)
+	}
+
+	switch status {
+	case DraftStatusDraft, DraftStatusPending, DraftStatusApproved, DraftStatusRejected:
+	default:
+		ErrorHandlerFunc(w, r, &InvalidParamFormatError{
+			ParamName: "status",
+			Err:       fmt.Errorf("unknown draft status %q", status),
+		})
+		return
+	}
+
+	response, err := s.repo.GetDrafts(r.Context(), status)
+	if err != nil {
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	if err = json.NewEncoder(w).Encode(response); err != nil {
+		ErrorHandlerFunc(w, r, err)
+	}
+}
+
+// Получение черновика баннера
+// (GET /draft/{id})
+func (s *BannerService) GetDraftId(w http.ResponseWriter, r *http.Request, id int, params GetDraftIdParams) {
+	u, found := user.FromContext(r.Context())
+	if !found {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if u.Role != "ADMIN" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	response, err := s.repo.GetDraft(r.Context(), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	if err = json.NewEncoder(w).Encode(response); err != nil {
+		ErrorHandlerFunc(w, r, err)
+	}
+}
+
+// Отправка черновика на проверку
+// (POST /draft/{id}/submit)
+func (s *BannerService) PostDraftIdSubmit(w http.ResponseWriter, r *http.Request, id int, params PostDraftIdSubmitParams) {
+	u, found := user.FromContext(r.Context())
+	if !found {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if u.Role != "ADMIN" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	err := s.repo.SubmitDraft(r.Context(), id, u.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// Одобрение черновика и публикация баннера
+// (POST /draft/{id}/approve)
+func (s *BannerService) PostDraftIdApprove(w http.ResponseWriter, r *http.Request, id int, params PostDraftIdApproveParams) {
+	u, found := user.FromContext(r.Context())
+	if !found {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if u.Role != "ADMIN" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	draft, err := s.repo.GetDraft(r.Context(), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	if draft.Status != DraftStatusPending {
+		ErrorHandlerFunc(w, r, &DraftStateError{Status: draft.Status})
+		return
+	}
+
+	change, err := s.prepareDraft(r.Context(), draft)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	// the draft stays pending unless its change is published
+	bannerID, err := s.repo.ApproveDraft(r.Context(), id, u.ID, change)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	if err = json.NewEncoder(w).Encode(PostBannerResponse{BannerID: bannerID}); err != nil {
+		ErrorHandlerFunc(w, r, err)
+	}
+}
+
+// Отклонение черновика
+// (POST /draft/{id}/reject)
+func (s *BannerService) PostDraftIdReject(w http.ResponseWriter, r *http.Request, id int, params PostDraftIdRejectParams) {
+	u, found := user.FromContext(r.Context())
+	if !found {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if u.Role != "ADMIN" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	// the comment is optional, so is the body
+	data := new(PostDraftIdRejectJSONBody)
+	defer r.Body.Close()
+	if err := json.NewDecoder(r.Body).Decode(data); err != nil && err != io.EOF {
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	_, err := s.repo.ReviewDraft(r.Context(), id, u.ID, DraftStatusRejected, data.Comment)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// Переход по ссылке баннера
+// (GET /r/{banner_id})
+func (s *BannerService) GetRBannerId(w http.ResponseWriter, r *http.Request, bannerId int, params GetRBannerIdParams) {
+	u, found := user.FromContext(r.Context())
+	if !found {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	// only banners the users can be shown right now are followed,
+	// inactive, deleted and unscheduled ones are not found
+	banner, err := s.repo.GetClickableBanner(r.Context(), bannerId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	var content struct {
+		URL string `json:"url"`
+	}
+	// content without url is not an error, there is just nowhere to go
+	_ = json.Unmarshal(banner.Content, &content)
+
+	if content.URL == "" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	s.track(eventClick, *banner, u.ID)
+
+	http.Redirect(w, r, content.URL, http.StatusFound)
+}
+
+// GetAnalyticsResponse is a line of the statistics, identified
+// by the fields the statistics are grouped by.
+type GetAnalyticsResponse struct {
+	BannerID    *int    `json:"banner_id,omitempty"`
+	FeatureID   *int    `json:"feature_id,omitempty"`
+	TagID       *int    `json:"tag_id,omitempty"`
+	Impressions int     `json:"impressions"`
+	Clicks      int     `json:"clicks"`
+	CTR         float64 `json:"ctr"`
+}
+
+// Получение показов, кликов и CTR баннеров
+// (GET /analytics)
+func (s *BannerService) GetAnalytics(w http.ResponseWriter, r *http.Request, params GetAnalyticsParams) {
+	u, found := user.FromContext(r.Context())
+	if !found {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if u.Role != "ADMIN" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	if params.From != nil && params.Until != nil && !params.From.Before(*params.Until) {
+		ErrorHandlerFunc(w, r, &InvalidParamFormatError{
+			ParamName: "until",
+			Err:       errors.New("until must be after from"),
+		})
+		return
+	}
+
+	if params.GroupBy != nil {
+		switch *params.GroupBy {
+		case GetAnalyticsParamsGroupByBanner, GetAnalyticsParamsGroupByFeature, GetAnalyticsParamsGroupByTag:
+		default:
+			ErrorHandlerFunc(w, r, &InvalidParamFormatError{
+				ParamName: "group_by",
+				Err:       fmt.Errorf("unknown grouping %q", *params.GroupBy),
+			})
+			return
+		}
+	}
+
+	response, err := s.repo.GetBannerStats(r.Context(), params)
+	if err != nil {
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	if err = json.NewEncoder(w).Encode(response); err != nil {
+		ErrorHandlerFunc(w, r, err)
+	}
+}
+
+// План применения манифеста баннеров
+// (POST /manifest/plan)
+func (s *BannerService) PostManifestPlan(w http.ResponseWriter, r *http.Request, params PostManifestPlanParams) {
+	u, found := user.FromContext(r.Context())
+	if !found {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if u.Role != "ADMIN" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	defer r.Body.Close()
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	plan, _, err := s.planManifest(r.Context(), body)
+	if err != nil {
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	if err = json.NewEncoder(w).Encode(plan); err != nil {
+		ErrorHandlerFunc(w, r, err)
+	}
+}
+
+// Применение манифеста баннеров
+// (POST /manifest/apply)
+func (s *BannerService) PostManifestApply(w http.ResponseWriter, r *http.Request, params PostManifestApplyParams) {
+	u, found := user.FromContext(r.Context())
+	if !found {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if u.Role != "ADMIN" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	// no second admin reviews the changes of the manifest
+	if !s.config.ManifestApply {
+		ErrorHandlerFunc(w, r, &ManifestApplyDisabledError{})
+		return
+	}
+
+	defer r.Body.Close()
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	plan, changes, err := s.planManifest(r.Context(), body)
+	if err != nil {
+		ErrorHandlerFunc(w, r, err)
+		return
+	}
+
+	if !changes.empty() {
+		created, err := s.repo.ApplyManifest(r.Context(), *changes)
+		if err == sql.ErrNoRows {
+			err = &StaleManifestPlanError{}
+		}
+		if err != nil {
+			ErrorHandlerFunc(w, r, err)
+			return
+		}
+
+		for i, change := range plan.Create {
+			id := created[change.Id]
+			plan.Create[i].BannerId = &id
+		}
+	}
+
+	if err = json.NewEncoder(w).Encode(plan); err != nil {
 		ErrorHandlerFunc(w, r, err)
 	}
 }
@@ -3,42 +3,320 @@
 // Code generated by github.com/deepmap/oapi-codegen/v2 version v2.1.0 DO NOT EDIT.
 package banner
 
+import (
+	"time"
+)
+
+// Defines values for DeleteJobResultOutcome.
+const (
+	DeleteJobResultOutcomeDeleted  DeleteJobResultOutcome = "deleted"
+	DeleteJobResultOutcomeError    DeleteJobResultOutcome = "error"
+	DeleteJobResultOutcomeNotFound DeleteJobResultOutcome = "not_found"
+)
+
+// Defines values for DeleteJobStatus.
+const (
+	DeleteJobStatusDone    DeleteJobStatus = "done"
+	DeleteJobStatusFailed  DeleteJobStatus = "failed"
+	DeleteJobStatusQueued  DeleteJobStatus = "queued"
+	DeleteJobStatusRunning DeleteJobStatus = "running"
+)
+
+// Defines values for DraftStatus.
+const (
+	DraftStatusApproved DraftStatus = "approved"
+	DraftStatusDraft    DraftStatus = "draft"
+	DraftStatusPending  DraftStatus = "pending"
+	DraftStatusRejected DraftStatus = "rejected"
+)
+
+// Defines values for GetAnalyticsParamsGroupBy.
+const (
+	GetAnalyticsParamsGroupByBanner  GetAnalyticsParamsGroupBy = "banner"
+	GetAnalyticsParamsGroupByFeature GetAnalyticsParamsGroupBy = "feature"
+	GetAnalyticsParamsGroupByTag     GetAnalyticsParamsGroupBy = "tag"
+)
+
+// Defines values for GetBannerExportParamsFormat.
+const (
+	GetBannerExportParamsFormatCsv    GetBannerExportParamsFormat = "csv"
+	GetBannerExportParamsFormatNdjson GetBannerExportParamsFormat = "ndjson"
+)
+
+// Defines values for GetBannerParamsOrder.
+const (
+	GetBannerParamsOrderAsc  GetBannerParamsOrder = "asc"
+	GetBannerParamsOrderDesc GetBannerParamsOrder = "desc"
+)
+
+// Defines values for GetBannerParamsSort.
+const (
+	GetBannerParamsSortCreatedAt GetBannerParamsSort = "created_at"
+	GetBannerParamsSortId        GetBannerParamsSort = "id"
+	GetBannerParamsSortUpdatedAt GetBannerParamsSort = "updated_at"
+)
+
+// Defines values for GetDraftParamsStatus.
+const (
+	GetDraftParamsStatusApproved GetDraftParamsStatus = "approved"
+	GetDraftParamsStatusDraft    GetDraftParamsStatus = "draft"
+	GetDraftParamsStatusPending  GetDraftParamsStatus = "pending"
+	GetDraftParamsStatusRejected GetDraftParamsStatus = "rejected"
+)
+
+// Defines values for PostBannerImportParamsFormat.
+const (
+	PostBannerImportParamsFormatCsv    PostBannerImportParamsFormat = "csv"
+	PostBannerImportParamsFormatNdjson PostBannerImportParamsFormat = "ndjson"
+)
+
+// ConflictError Конфликт активных баннеров
+type ConflictError struct {
+	// BannerIds Идентификаторы конфликтующих баннеров
+	BannerIds []int  `json:"banner_ids"`
+	Error     string `json:"error"`
+}
+
+// DeleteJob Задача асинхронного удаления баннеров
+type DeleteJob struct {
+	// BannerIds Идентификаторы удаляемых баннеров, при удалении по фильтру пополняются по мере удаления
+	BannerIds []int     `json:"banner_ids"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Error Причина неудачи задачи
+	Error *string `json:"error"`
+
+	// FeatureId Фича, все баннеры которой удаляет задача
+	FeatureId  *int       `json:"feature_id"`
+	FinishedAt *time.Time `json:"finished_at"`
+
+	// JobId Идентификатор задачи
+	JobId string `json:"job_id"`
+
+	// Results Итоги удаления по баннерам, заполняются по завершении задачи, при удалении по фильтру по мере удаления
+	Results   []DeleteJobResult `json:"results"`
+	StartedAt *time.Time        `json:"started_at"`
+
+	// Status Состояние задачи
+	Status DeleteJobStatus `json:"status"`
+
+	// TagId Тэг, все баннеры с которым удаляет задача
+	TagId *int `json:"tag_id"`
+}
+
+// DeleteJobStatus Состояние задачи
+type DeleteJobStatus string
+
+// DeleteJobResult Итог удаления баннера
+type DeleteJobResult struct {
+	// BannerId Идентификатор баннера
+	BannerId int `json:"banner_id"`
+
+	// Error Причина неудачи удаления
+	Error *string `json:"error,omitempty"`
+
+	// Outcome Итог удаления
+	Outcome DeleteJobResultOutcome `json:"outcome"`
+}
+
+// DeleteJobResultOutcome Итог удаления
+type DeleteJobResultOutcome string
+
+// Draft Черновик нового баннера или изменения существующего
+type Draft struct {
+	// BannerId Идентификатор изменяемого баннера, отсутствует у нового баннера до публикации
+	BannerId  *int      `json:"banner_id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// CreatedBy Идентификатор создавшего черновик админа
+	CreatedBy int `json:"created_by"`
+
+	// DraftId Идентификатор черновика
+	DraftId int `json:"draft_id"`
+
+	// Payload Тело запроса создания или изменения баннера
+	Payload map[string]interface{} `json:"payload"`
+
+	// ReviewComment Причина отклонения
+	ReviewComment *string    `json:"review_comment"`
+	ReviewedAt    *time.Time `json:"reviewed_at"`
+
+	// ReviewedBy Идентификатор одобрившего или отклонившего черновик админа
+	ReviewedBy *int `json:"reviewed_by"`
+
+	// Status Состояние черновика
+	Status      DraftStatus `json:"status"`
+	SubmittedAt *time.Time  `json:"submitted_at"`
+
+	// SubmittedBy Идентификатор отправившего черновик на проверку админа
+	SubmittedBy *int      `json:"submitted_by"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// DraftStatus Состояние черновика
+type DraftStatus string
+
 // Error Ошибка
 type Error struct {
 	Error string `json:"error"`
 }
 
+// FieldError Ошибка валидации поля
+type FieldError struct {
+	Error string `json:"error"`
+
+	// Field JSON Pointer на поле содержимого
+	Field string `json:"field"`
+}
+
+// ImportError Ошибки строк загружаемого файла
+type ImportError struct {
+	Error string     `json:"error"`
+	Rows  []RowError `json:"rows"`
+}
+
+// ManifestChange Изменение управляемого манифестом баннера
+type ManifestChange struct {
+	// BannerId Идентификатор баннера, отсутствует у ещё не созданного
+	BannerId *int `json:"banner_id"`
+
+	// FeatureId Идентификатор фичи
+	FeatureId int `json:"feature_id"`
+
+	// Fields Изменяемые поля баннера
+	Fields *[]string `json:"fields,omitempty"`
+
+	// Id Внешний идентификатор баннера в манифесте
+	Id string `json:"id"`
+}
+
+// ManifestPlan План приведения управляемых баннеров к манифесту
+type ManifestPlan struct {
+	// Create Баннеры, которые будут созданы
+	Create []ManifestChange `json:"create"`
+
+	// Deactivate Баннеры, убранные из манифеста, которые будут выключены
+	Deactivate []ManifestChange `json:"deactivate"`
+
+	// Unchanged Количество баннеров манифеста без изменений
+	Unchanged int `json:"unchanged"`
+
+	// Update Баннеры, которые будут изменены
+	Update []ManifestChange `json:"update"`
+}
+
+// RowError Ошибка строки загружаемого файла
+type RowError struct {
+	// BannerIds Идентификаторы конфликтующих баннеров
+	BannerIds *[]int        `json:"banner_ids,omitempty"`
+	Error     string        `json:"error"`
+	Fields    *[]FieldError `json:"fields,omitempty"`
+
+	// Row Номер строки данных, начиная с 1
+	Row int `json:"row"`
+}
+
+// ValidationError Ошибка валидации содержимого баннера
+type ValidationError struct {
+	Error  string       `json:"error"`
+	Fields []FieldError `json:"fields"`
+}
+
+// Variant Вариант A/B эксперимента
+type Variant struct {
+	// BannerId Идентификатор баннера
+	BannerId int `json:"banner_id"`
+
+	// Weight Вес варианта
+	Weight int `json:"weight"`
+}
+
+// GetAnalyticsParams defines parameters for GetAnalytics.
+type GetAnalyticsParams struct {
+	BannerId  *int       `form:"banner_id,omitempty" json:"banner_id,omitempty"`
+	FeatureId *int       `form:"feature_id,omitempty" json:"feature_id,omitempty"`
+	TagId     *int       `form:"tag_id,omitempty" json:"tag_id,omitempty"`
+	From      *time.Time `form:"from,omitempty" json:"from,omitempty"`
+	Until     *time.Time `form:"until,omitempty" json:"until,omitempty"`
+
+	// GroupBy Группировка статистики
+	GroupBy *GetAnalyticsParamsGroupBy `form:"group_by,omitempty" json:"group_by,omitempty"`
+
+	// Token Токен админа
+	Token *string `json:"token,omitempty"`
+}
+
+// GetAnalyticsParamsGroupBy defines parameters for GetAnalytics.
+type GetAnalyticsParamsGroupBy string
+
 // DeleteBannerJSONBody defines parameters for DeleteBanner.
 type DeleteBannerJSONBody = []int
 
 // DeleteBannerParams defines parameters for DeleteBanner.
 type DeleteBannerParams struct {
+	FeatureId *int `form:"feature_id,omitempty" json:"feature_id,omitempty"`
+	TagId     *int `form:"tag_id,omitempty" json:"tag_id,omitempty"`
+
 	// Token Токен админа
 	Token *string `json:"token,omitempty"`
 }
 
 // GetBannerParams defines parameters for GetBanner.
 type GetBannerParams struct {
-	FeatureId *int `form:"feature_id,omitempty" json:"feature_id,omitempty"`
-	TagId     *int `form:"tag_id,omitempty" json:"tag_id,omitempty"`
-	Limit     *int `form:"limit,omitempty" json:"limit,omitempty"`
-	Offset    *int `form:"offset,omitempty" json:"offset,omitempty"`
+	FeatureId      *int                  `form:"feature_id,omitempty" json:"feature_id,omitempty"`
+	TagId          *int                  `form:"tag_id,omitempty" json:"tag_id,omitempty"`
+	Limit          *int                  `form:"limit,omitempty" json:"limit,omitempty"`
+	Offset         *int                  `form:"offset,omitempty" json:"offset,omitempty"`
+	IncludeDeleted *bool                 `form:"include_deleted,omitempty" json:"include_deleted,omitempty"`
+	OnlyDeleted    *bool                 `form:"only_deleted,omitempty" json:"only_deleted,omitempty"`
+	Q              *string               `form:"q,omitempty" json:"q,omitempty"`
+	After          *string               `form:"after,omitempty" json:"after,omitempty"`
+	Sort           *GetBannerParamsSort  `form:"sort,omitempty" json:"sort,omitempty"`
+	Order          *GetBannerParamsOrder `form:"order,omitempty" json:"order,omitempty"`
+	WithTotal      *bool                 `form:"with_total,omitempty" json:"with_total,omitempty"`
 
 	// Token Токен админа
 	Token *string `json:"token,omitempty"`
 }
 
+// GetBannerParamsSort defines parameters for GetBanner.
+type GetBannerParamsSort string
+
+// GetBannerParamsOrder defines parameters for GetBanner.
+type GetBannerParamsOrder string
+
+// GetBannerDeleteJobsIdParams defines parameters for GetBannerDeleteJobsId.
+type GetBannerDeleteJobsIdParams struct {
+	// Token Токен админа
+	Token *string `json:"token,omitempty"`
+}
+
 // PostBannerJSONBody defines parameters for PostBanner.
 type PostBannerJSONBody struct {
+	// ActiveFrom Начало показа баннера
+	ActiveFrom *time.Time `json:"active_from,omitempty"`
+
+	// ActiveUntil Окончание показа баннера
+	ActiveUntil *time.Time `json:"active_until,omitempty"`
+
 	// Content Содержимое баннера
 	Content *map[string]interface{} `json:"content,omitempty"`
 
 	// FeatureId Идентификатор фичи
 	FeatureId *int `json:"feature_id,omitempty"`
 
+	// FrequencyCap Максимум показов баннера одному пользователю в сутки
+	FrequencyCap *int `json:"frequency_cap,omitempty"`
+
 	// IsActive Флаг активности баннера
 	IsActive *bool `json:"is_active,omitempty"`
 
+	// LocalizedContent Содержимое баннера по локалям
+	LocalizedContent *map[string]map[string]interface{} `json:"localized_content,omitempty"`
+
+	// Priority Приоритет баннера среди подходящих пользователю
+	Priority *int `json:"priority,omitempty"`
+
 	// TagIds Идентификаторы тэгов
 	TagIds *[]int `json:"tag_ids,omitempty"`
 }
@@ -49,6 +327,43 @@ type PostBannerParams struct {
 	Token *string `json:"token,omitempty"`
 }
 
+// GetBannerExportParams defines parameters for GetBannerExport.
+type GetBannerExportParams struct {
+	FeatureId      *int                         `form:"feature_id,omitempty" json:"feature_id,omitempty"`
+	TagId          *int                         `form:"tag_id,omitempty" json:"tag_id,omitempty"`
+	Limit          *int                         `form:"limit,omitempty" json:"limit,omitempty"`
+	Offset         *int                         `form:"offset,omitempty" json:"offset,omitempty"`
+	IncludeDeleted *bool                        `form:"include_deleted,omitempty" json:"include_deleted,omitempty"`
+	OnlyDeleted    *bool                        `form:"only_deleted,omitempty" json:"only_deleted,omitempty"`
+	Format         *GetBannerExportParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+
+	// Token Токен админа
+	Token *string `json:"token,omitempty"`
+}
+
+// GetBannerExportParamsFormat defines parameters for GetBannerExport.
+type GetBannerExportParamsFormat string
+
+// PostBannerImportParams defines parameters for PostBannerImport.
+type PostBannerImportParams struct {
+	Format *PostBannerImportParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+	DryRun *bool                         `form:"dry_run,omitempty" json:"dry_run,omitempty"`
+
+	// Token Токен админа
+	Token *string `json:"token,omitempty"`
+}
+
+// PostBannerImportParamsFormat defines parameters for PostBannerImport.
+type PostBannerImportParamsFormat string
+
+// PostBannerPurgeParams defines parameters for PostBannerPurge.
+type PostBannerPurgeParams struct {
+	DryRun *bool `form:"dry_run,omitempty" json:"dry_run,omitempty"`
+
+	// Token Токен админа
+	Token *string `json:"token,omitempty"`
+}
+
 // DeleteBannerIdParams defines parameters for DeleteBannerId.
 type DeleteBannerIdParams struct {
 	// Token Токен админа
@@ -57,15 +372,30 @@ type DeleteBannerIdParams struct {
 
 // PatchBannerIdJSONBody defines parameters for PatchBannerId.
 type PatchBannerIdJSONBody struct {
+	// ActiveFrom Начало показа баннера, явный null снимает ограничение
+	ActiveFrom *time.Time `json:"active_from"`
+
+	// ActiveUntil Окончание показа баннера, явный null снимает ограничение
+	ActiveUntil *time.Time `json:"active_until"`
+
 	// Content Содержимое баннера
 	Content *map[string]interface{} `json:"content"`
 
 	// FeatureId Идентификатор фичи
 	FeatureId *int `json:"feature_id"`
 
+	// FrequencyCap Максимум показов баннера одному пользователю в сутки, явный null снимает ограничение
+	FrequencyCap *int `json:"frequency_cap"`
+
 	// IsActive Флаг активности баннера
 	IsActive *bool `json:"is_active"`
 
+	// LocalizedContent Содержимое баннера по локалям, заменяет прежнее целиком
+	LocalizedContent *map[string]map[string]interface{} `json:"localized_content"`
+
+	// Priority Приоритет баннера среди подходящих пользователю
+	Priority *int `json:"priority"`
+
 	// TagIds Идентификаторы тэгов
 	TagIds *[]int `json:"tag_ids"`
 }
@@ -76,12 +406,297 @@ type PatchBannerIdParams struct {
 	Token *string `json:"token,omitempty"`
 }
 
+// PostBannerIdRestoreParams defines parameters for PostBannerIdRestore.
+type PostBannerIdRestoreParams struct {
+	// Token Токен админа
+	Token *string `json:"token,omitempty"`
+}
+
+// GetBannerIdVersionsParams defines parameters for GetBannerIdVersions.
+type GetBannerIdVersionsParams struct {
+	// Token Токен админа
+	Token *string `json:"token,omitempty"`
+}
+
+// PostBannerIdVersionsVersionRollbackParams defines parameters for PostBannerIdVersionsVersionRollback.
+type PostBannerIdVersionsVersionRollbackParams struct {
+	// Token Токен админа
+	Token *string `json:"token,omitempty"`
+}
+
+// GetDraftParams defines parameters for GetDraft.
+type GetDraftParams struct {
+	// Status Состояние черновиков
+	Status *GetDraftParamsStatus `form:"status,omitempty" json:"status,omitempty"`
+
+	// Token Токен админа
+	Token *string `json:"token,omitempty"`
+}
+
+// GetDraftParamsStatus defines parameters for GetDraft.
+type GetDraftParamsStatus string
+
+// GetDraftIdParams defines parameters for GetDraftId.
+type GetDraftIdParams struct {
+	// Token Токен админа
+	Token *string `json:"token,omitempty"`
+}
+
+// PostDraftIdApproveParams defines parameters for PostDraftIdApprove.
+type PostDraftIdApproveParams struct {
+	// Token Токен админа
+	Token *string `json:"token,omitempty"`
+}
+
+// PostDraftIdRejectJSONBody defines parameters for PostDraftIdReject.
+type PostDraftIdRejectJSONBody struct {
+	// Comment Причина отклонения
+	Comment *string `json:"comment,omitempty"`
+}
+
+// PostDraftIdRejectParams defines parameters for PostDraftIdReject.
+type PostDraftIdRejectParams struct {
+	// Token Токен админа
+	Token *string `json:"token,omitempty"`
+}
+
+// PostDraftIdSubmitParams defines parameters for PostDraftIdSubmit.
+type PostDraftIdSubmitParams struct {
+	// Token Токен админа
+	Token *string `json:"token,omitempty"`
+}
+
+// PostExperimentJSONBody defines parameters for PostExperiment.
+type PostExperimentJSONBody struct {
+	// FeatureId Идентификатор фичи
+	FeatureId int `json:"feature_id"`
+
+	// TagId Идентификатор тэга
+	TagId int `json:"tag_id"`
+
+	// Variants Баннеры фичи, участвующие в эксперименте, не менее двух
+	Variants []Variant `json:"variants"`
+}
+
+// PostExperimentParams defines parameters for PostExperiment.
+type PostExperimentParams struct {
+	// Token Токен админа
+	Token *string `json:"token,omitempty"`
+}
+
+// GetExperimentIdParams defines parameters for GetExperimentId.
+type GetExperimentIdParams struct {
+	// Token Токен админа
+	Token *string `json:"token,omitempty"`
+}
+
+// PatchExperimentIdJSONBody defines parameters for PatchExperimentId.
+type PatchExperimentIdJSONBody struct {
+	// Variants Новые веса вариантов эксперимента
+	Variants []Variant `json:"variants"`
+}
+
+// PatchExperimentIdParams defines parameters for PatchExperimentId.
+type PatchExperimentIdParams struct {
+	// Token Токен админа
+	Token *string `json:"token,omitempty"`
+}
+
+// PostExperimentIdPromoteJSONBody defines parameters for PostExperimentIdPromote.
+type PostExperimentIdPromoteJSONBody struct {
+	// BannerId Идентификатор баннера-победителя
+	BannerId int `json:"banner_id"`
+}
+
+// PostExperimentIdPromoteParams defines parameters for PostExperimentIdPromote.
+type PostExperimentIdPromoteParams struct {
+	// Token Токен админа
+	Token *string `json:"token,omitempty"`
+}
+
+// GetFeatureParams defines parameters for GetFeature.
+type GetFeatureParams struct {
+	IncludeArchived *bool `form:"include_archived,omitempty" json:"include_archived,omitempty"`
+
+	// Token Токен админа
+	Token *string `json:"token,omitempty"`
+}
+
+// PostFeatureJSONBody defines parameters for PostFeature.
+type PostFeatureJSONBody struct {
+	// Description Описание фичи
+	Description *string `json:"description,omitempty"`
+
+	// FeatureId Идентификатор фичи
+	FeatureId *int `json:"feature_id,omitempty"`
+
+	// Name Уникальное название фичи
+	Name *string `json:"name,omitempty"`
+}
+
+// PostFeatureParams defines parameters for PostFeature.
+type PostFeatureParams struct {
+	// Token Токен админа
+	Token *string `json:"token,omitempty"`
+}
+
+// DeleteFeatureFeatureIdParams defines parameters for DeleteFeatureFeatureId.
+type DeleteFeatureFeatureIdParams struct {
+	// Token Токен админа
+	Token *string `json:"token,omitempty"`
+}
+
+// GetFeatureFeatureIdParams defines parameters for GetFeatureFeatureId.
+type GetFeatureFeatureIdParams struct {
+	// Token Токен админа
+	Token *string `json:"token,omitempty"`
+}
+
+// PatchFeatureFeatureIdJSONBody defines parameters for PatchFeatureFeatureId.
+type PatchFeatureFeatureIdJSONBody struct {
+	// Description Описание фичи
+	Description *string `json:"description,omitempty"`
+
+	// IsArchived Флаг архивации, к архивной фиче нельзя привязать новые баннеры
+	IsArchived *bool `json:"is_archived,omitempty"`
+
+	// Name Уникальное название фичи
+	Name *string `json:"name,omitempty"`
+}
+
+// PatchFeatureFeatureIdParams defines parameters for PatchFeatureFeatureId.
+type PatchFeatureFeatureIdParams struct {
+	// Token Токен админа
+	Token *string `json:"token,omitempty"`
+}
+
+// DeleteFeatureFeatureIdFallbackParams defines parameters for DeleteFeatureFeatureIdFallback.
+type DeleteFeatureFeatureIdFallbackParams struct {
+	// Token Токен админа
+	Token *string `json:"token,omitempty"`
+}
+
+// GetFeatureFeatureIdFallbackParams defines parameters for GetFeatureFeatureIdFallback.
+type GetFeatureFeatureIdFallbackParams struct {
+	// Token Токен админа
+	Token *string `json:"token,omitempty"`
+}
+
+// PutFeatureFeatureIdFallbackJSONBody defines parameters for PutFeatureFeatureIdFallback.
+type PutFeatureFeatureIdFallbackJSONBody struct {
+	// BannerId Идентификатор баннера фичи
+	BannerId int `json:"banner_id"`
+}
+
+// PutFeatureFeatureIdFallbackParams defines parameters for PutFeatureFeatureIdFallback.
+type PutFeatureFeatureIdFallbackParams struct {
+	// Token Токен админа
+	Token *string `json:"token,omitempty"`
+}
+
+// GetFeatureFeatureIdSchemaParams defines parameters for GetFeatureFeatureIdSchema.
+type GetFeatureFeatureIdSchemaParams struct {
+	// Token Токен админа
+	Token *string `json:"token,omitempty"`
+}
+
+// PutFeatureFeatureIdSchemaJSONBody defines parameters for PutFeatureFeatureIdSchema.
+type PutFeatureFeatureIdSchemaJSONBody map[string]interface{}
+
+// PutFeatureFeatureIdSchemaParams defines parameters for PutFeatureFeatureIdSchema.
+type PutFeatureFeatureIdSchemaParams struct {
+	// Token Токен админа
+	Token *string `json:"token,omitempty"`
+}
+
+// PostManifestApplyParams defines parameters for PostManifestApply.
+type PostManifestApplyParams struct {
+	// Token Токен админа
+	Token *string `json:"token,omitempty"`
+}
+
+// PostManifestPlanParams defines parameters for PostManifestPlan.
+type PostManifestPlanParams struct {
+	// Token Токен админа
+	Token *string `json:"token,omitempty"`
+}
+
+// GetRBannerIdParams defines parameters for GetRBannerId.
+type GetRBannerIdParams struct {
+	// Token Токен пользователя
+	Token *string `json:"token,omitempty"`
+}
+
+// GetTagParams defines parameters for GetTag.
+type GetTagParams struct {
+	IncludeArchived *bool `form:"include_archived,omitempty" json:"include_archived,omitempty"`
+
+	// Token Токен админа
+	Token *string `json:"token,omitempty"`
+}
+
+// PostTagJSONBody defines parameters for PostTag.
+type PostTagJSONBody struct {
+	// Description Описание тэга
+	Description *string `json:"description,omitempty"`
+
+	// Name Уникальное название тэга
+	Name *string `json:"name,omitempty"`
+
+	// TagId Идентификатор тэга
+	TagId *int `json:"tag_id,omitempty"`
+}
+
+// PostTagParams defines parameters for PostTag.
+type PostTagParams struct {
+	// Token Токен админа
+	Token *string `json:"token,omitempty"`
+}
+
+// DeleteTagTagIdParams defines parameters for DeleteTagTagId.
+type DeleteTagTagIdParams struct {
+	// Token Токен админа
+	Token *string `json:"token,omitempty"`
+}
+
+// GetTagTagIdParams defines parameters for GetTagTagId.
+type GetTagTagIdParams struct {
+	// Token Токен админа
+	Token *string `json:"token,omitempty"`
+}
+
+// PatchTagTagIdJSONBody defines parameters for PatchTagTagId.
+type PatchTagTagIdJSONBody struct {
+	// Description Описание тэга
+	Description *string `json:"description,omitempty"`
+
+	// IsArchived Флаг архивации, к архивной тэгу нельзя привязать новые баннеры
+	IsArchived *bool `json:"is_archived,omitempty"`
+
+	// Name Уникальное название тэга
+	Name *string `json:"name,omitempty"`
+}
+
+// PatchTagTagIdParams defines parameters for PatchTagTagId.
+type PatchTagTagIdParams struct {
+	// Token Токен админа
+	Token *string `json:"token,omitempty"`
+}
+
 // GetUserBannerParams defines parameters for GetUserBanner.
 type GetUserBannerParams struct {
-	TagId           int   `form:"tag_id" json:"tag_id"`
+	// TagId Тэги пользователя, параметр повторяется для каждого тэга
+	TagId           []int `form:"tag_id" json:"tag_id"`
 	FeatureId       int   `form:"feature_id" json:"feature_id"`
 	UseLastRevision *bool `form:"use_last_revision,omitempty" json:"use_last_revision,omitempty"`
 
+	// Locale Локаль содержимого баннера, имеет приоритет над заголовком Accept-Language
+	Locale          *string `form:"locale,omitempty" json:"locale,omitempty"`
+	TrackImpression *bool   `form:"track_impression,omitempty" json:"track_impression,omitempty"`
+
+	// AcceptLanguage Предпочитаемые языки пользователя
+	AcceptLanguage *string `json:"Accept-Language,omitempty"`
+
 	// Token Токен пользователя
 	Token *string `json:"token,omitempty"`
 }
@@ -94,3 +709,33 @@ type PostBannerJSONRequestBody PostBannerJSONBody
 
 // PatchBannerIdJSONRequestBody defines body for PatchBannerId for application/json ContentType.
 type PatchBannerIdJSONRequestBody PatchBannerIdJSONBody
+
+// PostDraftIdRejectJSONRequestBody defines body for PostDraftIdReject for application/json ContentType.
+type PostDraftIdRejectJSONRequestBody PostDraftIdRejectJSONBody
+
+// PostExperimentJSONRequestBody defines body for PostExperiment for application/json ContentType.
+type PostExperimentJSONRequestBody PostExperimentJSONBody
+
+// PatchExperimentIdJSONRequestBody defines body for PatchExperimentId for application/json ContentType.
+type PatchExperimentIdJSONRequestBody PatchExperimentIdJSONBody
+
+// PostExperimentIdPromoteJSONRequestBody defines body for PostExperimentIdPromote for application/json ContentType.
+type PostExperimentIdPromoteJSONRequestBody PostExperimentIdPromoteJSONBody
+
+// PostFeatureJSONRequestBody defines body for PostFeature for application/json ContentType.
+type PostFeatureJSONRequestBody PostFeatureJSONBody
+
+// PatchFeatureFeatureIdJSONRequestBody defines body for PatchFeatureFeatureId for application/json ContentType.
+type PatchFeatureFeatureIdJSONRequestBody PatchFeatureFeatureIdJSONBody
+
+// PutFeatureFeatureIdFallbackJSONRequestBody defines body for PutFeatureFeatureIdFallback for application/json ContentType.
+type PutFeatureFeatureIdFallbackJSONRequestBody PutFeatureFeatureIdFallbackJSONBody
+
+// PutFeatureFeatureIdSchemaJSONRequestBody defines body for PutFeatureFeatureIdSchema for application/json ContentType.
+type PutFeatureFeatureIdSchemaJSONRequestBody PutFeatureFeatureIdSchemaJSONBody
+
+// PostTagJSONRequestBody defines body for PostTag for application/json ContentType.
+type PostTagJSONRequestBody PostTagJSONBody
+
+// PatchTagTagIdJSONRequestBody defines body for PatchTagTagId for application/json ContentType.
+type PatchTagTagIdJSONRequestBody PatchTagTagIdJSONBody
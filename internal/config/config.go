@@ -13,10 +13,21 @@ import (
 const (
 	defaultServerPort = 8080
 	// HUGE expiration for Postman to test with always valid token
-	defaultJWTExpiration      = 480 * time.Hour
-	defaultShutdownTimeout    = 30 * time.Second
-	defaultCacheExpiration    = 5 * time.Minute
-	defaultBannerBufferLength = 5
+	defaultJWTExpiration       = 480 * time.Hour
+	defaultShutdownTimeout     = 30 * time.Second
+	defaultCacheExpiration     = 5 * time.Minute
+	defaultBannerRevisions     = 3
+	defaultEventBufferLength   = 100
+	defaultPurgeRetention      = 30 * 24 * time.Hour
+	defaultPurgeInterval       = time.Hour
+	defaultPurgeBatchSize      = 100
+	defaultDeleteJobRetention  = 24 * time.Hour
+	defaultDeleteChunkSize     = 500
+	defaultDeleteFlushInterval = 10 * time.Second
+	defaultDeleteMaxBatchSize  = 1000
+	defaultDeleteQueueLimit    = 100
+	defaultImportMaxBytes      = 32 << 20
+	defaultImportMaxRows       = 10000
 )
 
 // Config represents an application configuration.
@@ -33,10 +44,39 @@ type Config struct {
 	ShutdownTimeout time.Duration
 	// Live mode for development instant reload. Local default true
 	LiveMode bool `yaml:"live_reload" env:"LIVE_RELOAD"`
-	// Length of buffer for async banners deleting. Defaults to 5
-	BannerBufferLength int `yaml:"banner_buffer_length" env:"BANNER_BUFFER_LENGTH"`
 	// Cache expiration time. Defaults to 5 minutes
 	CacheExpiration time.Duration `yaml:"cache_expiration" env:"CACHE_EXPIRATION"`
+	// Number of revisions kept per banner. Defaults to 3
+	BannerRevisions int `yaml:"banner_revisions" env:"BANNER_REVISIONS"`
+	// Locales to look banner content up in when the requested one is missing,
+	// e.g. ["en", "ru"]. Defaults to none, the banner content is shown as is
+	LocaleFallback []string `yaml:"locale_fallback" env:"LOCALE_FALLBACK"`
+	// Number of impressions and clicks written to the database at once. Defaults to 100
+	EventBufferLength int `yaml:"event_buffer_length" env:"EVENT_BUFFER_LENGTH"`
+	// Soft-deleted banners are purged for good once deleted that long ago. Defaults to 30 days
+	PurgeRetention time.Duration `yaml:"purge_retention" env:"PURGE_RETENTION"`
+	// Interval between background purges, zero disables them. Defaults to 1 hour
+	PurgeInterval time.Duration `yaml:"purge_interval" env:"PURGE_INTERVAL"`
+	// Number of banners or orphaned tags purged at once. Defaults to 100
+	PurgeBatchSize int `yaml:"purge_batch_size" env:"PURGE_BATCH_SIZE"`
+	// Finished async deletion jobs are reported that long. Defaults to 24 hours
+	DeleteJobRetention time.Duration `yaml:"delete_job_retention" env:"DELETE_JOB_RETENTION"`
+	// Number of banners deleted by feature or tag in a single transaction. Defaults to 500
+	DeleteChunkSize int `yaml:"delete_chunk_size" env:"DELETE_CHUNK_SIZE"`
+	// Interval between runs of the stored async delete jobs, also suggested
+	// to retry a rejected deletion after. Defaults to 10 seconds
+	DeleteFlushInterval time.Duration `yaml:"delete_flush_interval" env:"DELETE_FLUSH_INTERVAL"`
+	// Number of banner ids accepted in a single async deletion. Defaults to 1000
+	DeleteMaxBatchSize int `yaml:"delete_max_batch_size" env:"DELETE_MAX_BATCH_SIZE"`
+	// Number of unfinished async delete jobs over which new ones are rejected. Defaults to 100
+	DeleteQueueLimit int `yaml:"delete_queue_limit" env:"DELETE_QUEUE_LIMIT"`
+	// Size of the largest file accepted for import in bytes. Defaults to 32 MiB
+	ImportMaxBytes int64 `yaml:"import_max_bytes" env:"IMPORT_MAX_BYTES"`
+	// Number of rows accepted in a single imported file. Defaults to 10000
+	ImportMaxRows int `yaml:"import_max_rows" env:"IMPORT_MAX_ROWS"`
+	// Allow manifests to be applied straight to the live banners, bypassing
+	// the review of drafts. Defaults to false
+	ManifestApply bool `yaml:"manifest_apply" env:"MANIFEST_APPLY"`
 }
 
 // Validate validates the application configuration.
@@ -44,6 +84,18 @@ func (c Config) Validate() error {
 	return validation.ValidateStruct(&c,
 		validation.Field(&c.DSN, validation.Required),
 		validation.Field(&c.JWTSigningKey, validation.Required),
+		validation.Field(&c.BannerRevisions, validation.Min(1)),
+		validation.Field(&c.EventBufferLength, validation.Min(1)),
+		validation.Field(&c.PurgeRetention, validation.Min(time.Duration(0))),
+		validation.Field(&c.PurgeInterval, validation.Min(time.Duration(0))),
+		validation.Field(&c.PurgeBatchSize, validation.Min(1)),
+		validation.Field(&c.DeleteJobRetention, validation.Min(time.Duration(0))),
+		validation.Field(&c.DeleteChunkSize, validation.Min(1)),
+		validation.Field(&c.DeleteFlushInterval, validation.Min(time.Second)),
+		validation.Field(&c.DeleteMaxBatchSize, validation.Min(1)),
+		validation.Field(&c.DeleteQueueLimit, validation.Min(1)),
+		validation.Field(&c.ImportMaxBytes, validation.Min(int64(1))),
+		validation.Field(&c.ImportMaxRows, validation.Min(1)),
 	)
 }
 
@@ -52,12 +104,23 @@ func (c Config) Validate() error {
 func Load(file string, logger log.Logger) (*Config, error) {
 	// default config
 	c := Config{
-		ServerPort:         defaultServerPort,
-		JWTExpiration:      defaultJWTExpiration,
-		ShutdownTimeout:    defaultShutdownTimeout,
-		CacheExpiration:    defaultCacheExpiration,
-		LiveMode:           false,
-		BannerBufferLength: defaultBannerBufferLength,
+		ServerPort:          defaultServerPort,
+		JWTExpiration:       defaultJWTExpiration,
+		ShutdownTimeout:     defaultShutdownTimeout,
+		CacheExpiration:     defaultCacheExpiration,
+		LiveMode:            false,
+		BannerRevisions:     defaultBannerRevisions,
+		EventBufferLength:   defaultEventBufferLength,
+		PurgeRetention:      defaultPurgeRetention,
+		PurgeInterval:       defaultPurgeInterval,
+		PurgeBatchSize:      defaultPurgeBatchSize,
+		DeleteJobRetention:  defaultDeleteJobRetention,
+		DeleteChunkSize:     defaultDeleteChunkSize,
+		DeleteFlushInterval: defaultDeleteFlushInterval,
+		DeleteMaxBatchSize:  defaultDeleteMaxBatchSize,
+		DeleteQueueLimit:    defaultDeleteQueueLimit,
+		ImportMaxBytes:      defaultImportMaxBytes,
+		ImportMaxRows:       defaultImportMaxRows,
 	}
 
 	// load from YAML config file
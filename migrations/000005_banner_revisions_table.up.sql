@@ -0,0 +1,33 @@
+CREATE TABLE banner_revisions (
+    id SERIAL PRIMARY KEY,
+    banner_id INTEGER NOT NULL,
+    version INTEGER NOT NULL,
+    feature_id INTEGER NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    text TEXT NOT NULL,
+    url TEXT NOT NULL,
+    is_active BOOLEAN NOT NULL,
+    tag_ids JSONB DEFAULT '[]' NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE UNIQUE INDEX banner_revisions_banner_id_version_idx ON banner_revisions (banner_id, version);
+
+INSERT INTO banner_revisions (banner_id, version, feature_id, title, text, url, is_active, tag_ids)
+SELECT
+    b.id,
+    1,
+    b.feature_id,
+    b.title,
+    b.text,
+    b.url,
+    b.is_active,
+    COALESCE((
+        SELECT
+            jsonb_agg(t.tag_id ORDER BY t.id)
+        FROM tags t
+        WHERE
+            t.banner_id = b.id
+            AND t.tag_id <> -1), '[]')
+FROM
+    banners b;
@@ -0,0 +1,29 @@
+ALTER TABLE banners
+    ADD COLUMN title VARCHAR(255) DEFAULT '' NOT NULL,
+    ADD COLUMN text TEXT DEFAULT '' NOT NULL,
+    ADD COLUMN url TEXT DEFAULT '' NOT NULL;
+
+UPDATE
+    banners
+SET
+    title = COALESCE(LEFT(content ->> 'title', 255), ''),
+    text = COALESCE(content ->> 'text', ''),
+    url = COALESCE(content ->> 'url', '');
+
+ALTER TABLE banners
+    DROP COLUMN content;
+
+ALTER TABLE banner_revisions
+    ADD COLUMN title VARCHAR(255) DEFAULT '' NOT NULL,
+    ADD COLUMN text TEXT DEFAULT '' NOT NULL,
+    ADD COLUMN url TEXT DEFAULT '' NOT NULL;
+
+UPDATE
+    banner_revisions
+SET
+    title = COALESCE(LEFT(content ->> 'title', 255), ''),
+    text = COALESCE(content ->> 'text', ''),
+    url = COALESCE(content ->> 'url', '');
+
+ALTER TABLE banner_revisions
+    DROP COLUMN content;
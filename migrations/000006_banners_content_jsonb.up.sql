@@ -0,0 +1,25 @@
+ALTER TABLE banners
+    ADD COLUMN content JSONB DEFAULT '{}' NOT NULL;
+
+UPDATE
+    banners
+SET
+    content = jsonb_build_object('title', title, 'text', text, 'url', url);
+
+ALTER TABLE banners
+    DROP COLUMN title,
+    DROP COLUMN text,
+    DROP COLUMN url;
+
+ALTER TABLE banner_revisions
+    ADD COLUMN content JSONB DEFAULT '{}' NOT NULL;
+
+UPDATE
+    banner_revisions
+SET
+    content = jsonb_build_object('title', title, 'text', text, 'url', url);
+
+ALTER TABLE banner_revisions
+    DROP COLUMN title,
+    DROP COLUMN text,
+    DROP COLUMN url;
@@ -0,0 +1,6 @@
+CREATE TABLE feature_schemas (
+    feature_id INTEGER PRIMARY KEY,
+    schema JSONB NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
@@ -0,0 +1,13 @@
+DROP INDEX tags_live_feature_id_tag_id_idx;
+
+DROP TRIGGER banners_sync_tags ON banners;
+
+DROP FUNCTION banners_sync_tags;
+
+DROP TRIGGER tags_sync_banner ON tags;
+
+DROP FUNCTION tags_sync_banner;
+
+ALTER TABLE tags
+    DROP COLUMN feature_id,
+    DROP COLUMN is_live;
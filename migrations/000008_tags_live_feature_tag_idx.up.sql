@@ -0,0 +1,117 @@
+-- Tags carry the feature and liveness of their banner so that
+-- a single active banner per (feature, tag) can be enforced by an index.
+-- Live banners conflicting over a (feature, tag) are resolved first:
+-- the most recently updated one stays active and the rest are deactivated,
+-- which the down migration does not undo.
+DO $$
+DECLARE
+    banner RECORD;
+BEGIN
+    CREATE TEMPORARY TABLE live_slots (
+        feature_id INTEGER,
+        tag_id INTEGER,
+        PRIMARY KEY (feature_id, tag_id)
+    ) ON COMMIT DROP;
+    FOR banner IN
+    SELECT
+        b.id,
+        b.feature_id
+    FROM
+        banners b
+    WHERE
+        b.is_active
+        AND NOT b.is_deleted
+    ORDER BY
+        b.updated_at DESC,
+        b.id DESC LOOP
+            IF EXISTS (
+                SELECT
+                    1
+                FROM
+                    tags t
+                    JOIN live_slots s ON s.feature_id = banner.feature_id
+                        AND s.tag_id = t.tag_id
+                WHERE
+                    t.banner_id = banner.id) THEN
+            UPDATE
+                banners
+            SET
+                is_active = FALSE
+            WHERE
+                id = banner.id;
+        ELSE
+            INSERT INTO live_slots (feature_id, tag_id)
+            SELECT DISTINCT
+                banner.feature_id,
+                t.tag_id
+            FROM
+                tags t
+            WHERE
+                t.banner_id = banner.id
+                AND t.tag_id <> -1;
+        END IF;
+    END LOOP;
+END;
+$$;
+
+ALTER TABLE tags
+    ADD COLUMN feature_id INTEGER,
+    ADD COLUMN is_live BOOLEAN DEFAULT FALSE NOT NULL;
+
+UPDATE
+    tags t
+SET
+    feature_id = b.feature_id,
+    is_live = b.is_active AND NOT b.is_deleted
+FROM
+    banners b
+WHERE
+    b.id = t.banner_id;
+
+CREATE FUNCTION tags_sync_banner()
+    RETURNS TRIGGER
+    AS $$
+BEGIN
+    SELECT
+        b.feature_id,
+        b.is_active AND NOT b.is_deleted INTO NEW.feature_id,
+        NEW.is_live
+    FROM
+        banners b
+    WHERE
+        b.id = NEW.banner_id;
+    RETURN NEW;
+END;
+$$
+LANGUAGE plpgsql;
+
+CREATE TRIGGER tags_sync_banner
+    BEFORE INSERT OR UPDATE OF tag_id, banner_id ON tags
+    FOR EACH ROW
+    EXECUTE FUNCTION tags_sync_banner();
+
+CREATE FUNCTION banners_sync_tags()
+    RETURNS TRIGGER
+    AS $$
+BEGIN
+    UPDATE
+        tags
+    SET
+        feature_id = NEW.feature_id,
+        is_live = NEW.is_active AND NOT NEW.is_deleted
+    WHERE
+        banner_id = NEW.id;
+    RETURN NULL;
+END;
+$$
+LANGUAGE plpgsql;
+
+CREATE TRIGGER banners_sync_tags
+    AFTER UPDATE OF feature_id, is_active, is_deleted ON banners
+    FOR EACH ROW
+    EXECUTE FUNCTION banners_sync_tags();
+
+-- -1 is a mock deletion tag, it never identifies a real slot
+CREATE UNIQUE INDEX tags_live_feature_id_tag_id_idx ON tags (feature_id, tag_id)
+WHERE
+    is_live AND tag_id <> -1;
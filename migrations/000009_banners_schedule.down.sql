@@ -0,0 +1,59 @@
+ALTER TABLE tags
+    DROP CONSTRAINT tags_live_slot_excl;
+
+CREATE UNIQUE INDEX tags_live_feature_id_tag_id_idx ON tags (feature_id, tag_id)
+WHERE
+    is_live AND tag_id <> -1;
+
+DROP TRIGGER banners_sync_tags ON banners;
+
+CREATE TRIGGER banners_sync_tags
+    AFTER UPDATE OF feature_id, is_active, is_deleted ON banners
+    FOR EACH ROW
+    EXECUTE FUNCTION banners_sync_tags();
+
+CREATE OR REPLACE FUNCTION banners_sync_tags()
+    RETURNS TRIGGER
+    AS $$
+BEGIN
+    UPDATE
+        tags
+    SET
+        feature_id = NEW.feature_id,
+        is_live = NEW.is_active AND NOT NEW.is_deleted
+    WHERE
+        banner_id = NEW.id;
+    RETURN NULL;
+END;
+$$
+LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION tags_sync_banner()
+    RETURNS TRIGGER
+    AS $$
+BEGIN
+    SELECT
+        b.feature_id,
+        b.is_active AND NOT b.is_deleted INTO NEW.feature_id,
+        NEW.is_live
+    FROM
+        banners b
+    WHERE
+        b.id = NEW.banner_id;
+    RETURN NEW;
+END;
+$$
+LANGUAGE plpgsql;
+
+ALTER TABLE tags
+    DROP COLUMN active_from,
+    DROP COLUMN active_until;
+
+ALTER TABLE banner_revisions
+    DROP COLUMN active_from,
+    DROP COLUMN active_until;
+
+ALTER TABLE banners
+    DROP CONSTRAINT banners_schedule_check,
+    DROP COLUMN active_from,
+    DROP COLUMN active_until;
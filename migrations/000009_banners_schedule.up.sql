@@ -0,0 +1,68 @@
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+ALTER TABLE banners
+    ADD COLUMN active_from TIMESTAMPTZ,
+    ADD COLUMN active_until TIMESTAMPTZ,
+    ADD CONSTRAINT banners_schedule_check CHECK (active_from < active_until);
+
+ALTER TABLE banner_revisions
+    ADD COLUMN active_from TIMESTAMPTZ,
+    ADD COLUMN active_until TIMESTAMPTZ;
+
+-- Banners scheduled for non-overlapping periods may share a feature and tag.
+ALTER TABLE tags
+    ADD COLUMN active_from TIMESTAMPTZ,
+    ADD COLUMN active_until TIMESTAMPTZ;
+
+CREATE OR REPLACE FUNCTION tags_sync_banner()
+    RETURNS TRIGGER
+    AS $$
+BEGIN
+    SELECT
+        b.feature_id,
+        b.is_active AND NOT b.is_deleted,
+        b.active_from,
+        b.active_until INTO NEW.feature_id,
+        NEW.is_live,
+        NEW.active_from,
+        NEW.active_until
+    FROM
+        banners b
+    WHERE
+        b.id = NEW.banner_id;
+    RETURN NEW;
+END;
+$$
+LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION banners_sync_tags()
+    RETURNS TRIGGER
+    AS $$
+BEGIN
+    UPDATE
+        tags
+    SET
+        feature_id = NEW.feature_id,
+        is_live = NEW.is_active AND NOT NEW.is_deleted,
+        active_from = NEW.active_from,
+        active_until = NEW.active_until
+    WHERE
+        banner_id = NEW.id;
+    RETURN NULL;
+END;
+$$
+LANGUAGE plpgsql;
+
+DROP TRIGGER banners_sync_tags ON banners;
+
+CREATE TRIGGER banners_sync_tags
+    AFTER UPDATE OF feature_id, is_active, is_deleted, active_from, active_until ON banners
+    FOR EACH ROW
+    EXECUTE FUNCTION banners_sync_tags();
+
+DROP INDEX tags_live_feature_id_tag_id_idx;
+
+ALTER TABLE tags
+    ADD CONSTRAINT tags_live_slot_excl
+    EXCLUDE USING gist (feature_id WITH =, tag_id WITH =, tstzrange(active_from, active_until) WITH &&)
+    WHERE (is_live AND tag_id <> -1);
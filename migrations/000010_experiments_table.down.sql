@@ -0,0 +1,3 @@
+DROP TABLE experiment_variants;
+
+DROP TABLE experiments;
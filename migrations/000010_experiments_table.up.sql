@@ -0,0 +1,21 @@
+CREATE TABLE experiments (
+    id SERIAL PRIMARY KEY,
+    feature_id INTEGER NOT NULL,
+    tag_id INTEGER NOT NULL,
+    winner_id INTEGER,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    ended_at TIMESTAMP
+);
+
+-- at most one running experiment per feature and tag
+CREATE UNIQUE INDEX experiments_running_feature_id_tag_id_idx ON experiments (feature_id, tag_id)
+WHERE
+    ended_at IS NULL;
+
+CREATE TABLE experiment_variants (
+    experiment_id INTEGER NOT NULL REFERENCES experiments (id),
+    banner_id INTEGER NOT NULL,
+    weight INTEGER NOT NULL CHECK (weight > 0),
+    PRIMARY KEY (experiment_id, banner_id)
+);
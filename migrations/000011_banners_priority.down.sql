@@ -0,0 +1,5 @@
+ALTER TABLE banner_revisions
+    DROP COLUMN priority;
+
+ALTER TABLE banners
+    DROP COLUMN priority;
@@ -0,0 +1,7 @@
+-- Users carry several tags, so more than one banner of a feature may match.
+-- The one with the highest priority is shown.
+ALTER TABLE banners
+    ADD COLUMN priority INTEGER DEFAULT 0 NOT NULL;
+
+ALTER TABLE banner_revisions
+    ADD COLUMN priority INTEGER DEFAULT 0 NOT NULL;
@@ -0,0 +1,7 @@
+-- the banner shown for the feature when none matches the user tags
+CREATE TABLE feature_fallbacks (
+    feature_id INTEGER PRIMARY KEY,
+    banner_id INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
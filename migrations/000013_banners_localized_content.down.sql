@@ -0,0 +1,5 @@
+ALTER TABLE banner_revisions
+    DROP COLUMN localized_content;
+
+ALTER TABLE banners
+    DROP COLUMN localized_content;
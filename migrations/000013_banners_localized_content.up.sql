@@ -0,0 +1,6 @@
+-- content per locale, the plain content is shown when no locale matches
+ALTER TABLE banners
+    ADD COLUMN localized_content JSONB DEFAULT '{}' NOT NULL;
+
+ALTER TABLE banner_revisions
+    ADD COLUMN localized_content JSONB DEFAULT '{}' NOT NULL;
@@ -0,0 +1,18 @@
+-- a new banner or a change of an existing one waiting for approval
+-- of a second admin, banner_id is NULL for a banner not created yet
+CREATE TABLE banner_drafts (
+    id SERIAL PRIMARY KEY,
+    banner_id INTEGER,
+    payload JSONB NOT NULL,
+    status VARCHAR(16) DEFAULT 'draft' NOT NULL,
+    created_by INTEGER NOT NULL,
+    submitted_by INTEGER,
+    submitted_at TIMESTAMP,
+    reviewed_by INTEGER,
+    reviewed_at TIMESTAMP,
+    review_comment TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX banner_drafts_status_idx ON banner_drafts (status);
@@ -0,0 +1,14 @@
+-- impressions and clicks of banners, feature_id is the one
+-- the banner belonged to at the moment of the event
+CREATE TABLE banner_events (
+    id BIGSERIAL PRIMARY KEY,
+    banner_id INTEGER NOT NULL,
+    feature_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    kind VARCHAR(16) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX banner_events_banner_id_created_at_idx ON banner_events (banner_id, created_at);
+
+CREATE INDEX banner_events_feature_id_created_at_idx ON banner_events (feature_id, created_at);
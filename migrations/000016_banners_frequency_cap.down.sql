@@ -0,0 +1,5 @@
+ALTER TABLE banner_revisions
+    DROP COLUMN frequency_cap;
+
+ALTER TABLE banners
+    DROP COLUMN frequency_cap;
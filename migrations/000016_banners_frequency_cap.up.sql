@@ -0,0 +1,6 @@
+-- at most that many impressions per user per day, NULL for no cap
+ALTER TABLE banners
+    ADD COLUMN frequency_cap INTEGER CHECK (frequency_cap > 0);
+
+ALTER TABLE banner_revisions
+    ADD COLUMN frequency_cap INTEGER;
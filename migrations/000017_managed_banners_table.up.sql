@@ -0,0 +1,7 @@
+-- banners kept in a manifest under a stable external id
+CREATE TABLE managed_banners (
+    external_id VARCHAR(255) PRIMARY KEY,
+    banner_id INTEGER NOT NULL UNIQUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
@@ -0,0 +1,4 @@
+DROP INDEX banners_deleted_at_idx;
+
+ALTER TABLE banners
+    DROP COLUMN deleted_at;
@@ -0,0 +1,15 @@
+-- the moment of the soft deletion, the retention of deleted banners counts from it
+ALTER TABLE banners
+    ADD COLUMN deleted_at TIMESTAMP;
+
+-- the real moment is unknown for banners deleted before
+UPDATE
+    banners
+SET
+    deleted_at = updated_at
+WHERE
+    is_deleted = TRUE;
+
+CREATE INDEX banners_deleted_at_idx ON banners (deleted_at)
+WHERE
+    is_deleted = TRUE;
@@ -0,0 +1,3 @@
+DROP TABLE tag_definitions;
+
+DROP TABLE features;
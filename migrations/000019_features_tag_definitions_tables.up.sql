@@ -0,0 +1,63 @@
+-- catalogs of the features and tags banners are placed in,
+-- ids are the ones clients already pass around, so they are not generated
+CREATE TABLE features (
+    id INTEGER PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    description TEXT DEFAULT '' NOT NULL,
+    is_archived BOOLEAN DEFAULT FALSE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+-- -1 is a mock deletion tag, it is never registered
+CREATE TABLE tag_definitions (
+    id INTEGER PRIMARY KEY CHECK (id <> -1),
+    name VARCHAR(255) NOT NULL UNIQUE,
+    description TEXT DEFAULT '' NOT NULL,
+    is_archived BOOLEAN DEFAULT FALSE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+-- register the ids in use under placeholder names to be renamed by admins
+INSERT INTO features (id, name)
+SELECT
+    id,
+    'feature ' || id
+FROM (
+    SELECT
+        feature_id
+    FROM
+        banners
+    UNION
+    SELECT
+        feature_id
+    FROM
+        feature_schemas
+    UNION
+    SELECT
+        feature_id
+    FROM
+        feature_fallbacks
+    UNION
+    SELECT
+        feature_id
+    FROM
+        experiments) AS used (id);
+
+INSERT INTO tag_definitions (id, name)
+SELECT
+    id,
+    'tag ' || id
+FROM (
+    SELECT
+        tag_id
+    FROM
+        tags
+    WHERE
+        tag_id <> -1
+    UNION
+    SELECT
+        tag_id
+    FROM
+        experiments) AS used (id);
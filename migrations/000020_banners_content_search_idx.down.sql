@@ -0,0 +1 @@
+DROP INDEX banners_content_search_idx;
@@ -0,0 +1,3 @@
+-- full-text search over every string of the content in any locale,
+-- the simple configuration keeps words of all the languages as is
+CREATE INDEX banners_content_search_idx ON banners USING GIN ((jsonb_to_tsvector('simple', content, '["string"]') || jsonb_to_tsvector('simple', localized_content, '["string"]')));
@@ -0,0 +1,9 @@
+DROP INDEX tags_tag_id_banner_id_idx;
+
+DROP INDEX tags_banner_id_idx;
+
+DROP INDEX banners_updated_at_id_idx;
+
+DROP INDEX banners_created_at_id_idx;
+
+DROP INDEX banners_feature_id_id_idx;
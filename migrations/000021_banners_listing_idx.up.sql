@@ -0,0 +1,11 @@
+-- the admin listing filters by feature and tag, aggregates the tags
+-- of every banner and pages through the sort orders with a cursor
+CREATE INDEX banners_feature_id_id_idx ON banners (feature_id, id);
+
+CREATE INDEX banners_created_at_id_idx ON banners (created_at, id);
+
+CREATE INDEX banners_updated_at_id_idx ON banners (updated_at, id);
+
+CREATE INDEX tags_banner_id_idx ON tags (banner_id);
+
+CREATE INDEX tags_tag_id_banner_id_idx ON tags (tag_id, banner_id);
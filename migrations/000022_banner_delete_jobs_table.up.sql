@@ -0,0 +1,21 @@
+-- outbox of async banner deletions, a job is stored before it is
+-- acknowledged and claimed by a worker of any replica for a lease
+CREATE TABLE banner_delete_jobs (
+    id UUID PRIMARY KEY,
+    status VARCHAR(16) DEFAULT 'queued' NOT NULL,
+    banner_ids JSONB NOT NULL,
+    results JSONB DEFAULT '[]' NOT NULL,
+    claim_id UUID,
+    claimed_until TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    started_at TIMESTAMP,
+    finished_at TIMESTAMP
+);
+
+CREATE INDEX banner_delete_jobs_unfinished_idx ON banner_delete_jobs (created_at)
+WHERE
+    status IN ('queued', 'running');
+
+CREATE INDEX banner_delete_jobs_finished_at_idx ON banner_delete_jobs (finished_at)
+WHERE
+    finished_at IS NOT NULL;
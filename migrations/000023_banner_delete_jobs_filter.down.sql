@@ -0,0 +1,4 @@
+ALTER TABLE banner_delete_jobs
+    DROP COLUMN error,
+    DROP COLUMN tag_id,
+    DROP COLUMN feature_id;
@@ -0,0 +1,6 @@
+-- a job may delete every live banner of the feature and/or the tag,
+-- its banner_ids then grow chunk by chunk as the banners are deleted
+ALTER TABLE banner_delete_jobs
+    ADD COLUMN feature_id INTEGER,
+    ADD COLUMN tag_id INTEGER,
+    ADD COLUMN error TEXT;
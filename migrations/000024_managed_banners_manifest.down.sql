@@ -0,0 +1,7 @@
+-- fails if several manifests use the same external id
+ALTER TABLE managed_banners
+    DROP CONSTRAINT managed_banners_pkey,
+    ADD PRIMARY KEY (external_id);
+
+ALTER TABLE managed_banners
+    DROP COLUMN manifest;
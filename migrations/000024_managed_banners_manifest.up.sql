@@ -0,0 +1,12 @@
+-- a managed banner belongs to the named manifest that created it, the same
+-- external id may be used by several manifests; banners applied before
+-- manifests were named belong to the manifest named default
+ALTER TABLE managed_banners
+    ADD COLUMN manifest VARCHAR(255) DEFAULT 'default' NOT NULL;
+
+ALTER TABLE managed_banners
+    ALTER COLUMN manifest DROP DEFAULT;
+
+ALTER TABLE managed_banners
+    DROP CONSTRAINT managed_banners_pkey,
+    ADD PRIMARY KEY (manifest, external_id);
@@ -0,0 +1,5 @@
+ALTER TABLE experiments
+    DROP CONSTRAINT experiments_winner_id_fkey;
+
+ALTER TABLE experiment_variants
+    DROP CONSTRAINT experiment_variants_banner_id_fkey;
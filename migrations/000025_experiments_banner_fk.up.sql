@@ -0,0 +1,30 @@
+-- variants and winners left behind by banners purged before the purge
+-- pruned them are dropped, so that the constraints can be validated
+DELETE FROM experiment_variants v
+WHERE NOT EXISTS (
+        SELECT
+            1
+        FROM
+            banners b
+        WHERE
+            b.id = v.banner_id);
+
+UPDATE
+    experiments e
+SET
+    winner_id = NULL
+WHERE
+    e.winner_id IS NOT NULL
+    AND NOT EXISTS (
+        SELECT
+            1
+        FROM
+            banners b
+        WHERE
+            b.id = e.winner_id);
+
+ALTER TABLE experiment_variants
+    ADD CONSTRAINT experiment_variants_banner_id_fkey FOREIGN KEY (banner_id) REFERENCES banners (id);
+
+ALTER TABLE experiments
+    ADD CONSTRAINT experiments_winner_id_fkey FOREIGN KEY (winner_id) REFERENCES banners (id);
@@ -1,14 +1,32 @@
-INSERT INTO banners (feature_id, title, text, url, is_active, is_deleted)
-    VALUES (1, 'some_title', 'some_text', 'some_url', TRUE, FALSE),
-    (2, 'some_title', 'some_text', 'some_url', TRUE, FALSE),
-    (3, 'some_title', 'some_text', 'some_url', TRUE, FALSE),
-    (4, 'some_title', 'some_text', 'some_url', TRUE, FALSE),
-    (5, 'some_title', 'some_text', 'some_url', TRUE, FALSE),
-    (1, 'some_title', 'some_text', 'some_url', FALSE, TRUE),
-    (2, 'some_title', 'some_text', 'some_url', FALSE, TRUE),
-    (3, 'some_title', 'some_text', 'some_url', FALSE, TRUE),
-    (4, 'some_title', 'some_text', 'some_url', FALSE, TRUE),
-    (5, 'some_title', 'some_text', 'some_url', FALSE, TRUE)
+INSERT INTO features (id, name, description)
+    VALUES (1, 'main_page', 'Banner on the main page'),
+    (2, 'search', 'Banner above search results'),
+    (3, 'cart', 'Banner in the cart'),
+    (4, 'profile', 'Banner in the user profile'),
+    (5, 'checkout', 'Banner on the checkout page')
+ON CONFLICT
+    DO NOTHING;
+
+INSERT INTO tag_definitions (id, name, description)
+    VALUES (1, 'new_users', 'Registered less than a month ago'),
+    (2, 'returning_users', 'Came back after a month or more'),
+    (3, 'premium', 'Paid subscription holders'),
+    (4, 'mobile', 'Mobile app users'),
+    (5, 'sellers', 'Users with active listings')
+ON CONFLICT
+    DO NOTHING;
+
+INSERT INTO banners (feature_id, content, is_active, is_deleted)
+    VALUES (1, '{"title": "some_title", "text": "some_text", "url": "some_url"}', TRUE, FALSE),
+    (2, '{"title": "some_title", "text": "some_text", "url": "some_url"}', TRUE, FALSE),
+    (3, '{"title": "some_title", "text": "some_text", "url": "some_url"}', TRUE, FALSE),
+    (4, '{"title": "some_title", "text": "some_text", "url": "some_url"}', TRUE, FALSE),
+    (5, '{"title": "some_title", "text": "some_text", "url": "some_url"}', TRUE, FALSE),
+    (1, '{"title": "some_title", "text": "some_text", "url": "some_url"}', FALSE, TRUE),
+    (2, '{"title": "some_title", "text": "some_text", "url": "some_url"}', FALSE, TRUE),
+    (3, '{"title": "some_title", "text": "some_text", "url": "some_url"}', FALSE, TRUE),
+    (4, '{"title": "some_title", "text": "some_text", "url": "some_url"}', FALSE, TRUE),
+    (5, '{"title": "some_title", "text": "some_text", "url": "some_url"}', FALSE, TRUE)
 ON CONFLICT
     DO NOTHING;
 
@@ -38,3 +56,20 @@ INSERT INTO tags (tag_id, banner_id)
 ON CONFLICT
     DO NOTHING;
 
+INSERT INTO banner_revisions (banner_id, version, feature_id, content, is_active, tag_ids)
+SELECT
+    b.id,
+    1,
+    b.feature_id,
+    b.content,
+    b.is_active,
+    COALESCE((
+        SELECT
+            jsonb_agg(t.tag_id ORDER BY t.id)
+        FROM tags t
+        WHERE
+            t.banner_id = b.id), '[]')
+FROM
+    banners b
+ON CONFLICT
+    DO NOTHING;